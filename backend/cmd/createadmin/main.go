@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"mini-banking-platform/internal/config"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/repository"
+	"mini-banking-platform/internal/service"
+	"mini-banking-platform/pkg/logger"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+// createadmin bootstraps an admin user. It only ever creates a new user, so
+// an email somebody has already registered is refused instead of promoted.
+// The password is read from ADMIN_PASSWORD to keep it out of the process list.
+func main() {
+	email := flag.String("email", "", "email of the admin to create")
+	firstName := flag.String("first-name", "Platform", "first name of the admin")
+	lastName := flag.String("last-name", "Admin", "last name of the admin")
+	flag.Parse()
+
+	password := os.Getenv("ADMIN_PASSWORD")
+	if *email == "" || password == "" {
+		fmt.Fprintln(os.Stderr, "usage: ADMIN_PASSWORD=... createadmin -email admin@example.org")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg := config.LoadDatabase()
+	db, err := sqlx.Connect("postgres", cfg.DatabaseURL())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	appLogger := logger.New()
+	repos := repository.NewRepositories(db, appLogger)
+	authService := service.NewAuthService(repos.User, repos.Account, repos.Transaction, repos.Currency, nil, appLogger)
+
+	user, err := authService.RegisterAdmin(context.Background(), dto.RegisterRequest{
+		Email:     *email,
+		Password:  password,
+		FirstName: *firstName,
+		LastName:  *lastName,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create admin %s: %v", *email, err)
+	}
+
+	fmt.Printf("created admin %s (%s)\n", user.Email, user.ID)
+}
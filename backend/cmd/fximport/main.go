@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"mini-banking-platform/internal/config"
+	"mini-banking-platform/internal/fximport"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/internal/repository"
+	"mini-banking-platform/internal/service"
+	"mini-banking-platform/pkg/logger"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	file := flag.String("file", "", "path to an ECB eurofxref XML or CSV file")
+	format := flag.String("format", "", "xml or csv (default: from the file extension)")
+	source := flag.String("source", "ecb", "source recorded on imported rates")
+	maxGapDays := flag.Int("max-gap-days", 4, "report gaps longer than this many calendar days")
+	strict := flag.Bool("strict", false, "fail on gaps instead of only reporting them")
+	dryRun := flag.Bool("dry-run", false, "validate the file without writing to the database")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	rates, err := parseFile(*file, *format)
+	if err != nil {
+		log.Fatalf("Failed to parse %s: %v", *file, err)
+	}
+
+	report := fximport.Validate(rates, *maxGapDays)
+	fmt.Printf("parsed %d rates, %d exact duplicates dropped\n", len(rates), report.Duplicates)
+	for _, gap := range report.Gaps {
+		fmt.Printf("gap: %s/%s has no rate between %s and %s (%d days)\n",
+			gap.Base, gap.Quote, gap.From.Format("2006-01-02"), gap.To.Format("2006-01-02"), gap.Days)
+	}
+	for _, conflict := range report.Conflicts {
+		fmt.Printf("conflict: %s\n", conflict)
+	}
+
+	if len(report.Conflicts) > 0 {
+		log.Fatalf("Found %d conflicting duplicate rates, nothing imported", len(report.Conflicts))
+	}
+	if *strict && len(report.Gaps) > 0 {
+		log.Fatalf("Found %d gaps in strict mode, nothing imported", len(report.Gaps))
+	}
+	if *dryRun {
+		fmt.Println("dry run, nothing imported")
+		return
+	}
+
+	cfg := config.LoadDatabase()
+	db, err := sqlx.Connect("postgres", cfg.DatabaseURL())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	appLogger := logger.New()
+	repos := repository.NewRepositories(db, appLogger)
+	fxRateService := service.NewFXRateService(repos.FXRate, repos.FXSpread, repos.Rounding, repos.Currency, repos.Transaction, appLogger)
+
+	result, err := fxRateService.ImportRates(context.Background(), toModels(report.Rates, *source))
+	if err != nil {
+		log.Fatalf("Failed to import rates: %v", err)
+	}
+
+	fmt.Printf("imported %d rates, %d already present\n", result.Inserted, result.Existing)
+	for code, count := range result.UnknownCurrency {
+		fmt.Printf("skipped %d rates for %s (not in the currency registry)\n", count, code)
+	}
+}
+
+func parseFile(path, format string) ([]fximport.Rate, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+
+	switch format {
+	case "xml":
+		return fximport.ParseECBXML(f)
+	case "csv":
+		return fximport.ParseCSV(f)
+	}
+	return nil, fmt.Errorf("unknown format %q, use -format xml or csv", format)
+}
+
+// toModels makes each reference rate effective from its publication time,
+// 16:00 CET on its date, so it is never visible before it was published.
+func toModels(rates []fximport.Rate, source string) []models.FXRate {
+	result := make([]models.FXRate, 0, len(rates))
+	for _, rate := range rates {
+		result = append(result, models.FXRate{
+			BaseCurrency:  rate.Base,
+			QuoteCurrency: rate.Quote,
+			RateNum:       rate.Num,
+			RateDenom:     rate.Denom,
+			ValidFrom:     fximport.PublishedAt(rate.Date),
+			Source:        source,
+		})
+	}
+	return result
+}
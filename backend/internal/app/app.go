@@ -27,6 +27,11 @@ type App struct {
 	cfg        *config.Config
 	httpServer *http.Server
 	db         *sqlx.DB
+	fxMatcher  *service.FXOrderMatcher
+	interest   *service.InterestScheduler
+	holds      *service.HoldExpirer
+	scheduled  *service.ScheduledTransferRunner
+	standing   *service.StandingOrderRunner
 	logger     *slog.Logger
 }
 
@@ -50,17 +55,33 @@ func NewApp() (*App, error) {
 	jwtService := jwt.NewService(cfg.JWTSecret, cfg.JWTExpiryHours)
 
 	repos := repository.NewRepositories(db, log)
-	authService := service.NewAuthService(repos.User, repos.Account, repos.Transaction, jwtService, log)
-	accountService := service.NewAccountService(repos.Account, repos.Transaction, log)
-	transactionService := service.NewTransactionService(repos.Account, repos.Transaction, repos.User, log)
+	authService := service.NewAuthService(repos.User, repos.Account, repos.Transaction, repos.Currency, jwtService, log)
+	accountService := service.NewAccountService(repos.Account, repos.Transaction, repos.Currency, log)
+	currencyService := service.NewCurrencyService(repos.Currency, log)
+	statementService := service.NewStatementService(repos.Statement, repos.Account, log)
+	fxRateService := service.NewFXRateService(repos.FXRate, repos.FXSpread, repos.Rounding, repos.Currency, repos.Transaction, log)
+	interestService := service.NewInterestService(repos.Interest, repos.Account, repos.Transaction, cfg.DayCount(), log)
+	transactionService := service.NewTransactionService(repos.Account, repos.Transaction, repos.User, repos.Currency, repos.FXQuote, repos.FXSpread, repos.Rounding, repos.Scheduled, repos.Standing, fxRateService, interestService, cfg.RoundingMode(), log)
+	fxOrderService := service.NewFXOrderService(repos.FXOrder, repos.Account, repos.Transaction, repos.Currency, transactionService, log)
+	fxMatcher := service.NewFXOrderMatcher(fxOrderService, cfg.FXOrderMatchInterval(), log)
+	fxRateService.OnRatePublished(fxMatcher.NotifyRatePublished)
+	interestScheduler := service.NewInterestScheduler(interestService, cfg.InterestJobInterval(), log)
+	holdService := service.NewHoldService(repos.Hold, repos.Account, repos.Transaction, log)
+	accountMemberService := service.NewAccountMemberService(repos.Account, repos.User, log)
+	idempotencyService := service.NewIdempotencyService(repos.Idempotency, repos.Transaction, transactionService, log)
+	holdExpirer := service.NewHoldExpirer(holdService, cfg.HoldExpiryInterval(), log)
+	scheduledTransferService := service.NewScheduledTransferService(repos.Scheduled, transactionService, log)
+	scheduledTransferRunner := service.NewScheduledTransferRunner(scheduledTransferService, cfg.ScheduledTransferInterval(), log)
+	standingOrderService := service.NewStandingOrderService(repos.Standing, repos.Transaction, transactionService, log)
+	standingOrderRunner := service.NewStandingOrderRunner(standingOrderService, cfg.StandingOrderInterval(), log)
 
 
 	if err := seedUsers(context.Background(), authService, cfg, log); err != nil {
 		log.Warn("failed to seed users (may already exist)", "error", err)
 	}
 
-	handler := handlers.NewHandler(authService, accountService, transactionService, cfg, jwtService, log)
-	router := routes.NewRouter(handler, jwtService, log)
+	handler := handlers.NewHandler(authService, accountService, transactionService, currencyService, fxRateService, fxOrderService, interestService, statementService, holdService, accountMemberService, idempotencyService, scheduledTransferService, standingOrderService, cfg, jwtService, log)
+	router := routes.NewRouter(handler, jwtService, authService, log)
 
 	httpServer := &http.Server{
 		Addr:         fmt.Sprintf(":%s", cfg.Port),
@@ -74,11 +95,22 @@ func NewApp() (*App, error) {
 		cfg:        cfg,
 		httpServer: httpServer,
 		db:         db,
+		fxMatcher:  fxMatcher,
+		interest:   interestScheduler,
+		holds:      holdExpirer,
+		scheduled:  scheduledTransferRunner,
+		standing:   standingOrderRunner,
 		logger:     log,
 	}, nil
 }
 
 func (a *App) Run() error {
+	a.fxMatcher.Start()
+	a.interest.Start()
+	a.holds.Start()
+	a.scheduled.Start()
+	a.standing.Start()
+
 	a.logger.Info("server starting", "port", a.cfg.Port)
 	err := a.httpServer.ListenAndServe()
 	if err != nil && errors.Is(err, http.ErrServerClosed) {
@@ -92,6 +124,21 @@ func (a *App) Shutdown(ctx context.Context) error {
 	if err := a.httpServer.Shutdown(ctx); err != nil {
 		return err
 	}
+	if err := a.fxMatcher.Stop(ctx); err != nil {
+		return err
+	}
+	if err := a.interest.Stop(ctx); err != nil {
+		return err
+	}
+	if err := a.holds.Stop(ctx); err != nil {
+		return err
+	}
+	if err := a.scheduled.Stop(ctx); err != nil {
+		return err
+	}
+	if err := a.standing.Stop(ctx); err != nil {
+		return err
+	}
 	return a.db.Close()
 }
 
@@ -154,7 +201,7 @@ func seedUsers(ctx context.Context, authService *service.AuthService, cfg *confi
 			Password:  u.password,
 			FirstName: u.firstName,
 			LastName:  u.lastName,
-		}, cfg.InitialBalanceUSDCents, cfg.InitialBalanceEURCents)
+		}, cfg.InitialBalancesCents())
 
 		if err != nil {
 			log.Debug("user seed skipped", "email", u.email, "reason", err.Error())
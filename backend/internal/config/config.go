@@ -4,6 +4,10 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
+
+	"mini-banking-platform/pkg/decimal"
+	"mini-banking-platform/pkg/interest"
 
 	"github.com/joho/godotenv"
 )
@@ -22,6 +26,20 @@ type Config struct {
 	InitialBalanceUSDCents int64
 	InitialBalanceEURCents int64
 
+	FXQuoteTTLSeconds int
+	FXRoundingMode    string
+
+	FXOrderMatchIntervalSeconds int
+
+	InterestDayCount           string
+	SavingsInterestRate        string
+	InterestJobIntervalMinutes int
+
+	HoldExpiryIntervalSeconds int
+
+	ScheduledTransferIntervalSeconds int
+	StandingOrderIntervalSeconds     int
+
 	DefaultPage  int
 	DefaultLimit int
 	MaxLimit     int
@@ -44,6 +62,20 @@ func Load() (*Config, error) {
 		InitialBalanceUSDCents: getEnvInt64("INITIAL_BALANCE_USD_CENTS", 100000),
 		InitialBalanceEURCents: getEnvInt64("INITIAL_BALANCE_EUR_CENTS", 50000),
 
+		FXQuoteTTLSeconds: getEnvInt("FX_QUOTE_TTL_SECONDS", 30),
+		FXRoundingMode:    getEnv("FX_ROUNDING_MODE", string(decimal.RoundFloor)),
+
+		FXOrderMatchIntervalSeconds: getEnvInt("FX_ORDER_MATCH_INTERVAL_SECONDS", 60),
+
+		InterestDayCount:           getEnv("INTEREST_DAY_COUNT", string(interest.Actual365)),
+		SavingsInterestRate:        getEnv("SAVINGS_INTEREST_RATE", "0.02"),
+		InterestJobIntervalMinutes: getEnvInt("INTEREST_JOB_INTERVAL_MINUTES", 60),
+
+		HoldExpiryIntervalSeconds: getEnvInt("HOLD_EXPIRY_INTERVAL_SECONDS", 60),
+
+		ScheduledTransferIntervalSeconds: getEnvInt("SCHEDULED_TRANSFER_INTERVAL_SECONDS", 60),
+		StandingOrderIntervalSeconds:     getEnvInt("STANDING_ORDER_INTERVAL_SECONDS", 60),
+
 		DefaultPage:  getEnvInt("DEFAULT_PAGE", 1),
 		DefaultLimit: getEnvInt("DEFAULT_LIMIT", 10),
 		MaxLimit:     getEnvInt("MAX_LIMIT", 100),
@@ -53,14 +85,99 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("JWT_SECRET must be at least 32 characters")
 	}
 
+	if _, err := decimal.ParseRoundingMode(config.FXRoundingMode); err != nil {
+		return nil, fmt.Errorf("FX_ROUNDING_MODE must be one of half_even, half_up, floor")
+	}
+
+	if config.FXOrderMatchIntervalSeconds < 1 {
+		return nil, fmt.Errorf("FX_ORDER_MATCH_INTERVAL_SECONDS must be positive")
+	}
+
+	if _, err := interest.ParseDayCount(config.InterestDayCount); err != nil {
+		return nil, fmt.Errorf("INTEREST_DAY_COUNT must be one of act/365, act/360, act/act")
+	}
+
+	if _, _, err := decimal.ParseRational(config.SavingsInterestRate); err != nil {
+		return nil, fmt.Errorf("SAVINGS_INTEREST_RATE must be a positive decimal number")
+	}
+
+	if config.InterestJobIntervalMinutes < 1 {
+		return nil, fmt.Errorf("INTEREST_JOB_INTERVAL_MINUTES must be positive")
+	}
+
+	if config.HoldExpiryIntervalSeconds < 1 {
+		return nil, fmt.Errorf("HOLD_EXPIRY_INTERVAL_SECONDS must be positive")
+	}
+
+	if config.ScheduledTransferIntervalSeconds < 1 {
+		return nil, fmt.Errorf("SCHEDULED_TRANSFER_INTERVAL_SECONDS must be positive")
+	}
+
+	if config.StandingOrderIntervalSeconds < 1 {
+		return nil, fmt.Errorf("STANDING_ORDER_INTERVAL_SECONDS must be positive")
+	}
+
 	return config, nil
 }
 
+// LoadDatabase reads only the database settings, for command-line tools that
+// do not serve HTTP.
+func LoadDatabase() *Config {
+	_ = godotenv.Load()
+
+	return &Config{
+		DBHost:     getEnv("DB_HOST", "localhost"),
+		DBPort:     getEnv("DB_PORT", "5432"),
+		DBUser:     getEnv("DB_USER", "postgres"),
+		DBPassword: getEnvRequired("DB_PASSWORD"),
+		DBName:     getEnv("DB_NAME", "banking_platform"),
+	}
+}
+
 func (c *Config) DatabaseURL() string {
 	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		c.DBHost, c.DBPort, c.DBUser, c.DBPassword, c.DBName)
 }
 
+func (c *Config) FXQuoteTTL() time.Duration {
+	return time.Duration(c.FXQuoteTTLSeconds) * time.Second
+}
+
+func (c *Config) FXOrderMatchInterval() time.Duration {
+	return time.Duration(c.FXOrderMatchIntervalSeconds) * time.Second
+}
+
+func (c *Config) InterestJobInterval() time.Duration {
+	return time.Duration(c.InterestJobIntervalMinutes) * time.Minute
+}
+
+func (c *Config) HoldExpiryInterval() time.Duration {
+	return time.Duration(c.HoldExpiryIntervalSeconds) * time.Second
+}
+
+func (c *Config) ScheduledTransferInterval() time.Duration {
+	return time.Duration(c.ScheduledTransferIntervalSeconds) * time.Second
+}
+
+func (c *Config) StandingOrderInterval() time.Duration {
+	return time.Duration(c.StandingOrderIntervalSeconds) * time.Second
+}
+
+func (c *Config) DayCount() interest.DayCount {
+	return interest.DayCount(c.InterestDayCount)
+}
+
+func (c *Config) RoundingMode() decimal.RoundingMode {
+	return decimal.RoundingMode(c.FXRoundingMode)
+}
+
+func (c *Config) InitialBalancesCents() map[string]int64 {
+	return map[string]int64{
+		"USD": c.InitialBalanceUSDCents,
+		"EUR": c.InitialBalanceEURCents,
+	}
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
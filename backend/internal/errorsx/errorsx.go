@@ -15,6 +15,26 @@ var (
 	ErrInvalidCurrency       = errors.New("invalid currency")
 	ErrCurrenciesMustDiffer  = errors.New("from and to currencies must be different")
 	ErrCannotTransferToSelf  = errors.New("cannot transfer to self")
+	ErrCurrencyDisabled      = errors.New("currency is not enabled")
+	ErrRateUnavailable       = errors.New("exchange rate not available")
+	ErrAccountExists         = errors.New("account in this currency already exists")
+	ErrQuoteNotFound         = errors.New("quote not found")
+	ErrQuoteExpired          = errors.New("quote has expired")
+	ErrQuoteUsed             = errors.New("quote has already been used")
+	ErrOrderNotFound         = errors.New("order not found")
+	ErrOrderNotOpen          = errors.New("order is no longer open")
+	ErrAccountFrozen         = errors.New("account is frozen")
+	ErrAccountClosed         = errors.New("account is closed")
+	ErrHoldNotFound          = errors.New("hold not found")
+	ErrHoldNotActive         = errors.New("hold is no longer active")
+	ErrAccountAccessDenied   = errors.New("insufficient permissions on this account")
+	ErrIdempotencyMismatch   = errors.New("idempotency key was already used with a different request")
+	ErrAlreadyReversed       = errors.New("transaction has already been fully reversed")
+	ErrScheduleNotFound      = errors.New("scheduled transfer not found")
+	ErrScheduleNotPending    = errors.New("scheduled transfer is no longer pending")
+	ErrStandingOrderNotFound = errors.New("standing order not found")
+	ErrStandingOrderState    = errors.New("standing order cannot be changed in its current state")
+	ErrStatementNotSettled   = errors.New("the month is still settling, the statement will be available shortly")
 )
 
 type PublicError struct {
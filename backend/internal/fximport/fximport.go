@@ -0,0 +1,252 @@
+package fximport
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	_ "time/tzdata"
+
+	"mini-banking-platform/pkg/decimal"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	ecbBase    = "EUR"
+
+	// ECB reference rates are published at around 16:00 CET on their date.
+	ecbPublishHour     = 16
+	ecbPublishLocation = "Europe/Berlin"
+)
+
+// Rate is one reference rate: one unit of Base costs Rate units of Quote on
+// Date. Num and Denom hold the parsed rate as an exact fraction.
+type Rate struct {
+	Date  time.Time
+	Base  string
+	Quote string
+	Rate  string
+	Num   int64
+	Denom int64
+}
+
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// ParseECBXML reads the eurofxref daily, 90-day or historical XML feed. All
+// rates are quoted against EUR.
+func ParseECBXML(r io.Reader) ([]Rate, error) {
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(r).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("fximport: invalid ECB XML: %w", err)
+	}
+
+	var rates []Rate
+	for _, day := range envelope.Days {
+		date, err := time.Parse(dateLayout, day.Time)
+		if err != nil {
+			return nil, fmt.Errorf("fximport: invalid date %q: %w", day.Time, err)
+		}
+		for _, cube := range day.Rates {
+			rate, err := newRate(date, ecbBase, cube.Currency, cube.Rate)
+			if err != nil {
+				return nil, err
+			}
+			rates = append(rates, rate)
+		}
+	}
+
+	if len(rates) == 0 {
+		return nil, errors.New("fximport: no rates found in ECB XML")
+	}
+	return rates, nil
+}
+
+// ParseCSV accepts either a long file with the header date,base,quote,rate or
+// the ECB wide layout (Date,USD,JPY,...) where every column is quoted
+// against EUR and missing values are written as N/A.
+func ParseCSV(r io.Reader) ([]Rate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("fximport: invalid CSV header: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+
+	long := len(header) == 4 &&
+		strings.EqualFold(header[0], "date") &&
+		strings.EqualFold(header[1], "base") &&
+		strings.EqualFold(header[2], "quote") &&
+		strings.EqualFold(header[3], "rate")
+	if !long && !strings.EqualFold(header[0], "date") {
+		return nil, fmt.Errorf("fximport: unrecognised CSV header %q", strings.Join(header, ","))
+	}
+
+	var rates []Rate
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("fximport: line %d: %w", line, err)
+		}
+
+		date, err := time.Parse(dateLayout, strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("fximport: line %d: invalid date %q", line, record[0])
+		}
+
+		if long {
+			if len(record) != 4 {
+				return nil, fmt.Errorf("fximport: line %d: expected 4 fields, got %d", line, len(record))
+			}
+			rate, err := newRate(date, record[1], record[2], record[3])
+			if err != nil {
+				return nil, fmt.Errorf("fximport: line %d: %w", line, err)
+			}
+			rates = append(rates, rate)
+			continue
+		}
+
+		for i := 1; i < len(record) && i < len(header); i++ {
+			value := strings.TrimSpace(record[i])
+			if header[i] == "" || value == "" || strings.EqualFold(value, "N/A") {
+				continue
+			}
+			rate, err := newRate(date, ecbBase, header[i], value)
+			if err != nil {
+				return nil, fmt.Errorf("fximport: line %d: %w", line, err)
+			}
+			rates = append(rates, rate)
+		}
+	}
+
+	if len(rates) == 0 {
+		return nil, errors.New("fximport: no rates found in CSV")
+	}
+	return rates, nil
+}
+
+// PublishedAt returns when the reference rates for date became public, in
+// UTC. Using it as valid_from keeps a rate out of anything priced earlier that
+// day, which a backtest could not have known.
+func PublishedAt(date time.Time) time.Time {
+	loc, err := time.LoadLocation(ecbPublishLocation)
+	if err != nil {
+		panic(fmt.Sprintf("fximport: %v", err))
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), ecbPublishHour, 0, 0, 0, loc).UTC()
+}
+
+func newRate(date time.Time, base, quote, value string) (Rate, error) {
+	base = strings.ToUpper(strings.TrimSpace(base))
+	quote = strings.ToUpper(strings.TrimSpace(quote))
+	if len(base) != 3 || len(quote) != 3 || base == quote {
+		return Rate{}, fmt.Errorf("invalid currency pair %s/%s", base, quote)
+	}
+
+	num, denom, err := decimal.ParseRational(value)
+	if err != nil {
+		return Rate{}, fmt.Errorf("invalid rate for %s/%s on %s: %w", base, quote, date.Format(dateLayout), err)
+	}
+
+	return Rate{
+		Date:  date,
+		Base:  base,
+		Quote: quote,
+		Rate:  strings.TrimSpace(value),
+		Num:   num,
+		Denom: denom,
+	}, nil
+}
+
+type Gap struct {
+	Base  string
+	Quote string
+	From  time.Time
+	To    time.Time
+	Days  int
+}
+
+type Report struct {
+	Rates      []Rate
+	Duplicates int
+	Conflicts  []string
+	Gaps       []Gap
+}
+
+// Validate sorts rates by pair and date, drops exact duplicates and reports
+// conflicting duplicates plus gaps of more than maxGapDays calendar days
+// between consecutive dates of a pair. Weekends alone never count as a gap.
+func Validate(rates []Rate, maxGapDays int) *Report {
+	sorted := make([]Rate, len(rates))
+	copy(sorted, rates)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Base != sorted[j].Base {
+			return sorted[i].Base < sorted[j].Base
+		}
+		if sorted[i].Quote != sorted[j].Quote {
+			return sorted[i].Quote < sorted[j].Quote
+		}
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	report := &Report{}
+	for i, rate := range sorted {
+		if i > 0 {
+			prev := report.Rates[len(report.Rates)-1]
+			samePair := prev.Base == rate.Base && prev.Quote == rate.Quote
+			if samePair && prev.Date.Equal(rate.Date) {
+				if prev.Num == rate.Num && prev.Denom == rate.Denom {
+					report.Duplicates++
+				} else {
+					report.Conflicts = append(report.Conflicts, fmt.Sprintf("%s/%s on %s: %s vs %s",
+						rate.Base, rate.Quote, rate.Date.Format(dateLayout), prev.Rate, rate.Rate))
+				}
+				continue
+			}
+			if samePair && businessDaysBetween(prev.Date, rate.Date) > 0 {
+				if days := int(rate.Date.Sub(prev.Date).Hours() / 24); days > maxGapDays {
+					report.Gaps = append(report.Gaps, Gap{
+						Base:  rate.Base,
+						Quote: rate.Quote,
+						From:  prev.Date,
+						To:    rate.Date,
+						Days:  days,
+					})
+				}
+			}
+		}
+		report.Rates = append(report.Rates, rate)
+	}
+
+	return report
+}
+
+// businessDaysBetween counts weekdays strictly between from and to.
+func businessDaysBetween(from, to time.Time) int {
+	count := 0
+	for d := from.AddDate(0, 0, 1); d.Before(to); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() != time.Saturday && d.Weekday() != time.Sunday {
+			count++
+		}
+	}
+	return count
+}
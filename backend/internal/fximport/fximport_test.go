@@ -0,0 +1,105 @@
+package fximport
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const ecbXML = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<gesmes:Sender><gesmes:name>European Central Bank</gesmes:name></gesmes:Sender>
+	<Cube>
+		<Cube time="2024-01-03">
+			<Cube currency="USD" rate="1.0919"/>
+			<Cube currency="JPY" rate="155.52"/>
+		</Cube>
+		<Cube time="2024-01-02">
+			<Cube currency="USD" rate="1.0956"/>
+			<Cube currency="JPY" rate="155.68"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+func TestParseECBXML(t *testing.T) {
+	rates, err := ParseECBXML(strings.NewReader(ecbXML))
+	if err != nil {
+		t.Fatalf("ParseECBXML failed: %v", err)
+	}
+	if len(rates) != 4 {
+		t.Fatalf("Expected 4 rates, got %d", len(rates))
+	}
+
+	first := rates[0]
+	if first.Base != "EUR" || first.Quote != "USD" || first.Date.Format("2006-01-02") != "2024-01-03" {
+		t.Errorf("Unexpected first rate %+v", first)
+	}
+	if first.Num != 10919 || first.Denom != 10000 {
+		t.Errorf("Expected 10919/10000, got %d/%d", first.Num, first.Denom)
+	}
+}
+
+func TestParseCSV_LongAndWide(t *testing.T) {
+	long := "date,base,quote,rate\n2024-01-02,usd,eur,0.92\n"
+	rates, err := ParseCSV(strings.NewReader(long))
+	if err != nil {
+		t.Fatalf("ParseCSV long failed: %v", err)
+	}
+	if len(rates) != 1 || rates[0].Base != "USD" || rates[0].Num != 23 || rates[0].Denom != 25 {
+		t.Errorf("Unexpected long rates %+v", rates)
+	}
+
+	wide := "Date,USD,JPY,CYP,\n2024-01-02,1.0956,155.68,N/A,\n"
+	rates, err = ParseCSV(strings.NewReader(wide))
+	if err != nil {
+		t.Fatalf("ParseCSV wide failed: %v", err)
+	}
+	if len(rates) != 2 || rates[0].Base != "EUR" || rates[1].Quote != "JPY" {
+		t.Errorf("Unexpected wide rates %+v", rates)
+	}
+
+	if _, err := ParseCSV(strings.NewReader("date,base,quote,rate\n2024-01-02,USD,EUR,abc\n")); err == nil {
+		t.Error("Expected error for invalid rate")
+	}
+}
+
+func TestValidate_DuplicatesAndGaps(t *testing.T) {
+	csv := "date,base,quote,rate\n" +
+		"2024-01-05,EUR,USD,1.09\n" +
+		"2024-01-05,EUR,USD,1.09\n" +
+		"2024-01-08,EUR,USD,1.10\n" +
+		"2024-01-15,EUR,USD,1.11\n" +
+		"2024-01-15,EUR,GBP,0.86\n" +
+		"2024-01-15,EUR,GBP,0.87\n"
+	rates, err := ParseCSV(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("ParseCSV failed: %v", err)
+	}
+
+	report := Validate(rates, 4)
+	if report.Duplicates != 1 {
+		t.Errorf("Expected 1 duplicate, got %d", report.Duplicates)
+	}
+	if len(report.Conflicts) != 1 {
+		t.Errorf("Expected 1 conflict, got %v", report.Conflicts)
+	}
+	// Friday to Monday is a weekend, Monday to the next Monday is a gap
+	if len(report.Gaps) != 1 || report.Gaps[0].Days != 7 {
+		t.Errorf("Expected one 7-day gap, got %+v", report.Gaps)
+	}
+	if len(report.Rates) != 4 {
+		t.Errorf("Expected 4 unique rates, got %d", len(report.Rates))
+	}
+}
+
+func TestPublishedAt(t *testing.T) {
+	winter := PublishedAt(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	if want := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC); !winter.Equal(want) {
+		t.Errorf("Expected %s in winter, got %s", want, winter)
+	}
+	summer := PublishedAt(time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC))
+	if want := time.Date(2024, 7, 1, 14, 0, 0, 0, time.UTC); !summer.Equal(want) {
+		t.Errorf("Expected %s in summer, got %s", want, summer)
+	}
+}
@@ -0,0 +1,58 @@
+package dto
+
+import "time"
+
+type GetAccountsRequest struct {
+	IncludeClosed bool `form:"include_closed"`
+}
+
+type OpenAccountRequest struct {
+	Currency string `json:"currency" binding:"required,len=3"`
+	Name     string `json:"name" binding:"omitempty,max=50"`
+	Type     string `json:"type" binding:"omitempty,oneof=current savings"`
+}
+
+type SetAccountStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=active debit_frozen frozen closed"`
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+type SetCreditLimitRequest struct {
+	CreditLimitCents int64 `json:"credit_limit_cents" binding:"gte=0"`
+}
+
+type SetInterestRateRequest struct {
+	InterestRate string `json:"interest_rate" binding:"required,max=32"`
+}
+
+type AccrueInterestRequest struct {
+	Date time.Time `form:"date" binding:"required" time_format:"2006-01-02"`
+}
+
+type CapitalizeInterestRequest struct {
+	Month time.Time `form:"month" binding:"required" time_format:"2006-01"`
+}
+
+type GetStatementRequest struct {
+	Period string `form:"period" binding:"required"`
+	Format string `form:"format" binding:"omitempty,oneof=json csv"`
+}
+
+type GetBalanceRequest struct {
+	At time.Time `form:"at" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type GetBalanceHistoryRequest struct {
+	From time.Time `form:"from" time_format:"2006-01-02"`
+	To   time.Time `form:"to" time_format:"2006-01-02"`
+}
+
+type AddAccountMemberRequest struct {
+	UserID string `json:"user_id" binding:"required,max=255"`
+	Role   string `json:"role" binding:"required,oneof=owner can_transact view_only"`
+}
+
+type CloseAccountRequest struct {
+	ToAccountID string `json:"to_account_id" binding:"omitempty,uuid"`
+	Reason      string `json:"reason" binding:"max=500"`
+}
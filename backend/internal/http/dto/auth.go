@@ -18,3 +18,7 @@ type AuthResponse struct {
 	Token string      `json:"token"`
 	User  models.User `json:"user"`
 }
+
+type SetPreferredCurrencyRequest struct {
+	Currency string `json:"currency" binding:"omitempty,len=3"`
+}
@@ -0,0 +1,49 @@
+package dto
+
+import "time"
+
+type PublishFXRateRequest struct {
+	BaseCurrency  string     `json:"base_currency" binding:"required,len=3"`
+	QuoteCurrency string     `json:"quote_currency" binding:"required,len=3"`
+	Rate          string     `json:"rate" binding:"required"`
+	ValidFrom     *time.Time `json:"valid_from"`
+	Source        string     `json:"source" binding:"required,max=50"`
+}
+
+type GetFXRateRequest struct {
+	From string    `form:"from" binding:"required,len=3"`
+	To   string    `form:"to" binding:"required,len=3"`
+	At   time.Time `form:"at" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type GetFXRateHistoryRequest struct {
+	Base  string `form:"base" binding:"required,len=3"`
+	Quote string `form:"quote" binding:"required,len=3"`
+	Limit int    `form:"limit"`
+}
+
+type SetFXSpreadRequest struct {
+	BaseCurrency  string `json:"base_currency" binding:"required,len=3"`
+	QuoteCurrency string `json:"quote_currency" binding:"required,len=3"`
+	BidBps        int64  `json:"bid_bps" binding:"gte=0,lte=10000"`
+	AskBps        int64  `json:"ask_bps" binding:"gte=0,lte=10000"`
+}
+
+type GetFXRevenueRequest struct {
+	From time.Time `form:"from" time_format:"2006-01-02"`
+	To   time.Time `form:"to" time_format:"2006-01-02"`
+}
+
+type GetFXPositionsRequest struct {
+	Base string    `form:"base" binding:"omitempty,len=3"`
+	From time.Time `form:"from" time_format:"2006-01-02"`
+	To   time.Time `form:"to" time_format:"2006-01-02"`
+}
+
+type PlaceFXOrderRequest struct {
+	FromCurrency string     `json:"from_currency" binding:"required,len=3"`
+	ToCurrency   string     `json:"to_currency" binding:"required,len=3"`
+	AmountCents  int64      `json:"amount_cents" binding:"required,gt=0"`
+	TargetRate   string     `json:"target_rate" binding:"required"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+}
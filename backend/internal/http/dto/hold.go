@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+type PlaceHoldRequest struct {
+	AccountID      string     `json:"account_id" binding:"required,uuid"`
+	PayeeAccountID string     `json:"payee_account_id" binding:"required,uuid"`
+	AmountCents    int64      `json:"amount_cents" binding:"required,gt=0"`
+	Description    string     `json:"description" binding:"max=255"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+}
+
+type CaptureHoldRequest struct {
+	AmountCents int64 `json:"amount_cents" binding:"omitempty,gt=0"`
+}
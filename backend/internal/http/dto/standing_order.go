@@ -0,0 +1,15 @@
+package dto
+
+type CreateStandingOrderRequest struct {
+	FromAccountID   string `json:"from_account_id" binding:"required,uuid"`
+	ToUserID        string `json:"to_user_id" binding:"required_without_all=ToAccountID ToAccountNumber"`
+	ToAccountID     string `json:"to_account_id" binding:"omitempty,uuid"`
+	ToAccountNumber string `json:"to_account_number" binding:"omitempty,max=42"`
+	AmountCents     int64  `json:"amount_cents" binding:"required,gt=0"`
+	Description     string `json:"description" binding:"max=255"`
+	Frequency       string `json:"frequency" binding:"required,oneof=weekly monthly"`
+	DayOfMonth      int    `json:"day_of_month" binding:"omitempty,min=1,max=31"`
+	MonthEndRule    string `json:"month_end_rule" binding:"omitempty,oneof=last_day next_month"`
+	StartOn         string `json:"start_on" binding:"required,datetime=2006-01-02"`
+	EndOn           string `json:"end_on" binding:"omitempty,datetime=2006-01-02"`
+}
@@ -1,14 +1,30 @@
 package dto
 
+import "time"
+
 type TransferRequest struct {
-	ToUserID    string `json:"to_user_id" binding:"required"`
-	Currency    string `json:"currency" binding:"required,oneof=USD EUR"`
-	AmountCents int64  `json:"amount_cents" binding:"required,gt=0"`
+	ToUserID        string `json:"to_user_id" binding:"required_without_all=ToAccountID ToAccountNumber"`
+	ToAccountID     string `json:"to_account_id" binding:"omitempty,uuid"`
+	ToAccountNumber string `json:"to_account_number" binding:"omitempty,max=42"`
+	FromAccountID   string `json:"from_account_id" binding:"omitempty,uuid"`
+	Currency        string `json:"currency" binding:"required_without=FromAccountID,omitempty,len=3"`
+	ToCurrency      string `json:"to_currency" binding:"omitempty,len=3"`
+	AmountCents     int64  `json:"amount_cents" binding:"required,gt=0"`
 }
 
 type ExchangeRequest struct {
-	FromCurrency string `json:"from_currency" binding:"required,oneof=USD EUR"`
-	AmountCents  int64  `json:"amount_cents" binding:"required,gt=0"`
+	FromCurrency  string `json:"from_currency" binding:"required_without=QuoteID,omitempty,len=3"`
+	ToCurrency    string `json:"to_currency" binding:"omitempty,len=3"`
+	AmountCents   int64  `json:"amount_cents" binding:"required_without_all=QuoteID ToAmountCents,omitempty,gt=0"`
+	ToAmountCents int64  `json:"to_amount_cents" binding:"omitempty,gt=0"`
+	QuoteID       string `json:"quote_id" binding:"omitempty,uuid"`
+}
+
+type ExchangeQuoteRequest struct {
+	FromCurrency  string `json:"from_currency" binding:"required,len=3"`
+	ToCurrency    string `json:"to_currency" binding:"omitempty,len=3"`
+	AmountCents   int64  `json:"amount_cents" binding:"required_without=ToAmountCents,omitempty,gt=0"`
+	ToAmountCents int64  `json:"to_amount_cents" binding:"omitempty,gt=0"`
 }
 
 type GetTransactionsRequest struct {
@@ -16,3 +32,33 @@ type GetTransactionsRequest struct {
 	Page  int    `form:"page"`
 	Limit int    `form:"limit"`
 }
+
+// ReverseTransactionRequest refunds whatever is left of the transaction
+// unless AmountCents is set.
+type ReverseTransactionRequest struct {
+	AmountCents int64  `json:"amount_cents" binding:"omitempty,gt=0"`
+	Reason      string `json:"reason" binding:"required,max=500"`
+}
+
+type ScheduleTransferRequest struct {
+	TransferRequest
+	ExecuteAt time.Time `json:"execute_at" binding:"required"`
+}
+
+// BatchTransferLine is checked by the batch itself, so a bad line is reported
+// as failed instead of rejecting the request. ParseError carries the reason a
+// CSV line could not be read.
+type BatchTransferLine struct {
+	Recipient   string `json:"recipient"`
+	Currency    string `json:"currency"`
+	AmountCents int64  `json:"amount_cents"`
+	ParseError  string `json:"-"`
+}
+
+// BatchTransferRequest pays every line from the caller's account in the
+// line's currency, or from FromAccountID when it is set.
+type BatchTransferRequest struct {
+	Mode          string              `json:"mode" binding:"omitempty,oneof=atomic per_line"`
+	FromAccountID string              `json:"from_account_id" binding:"omitempty,uuid"`
+	Lines         []BatchTransferLine `json:"lines" binding:"required,min=1,max=1000"`
+}
@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"net/http"
+	"time"
 
+	"mini-banking-platform/internal/http/dto"
 	"mini-banking-platform/internal/http/response"
 	"github.com/gin-gonic/gin"
 )
@@ -28,8 +30,14 @@ func (h *AccountHandler) GetAccounts(c *gin.Context) {
 		return
 	}
 
+	var req dto.GetAccountsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
 	ctx := c.Request.Context()
-	accounts, err := h.handler.accountService.GetUserAccounts(ctx, userIDStr)
+	accounts, err := h.handler.accountService.GetUserAccounts(ctx, userIDStr, req.IncludeClosed)
 	if err != nil {
 		response.WithServiceError(c, err)
 		return
@@ -38,6 +46,35 @@ func (h *AccountHandler) GetAccounts(c *gin.Context) {
 	response.WithJSON(c, http.StatusOK, gin.H{"accounts": accounts})
 }
 
+func (h *AccountHandler) OpenAccount(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.OpenAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	account, err := h.handler.accountService.OpenAccount(ctx, userIDStr, req, h.handler.config.SavingsInterestRate)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusCreated, account)
+}
+
 func (h *AccountHandler) GetBalance(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -53,7 +90,28 @@ func (h *AccountHandler) GetBalance(c *gin.Context) {
 
 	accountID := c.Param("id")
 
+	var req dto.GetBalanceRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
 	ctx := c.Request.Context()
+	if !req.At.IsZero() {
+		account, balanceCents, err := h.handler.accountService.GetAccountBalanceAt(ctx, userIDStr, accountID, req.At.UTC())
+		if err != nil {
+			response.WithServiceError(c, err)
+			return
+		}
+
+		response.WithJSON(c, http.StatusOK, gin.H{
+			"balance_cents": balanceCents,
+			"currency":      account.Currency,
+			"at":            req.At.UTC(),
+		})
+		return
+	}
+
 	account, err := h.handler.accountService.GetAccountBalance(ctx, userIDStr, accountID)
 	if err != nil {
 		response.WithServiceError(c, err)
@@ -66,6 +124,44 @@ func (h *AccountHandler) GetBalance(c *gin.Context) {
 	})
 }
 
+func (h *AccountHandler) GetBalanceHistory(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.GetBalanceHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
+	to := req.To
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	from := req.From
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -29)
+	}
+
+	ctx := c.Request.Context()
+	points, err := h.handler.accountService.GetBalanceHistory(ctx, userIDStr, c.Param("id"), from, to)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, gin.H{"balances": points})
+}
+
 func (h *AccountHandler) ReconcileBalances(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -89,3 +185,79 @@ func (h *AccountHandler) ReconcileBalances(c *gin.Context) {
 	response.WithJSON(c, http.StatusOK, results)
 }
 
+
+func (h *AccountHandler) SetStatus(c *gin.Context) {
+	var req dto.SetAccountStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	account, err := h.handler.accountService.SetStatus(ctx, c.GetString("user_id"), c.Param("id"), req)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, account)
+}
+
+func (h *AccountHandler) SetCreditLimit(c *gin.Context) {
+	var req dto.SetCreditLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	account, err := h.handler.accountService.SetCreditLimit(ctx, c.Param("id"), req)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, account)
+}
+
+func (h *AccountHandler) GetStatusHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+	changes, err := h.handler.accountService.GetStatusHistory(ctx, c.Param("id"))
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, gin.H{"changes": changes})
+}
+
+func (h *AccountHandler) CloseAccount(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.CloseAccountRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.WithBindError(c, err)
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	account, transaction, err := h.handler.transactionService.CloseAccount(ctx, userIDStr, c.Param("id"), req)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, gin.H{"account": account, "sweep": transaction})
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccountMemberHandler struct {
+	handler *Handler
+}
+
+func NewAccountMemberHandler(h *Handler) *AccountMemberHandler {
+	return &AccountMemberHandler{handler: h}
+}
+
+func (h *AccountMemberHandler) GetMembers(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	ctx := c.Request.Context()
+	members, err := h.handler.accountMemberService.GetMembers(ctx, userIDStr, c.Param("id"))
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, gin.H{"members": members})
+}
+
+func (h *AccountMemberHandler) AddMember(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.AddAccountMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	member, err := h.handler.accountMemberService.AddMember(ctx, userIDStr, c.Param("id"), req)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusCreated, member)
+}
+
+func (h *AccountMemberHandler) RemoveMember(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.handler.accountMemberService.RemoveMember(ctx, userIDStr, c.Param("id"), c.Param("user_id")); err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -25,7 +25,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.handler.authService.Register(ctx, req, h.handler.config.InitialBalanceUSDCents, h.handler.config.InitialBalanceEURCents)
+	resp, err := h.handler.authService.Register(ctx, req, h.handler.config.InitialBalancesCents())
 	if err != nil {
 		response.WithServiceError(c, err)
 		return
@@ -75,3 +75,32 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 	response.WithJSON(c, http.StatusOK, user)
 }
 
+
+func (h *AuthHandler) SetPreferredCurrency(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.SetPreferredCurrencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	user, err := h.handler.authService.SetPreferredCurrency(ctx, userIDStr, req)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, user)
+}
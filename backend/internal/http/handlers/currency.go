@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"mini-banking-platform/internal/http/response"
+	"github.com/gin-gonic/gin"
+)
+
+type CurrencyHandler struct {
+	handler *Handler
+}
+
+func NewCurrencyHandler(h *Handler) *CurrencyHandler {
+	return &CurrencyHandler{handler: h}
+}
+
+func (h *CurrencyHandler) GetCurrencies(c *gin.Context) {
+	ctx := c.Request.Context()
+	currencies, err := h.handler.currencyService.GetEnabledCurrencies(ctx)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, gin.H{"currencies": currencies})
+}
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/http/response"
+	"mini-banking-platform/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+type FXHandler struct {
+	handler *Handler
+}
+
+func NewFXHandler(h *Handler) *FXHandler {
+	return &FXHandler{handler: h}
+}
+
+func (h *FXHandler) GetRate(c *gin.Context) {
+	var req dto.GetFXRateRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
+	at := req.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	ctx := c.Request.Context()
+	rate, err := h.handler.fxRateService.GetRate(ctx, req.From, req.To, at.UTC())
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, rate)
+}
+
+// GetReferenceRate returns the imported historical rate in effect at the
+// given instant, for backtests. It never affects pricing.
+func (h *FXHandler) GetReferenceRate(c *gin.Context) {
+	var req dto.GetFXRateRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
+	at := req.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	ctx := c.Request.Context()
+	rate, err := h.handler.fxRateService.GetReferenceRate(ctx, req.From, req.To, at.UTC())
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, rate)
+}
+
+func (h *FXHandler) PublishRate(c *gin.Context) {
+	var req dto.PublishFXRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	rate, err := h.handler.fxRateService.PublishRate(ctx, req)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusCreated, rate)
+}
+
+func (h *FXHandler) GetRateHistory(c *gin.Context) {
+	var req dto.GetFXRateHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
+	limit := req.Limit
+	if limit < 1 {
+		limit = h.handler.config.DefaultLimit
+	}
+	if limit > h.handler.config.MaxLimit {
+		limit = h.handler.config.MaxLimit
+	}
+
+	ctx := c.Request.Context()
+	rates, err := h.handler.fxRateService.GetRateHistory(ctx, req.Base, req.Quote, limit)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, gin.H{"rates": rates})
+}
+
+func (h *FXHandler) SetSpread(c *gin.Context) {
+	var req dto.SetFXSpreadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	spread, err := h.handler.fxRateService.SetSpread(ctx, req)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, spread)
+}
+
+func (h *FXHandler) GetSpreads(c *gin.Context) {
+	ctx := c.Request.Context()
+	spreads, err := h.handler.fxRateService.GetSpreads(ctx)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, gin.H{"spreads": spreads})
+}
+
+func (h *FXHandler) GetRevenue(c *gin.Context) {
+	var req dto.GetFXRevenueRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
+	from := req.From
+	to := req.To
+	if to.IsZero() {
+		to = time.Now().UTC().AddDate(0, 0, 1).Truncate(24 * time.Hour)
+	}
+
+	ctx := c.Request.Context()
+	revenue, err := h.handler.fxRateService.GetRevenue(ctx, from, to)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, gin.H{
+		"from":    from,
+		"to":      to,
+		"revenue": revenue,
+	})
+}
+
+func (h *FXHandler) GetRoundingReport(c *gin.Context) {
+	ctx := c.Request.Context()
+	report, err := h.handler.fxRateService.GetRoundingReport(ctx)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, gin.H{"currencies": report})
+}
+
+func (h *FXHandler) GetPositions(c *gin.Context) {
+	var req dto.GetFXPositionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
+	base := req.Base
+	if base == "" {
+		base = models.CurrencyUSD
+	}
+	to := req.To
+	if to.IsZero() {
+		to = time.Now().UTC().AddDate(0, 0, 1).Truncate(24 * time.Hour)
+	}
+	from := req.From
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -30)
+	}
+
+	ctx := c.Request.Context()
+	report, err := h.handler.fxRateService.GetPositionReport(ctx, base, from, to)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, report)
+}
+
+func (h *FXHandler) PlaceOrder(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.PlaceFXOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	order, err := h.handler.fxOrderService.PlaceOrder(ctx, userIDStr, req)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusCreated, order)
+}
+
+func (h *FXHandler) GetOrders(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	ctx := c.Request.Context()
+	orders, err := h.handler.fxOrderService.GetOrders(ctx, userIDStr, c.Query("status"))
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, gin.H{"orders": orders})
+}
+
+func (h *FXHandler) CancelOrder(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	ctx := c.Request.Context()
+	order, err := h.handler.fxOrderService.CancelOrder(ctx, userIDStr, c.Param("id"))
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, order)
+}
@@ -9,29 +9,59 @@ import (
 )
 
 type Handler struct {
-	authService        *service.AuthService
-	accountService     *service.AccountService
-	transactionService *service.TransactionService
-	config             *config.Config
-	jwtService         *jwt.Service
-	logger             *slog.Logger
+	authService          *service.AuthService
+	accountService       *service.AccountService
+	transactionService   *service.TransactionService
+	currencyService      *service.CurrencyService
+	fxRateService        *service.FXRateService
+	fxOrderService       *service.FXOrderService
+	interestService      *service.InterestService
+	statementService     *service.StatementService
+	holdService          *service.HoldService
+	accountMemberService *service.AccountMemberService
+	idempotencyService   *service.IdempotencyService
+	scheduledService     *service.ScheduledTransferService
+	standingOrderService *service.StandingOrderService
+	config               *config.Config
+	jwtService           *jwt.Service
+	logger               *slog.Logger
 }
 
 func NewHandler(
 	authService *service.AuthService,
 	accountService *service.AccountService,
 	transactionService *service.TransactionService,
+	currencyService *service.CurrencyService,
+	fxRateService *service.FXRateService,
+	fxOrderService *service.FXOrderService,
+	interestService *service.InterestService,
+	statementService *service.StatementService,
+	holdService *service.HoldService,
+	accountMemberService *service.AccountMemberService,
+	idempotencyService *service.IdempotencyService,
+	scheduledService *service.ScheduledTransferService,
+	standingOrderService *service.StandingOrderService,
 	config *config.Config,
 	jwtService *jwt.Service,
 	logger *slog.Logger,
 ) *Handler {
 	return &Handler{
-		authService:        authService,
-		accountService:     accountService,
-		transactionService: transactionService,
-		config:             config,
-		jwtService:         jwtService,
-		logger:             logger,
+		authService:          authService,
+		accountService:       accountService,
+		transactionService:   transactionService,
+		currencyService:      currencyService,
+		fxRateService:        fxRateService,
+		fxOrderService:       fxOrderService,
+		interestService:      interestService,
+		statementService:     statementService,
+		holdService:          holdService,
+		accountMemberService: accountMemberService,
+		idempotencyService:   idempotencyService,
+		scheduledService:     scheduledService,
+		standingOrderService: standingOrderService,
+		config:               config,
+		jwtService:           jwtService,
+		logger:               logger,
 	}
 }
 
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"net/http"
+
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HoldHandler struct {
+	handler *Handler
+}
+
+func NewHoldHandler(h *Handler) *HoldHandler {
+	return &HoldHandler{handler: h}
+}
+
+func (h *HoldHandler) PlaceHold(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.PlaceHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	hold, err := h.handler.holdService.PlaceHold(ctx, userIDStr, req)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusCreated, hold)
+}
+
+func (h *HoldHandler) GetHolds(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	ctx := c.Request.Context()
+	holds, err := h.handler.holdService.GetHolds(ctx, userIDStr, c.Query("status"))
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, gin.H{"holds": holds})
+}
+
+func (h *HoldHandler) CaptureHold(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.CaptureHoldRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.WithBindError(c, err)
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	hold, err := h.handler.holdService.CaptureHold(ctx, userIDStr, c.Param("id"), req)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, hold)
+}
+
+func (h *HoldHandler) ReleaseHold(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	ctx := c.Request.Context()
+	hold, err := h.handler.holdService.ReleaseHold(ctx, userIDStr, c.Param("id"))
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, hold)
+}
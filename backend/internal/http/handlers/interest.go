@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InterestHandler struct {
+	handler *Handler
+}
+
+func NewInterestHandler(h *Handler) *InterestHandler {
+	return &InterestHandler{handler: h}
+}
+
+func (h *InterestHandler) SetInterestRate(c *gin.Context) {
+	var req dto.SetInterestRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	account, err := h.handler.interestService.SetInterestRate(ctx, c.Param("id"), req)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, account)
+}
+
+func (h *InterestHandler) Accrue(c *gin.Context) {
+	var req dto.AccrueInterestRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	created, err := h.handler.interestService.AccrueDay(ctx, req.Date)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, gin.H{"date": req.Date.Format("2006-01-02"), "accruals": created})
+}
+
+func (h *InterestHandler) Capitalize(c *gin.Context) {
+	var req dto.CapitalizeInterestRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	credited, err := h.handler.interestService.CapitalizeMonth(ctx, req.Month)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, gin.H{"month": req.Month.Format("2006-01"), "accounts_credited": credited})
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ScheduledTransferHandler struct {
+	handler *Handler
+}
+
+func NewScheduledTransferHandler(h *Handler) *ScheduledTransferHandler {
+	return &ScheduledTransferHandler{handler: h}
+}
+
+func (h *ScheduledTransferHandler) Schedule(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.ScheduleTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	transfer, err := h.handler.scheduledService.Schedule(ctx, userIDStr, req)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusCreated, transfer)
+}
+
+func (h *ScheduledTransferHandler) GetScheduledTransfers(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	ctx := c.Request.Context()
+	transfers, err := h.handler.scheduledService.GetScheduledTransfers(ctx, userIDStr, c.Query("status"))
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, gin.H{"scheduled_transfers": transfers})
+}
+
+func (h *ScheduledTransferHandler) Cancel(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	ctx := c.Request.Context()
+	transfer, err := h.handler.scheduledService.Cancel(ctx, userIDStr, c.Param("id"))
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, transfer)
+}
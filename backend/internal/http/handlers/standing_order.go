@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"net/http"
+
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/http/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StandingOrderHandler struct {
+	handler *Handler
+}
+
+func NewStandingOrderHandler(h *Handler) *StandingOrderHandler {
+	return &StandingOrderHandler{handler: h}
+}
+
+func (h *StandingOrderHandler) CreateStandingOrder(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.CreateStandingOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	order, err := h.handler.standingOrderService.CreateStandingOrder(ctx, userIDStr, req)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusCreated, order)
+}
+
+func (h *StandingOrderHandler) GetStandingOrders(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	ctx := c.Request.Context()
+	orders, err := h.handler.standingOrderService.GetStandingOrders(ctx, userIDStr, c.Query("status"))
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, gin.H{"standing_orders": orders})
+}
+
+func (h *StandingOrderHandler) GetRuns(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	ctx := c.Request.Context()
+	runs, err := h.handler.standingOrderService.GetRuns(ctx, userIDStr, c.Param("id"))
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, gin.H{"runs": runs})
+}
+
+func (h *StandingOrderHandler) Pause(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	ctx := c.Request.Context()
+	order, err := h.handler.standingOrderService.Pause(ctx, userIDStr, c.Param("id"))
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, order)
+}
+
+func (h *StandingOrderHandler) Resume(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	ctx := c.Request.Context()
+	order, err := h.handler.standingOrderService.Resume(ctx, userIDStr, c.Param("id"))
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, order)
+}
+
+func (h *StandingOrderHandler) Cancel(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	ctx := c.Request.Context()
+	order, err := h.handler.standingOrderService.Cancel(ctx, userIDStr, c.Param("id"))
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, order)
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/http/response"
+	"mini-banking-platform/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StatementHandler struct {
+	handler *Handler
+}
+
+func NewStatementHandler(h *Handler) *StatementHandler {
+	return &StatementHandler{handler: h}
+}
+
+func (h *StatementHandler) GetStatement(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.GetStatementRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	statement, err := h.handler.statementService.GetStatement(ctx, userIDStr, c.Param("id"), req.Period)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	if req.Format == "csv" {
+		filename := fmt.Sprintf("statement-%s-%s.csv", statement.AccountID, statement.Period)
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Status(http.StatusOK)
+		if err := writeStatementCSV(c.Writer, statement); err != nil {
+			h.handler.logger.Error("failed to write statement csv", "error", err, "statementID", statement.ID)
+		}
+		return
+	}
+
+	response.WithJSON(c, http.StatusOK, statement)
+}
+
+// writeStatementCSV renders the opening balance, one row per ledger movement
+// and the closing balance.
+func writeStatementCSV(w io.Writer, statement *models.Statement) error {
+	cw := csv.NewWriter(w)
+	records := [][]string{
+		{"date", "transaction_id", "type", "description", "amount_cents", "balance_cents"},
+		{statement.PeriodStart.Format(time.RFC3339), "", "", "Opening balance", "", strconv.FormatInt(statement.OpeningBalanceCents, 10)},
+	}
+	for _, line := range statement.Lines {
+		records = append(records, []string{
+			line.PostedAt.UTC().Format(time.RFC3339),
+			line.TransactionID,
+			line.TransactionType,
+			line.Description,
+			strconv.FormatInt(line.AmountCents, 10),
+			strconv.FormatInt(line.BalanceCents, 10),
+		})
+	}
+	records = append(records, []string{
+		statement.PeriodEnd.Format(time.RFC3339), "", "", "Closing balance", "", strconv.FormatInt(statement.ClosingBalanceCents, 10),
+	})
+	return cw.WriteAll(records)
+}
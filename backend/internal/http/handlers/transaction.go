@@ -1,13 +1,20 @@
 package handlers
 
 import (
+	"io"
 	"net/http"
+	"strings"
 
 	"mini-banking-platform/internal/http/dto"
 	"mini-banking-platform/internal/http/response"
+	"mini-banking-platform/internal/payout"
+	"mini-banking-platform/internal/service"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 )
 
+const maxBatchBodyBytes = 2 << 20
+
 type TransactionHandler struct {
 	handler *Handler
 }
@@ -36,6 +43,23 @@ func (h *TransactionHandler) Transfer(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
+	key, ok := idempotencyKey(c)
+	if !ok {
+		return
+	}
+	if key != "" {
+		transaction, replayed, err := h.handler.idempotencyService.Transfer(ctx, userIDStr, key, req)
+		if err != nil {
+			response.WithServiceError(c, err)
+			return
+		}
+		if replayed {
+			c.Header("Idempotent-Replayed", "true")
+		}
+		response.WithJSON(c, http.StatusCreated, transaction)
+		return
+	}
+
 	transaction, err := h.handler.transactionService.Transfer(ctx, userIDStr, req)
 	if err != nil {
 		response.WithServiceError(c, err)
@@ -65,6 +89,23 @@ func (h *TransactionHandler) Exchange(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
+	key, ok := idempotencyKey(c)
+	if !ok {
+		return
+	}
+	if key != "" {
+		transaction, replayed, err := h.handler.idempotencyService.Exchange(ctx, userIDStr, key, req)
+		if err != nil {
+			response.WithServiceError(c, err)
+			return
+		}
+		if replayed {
+			c.Header("Idempotent-Replayed", "true")
+		}
+		response.WithJSON(c, http.StatusCreated, transaction)
+		return
+	}
+
 	transaction, err := h.handler.transactionService.Exchange(ctx, userIDStr, req)
 	if err != nil {
 		response.WithServiceError(c, err)
@@ -74,6 +115,35 @@ func (h *TransactionHandler) Exchange(c *gin.Context) {
 	response.WithJSON(c, http.StatusCreated, transaction)
 }
 
+func (h *TransactionHandler) QuoteExchange(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	var req dto.ExchangeQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	quote, err := h.handler.transactionService.Quote(ctx, userIDStr, req, h.handler.config.FXQuoteTTL())
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusCreated, quote)
+}
+
 func (h *TransactionHandler) GetTransactions(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -121,3 +191,113 @@ func (h *TransactionHandler) GetTransactions(c *gin.Context) {
 	})
 }
 
+func (h *TransactionHandler) ReverseTransaction(c *gin.Context) {
+	var req dto.ReverseTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.WithBindError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	reversal, original, err := h.handler.transactionService.ReverseTransaction(ctx, c.GetString("user_id"), c.Param("id"), req)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	response.WithJSON(c, http.StatusCreated, gin.H{
+		"reversal": reversal,
+		"original": original,
+	})
+}
+
+// BatchTransfer takes the lines as JSON, or as a CSV file sent as the raw
+// body (text/csv) or as the "file" field of a form. CSV uploads pass mode and
+// from_account_id in the query string.
+func (h *TransactionHandler) BatchTransfer(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		response.WithError(c, "user not authenticated", http.StatusUnauthorized)
+		return
+	}
+
+	userIDStr, ok := userID.(string)
+	if !ok {
+		response.WithError(c, "invalid user ID", http.StatusInternalServerError)
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBatchBodyBytes)
+
+	var req dto.BatchTransferRequest
+	switch c.ContentType() {
+	case "text/csv", "multipart/form-data":
+		lines, err := readPayoutCSV(c)
+		if err != nil {
+			response.WithError(c, err.Error(), http.StatusBadRequest)
+			return
+		}
+		req.Mode = c.Query("mode")
+		req.FromAccountID = c.Query("from_account_id")
+		req.Lines = lines
+		if err := binding.Validator.ValidateStruct(&req); err != nil {
+			response.WithBindError(c, err)
+			return
+		}
+	default:
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.WithBindError(c, err)
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	result, err := h.handler.transactionService.BatchTransfer(ctx, userIDStr, req)
+	if err != nil {
+		response.WithServiceError(c, err)
+		return
+	}
+
+	status := http.StatusOK
+	if result.Status == service.BatchStatusRejected {
+		status = http.StatusUnprocessableEntity
+	}
+	response.WithJSON(c, status, result)
+}
+
+func readPayoutCSV(c *gin.Context) ([]dto.BatchTransferLine, error) {
+	var body io.Reader = c.Request.Body
+	if c.ContentType() == "multipart/form-data" {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, err
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		body = file
+	}
+
+	parsed, err := payout.ParseCSV(body)
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]dto.BatchTransferLine, len(parsed))
+	for i, line := range parsed {
+		lines[i] = dto.BatchTransferLine{Recipient: line.Recipient, Currency: line.Currency, AmountCents: line.AmountCents, ParseError: line.Error}
+	}
+	return lines, nil
+}
+
+// idempotencyKey reads the optional Idempotency-Key header and answers 400
+// itself when the key is unusable.
+func idempotencyKey(c *gin.Context) (string, bool) {
+	key := strings.TrimSpace(c.GetHeader("Idempotency-Key"))
+	if len(key) > 255 {
+		response.WithError(c, "Idempotency-Key must be at most 255 characters", http.StatusBadRequest)
+		return "", false
+	}
+	return key, true
+}
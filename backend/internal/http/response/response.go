@@ -49,7 +49,27 @@ func WithServiceError(c *gin.Context, err error) {
 			errors.Is(cause, errorsx.ErrInvalidAmount) ||
 			errors.Is(cause, errorsx.ErrInvalidCurrency) ||
 			errors.Is(cause, errorsx.ErrCurrenciesMustDiffer) ||
-			errors.Is(cause, errorsx.ErrCannotTransferToSelf)
+			errors.Is(cause, errorsx.ErrCannotTransferToSelf) ||
+			errors.Is(cause, errorsx.ErrCurrencyDisabled) ||
+			errors.Is(cause, errorsx.ErrRateUnavailable) ||
+			errors.Is(cause, errorsx.ErrAccountExists) ||
+			errors.Is(cause, errorsx.ErrQuoteNotFound) ||
+			errors.Is(cause, errorsx.ErrQuoteExpired) ||
+			errors.Is(cause, errorsx.ErrQuoteUsed) ||
+			errors.Is(cause, errorsx.ErrOrderNotFound) ||
+			errors.Is(cause, errorsx.ErrOrderNotOpen) ||
+			errors.Is(cause, errorsx.ErrAccountFrozen) ||
+			errors.Is(cause, errorsx.ErrAccountClosed) ||
+			errors.Is(cause, errorsx.ErrHoldNotFound) ||
+			errors.Is(cause, errorsx.ErrHoldNotActive) ||
+			errors.Is(cause, errorsx.ErrAccountAccessDenied) ||
+			errors.Is(cause, errorsx.ErrIdempotencyMismatch) ||
+			errors.Is(cause, errorsx.ErrAlreadyReversed) ||
+			errors.Is(cause, errorsx.ErrScheduleNotFound) ||
+			errors.Is(cause, errorsx.ErrScheduleNotPending) ||
+			errors.Is(cause, errorsx.ErrStandingOrderNotFound) ||
+			errors.Is(cause, errorsx.ErrStandingOrderState) ||
+			errors.Is(cause, errorsx.ErrStatementNotSettled)
 
 	if isClientError {
 		slog.Default().Warn(
@@ -98,6 +118,46 @@ func WithServiceError(c *gin.Context, err error) {
 		WithError(c, errorsx.ErrCurrenciesMustDiffer.Error(), http.StatusBadRequest)
 	case errors.Is(cause, errorsx.ErrCannotTransferToSelf):
 		WithError(c, errorsx.ErrCannotTransferToSelf.Error(), http.StatusBadRequest)
+	case errors.Is(cause, errorsx.ErrCurrencyDisabled):
+		WithError(c, errorsx.ErrCurrencyDisabled.Error(), http.StatusBadRequest)
+	case errors.Is(cause, errorsx.ErrRateUnavailable):
+		WithError(c, errorsx.ErrRateUnavailable.Error(), http.StatusUnprocessableEntity)
+	case errors.Is(cause, errorsx.ErrAccountExists):
+		WithError(c, errorsx.ErrAccountExists.Error(), http.StatusConflict)
+	case errors.Is(cause, errorsx.ErrQuoteNotFound):
+		WithError(c, errorsx.ErrQuoteNotFound.Error(), http.StatusNotFound)
+	case errors.Is(cause, errorsx.ErrQuoteExpired):
+		WithError(c, errorsx.ErrQuoteExpired.Error(), http.StatusGone)
+	case errors.Is(cause, errorsx.ErrQuoteUsed):
+		WithError(c, errorsx.ErrQuoteUsed.Error(), http.StatusConflict)
+	case errors.Is(cause, errorsx.ErrOrderNotFound):
+		WithError(c, errorsx.ErrOrderNotFound.Error(), http.StatusNotFound)
+	case errors.Is(cause, errorsx.ErrOrderNotOpen):
+		WithError(c, errorsx.ErrOrderNotOpen.Error(), http.StatusConflict)
+	case errors.Is(cause, errorsx.ErrAccountFrozen):
+		WithError(c, errorsx.ErrAccountFrozen.Error(), http.StatusForbidden)
+	case errors.Is(cause, errorsx.ErrAccountClosed):
+		WithError(c, errorsx.ErrAccountClosed.Error(), http.StatusConflict)
+	case errors.Is(cause, errorsx.ErrHoldNotFound):
+		WithError(c, errorsx.ErrHoldNotFound.Error(), http.StatusNotFound)
+	case errors.Is(cause, errorsx.ErrHoldNotActive):
+		WithError(c, errorsx.ErrHoldNotActive.Error(), http.StatusConflict)
+	case errors.Is(cause, errorsx.ErrAccountAccessDenied):
+		WithError(c, errorsx.ErrAccountAccessDenied.Error(), http.StatusForbidden)
+	case errors.Is(cause, errorsx.ErrIdempotencyMismatch):
+		WithError(c, errorsx.ErrIdempotencyMismatch.Error(), http.StatusConflict)
+	case errors.Is(cause, errorsx.ErrAlreadyReversed):
+		WithError(c, errorsx.ErrAlreadyReversed.Error(), http.StatusConflict)
+	case errors.Is(cause, errorsx.ErrScheduleNotFound):
+		WithError(c, errorsx.ErrScheduleNotFound.Error(), http.StatusNotFound)
+	case errors.Is(cause, errorsx.ErrScheduleNotPending):
+		WithError(c, errorsx.ErrScheduleNotPending.Error(), http.StatusConflict)
+	case errors.Is(cause, errorsx.ErrStandingOrderNotFound):
+		WithError(c, errorsx.ErrStandingOrderNotFound.Error(), http.StatusNotFound)
+	case errors.Is(cause, errorsx.ErrStandingOrderState):
+		WithError(c, errorsx.ErrStandingOrderState.Error(), http.StatusConflict)
+	case errors.Is(cause, errorsx.ErrStatementNotSettled):
+		WithError(c, errorsx.ErrStatementNotSettled.Error(), http.StatusConflict)
 	default:
 		WithError(c, "internal_error", http.StatusInternalServerError)
 	}
@@ -155,7 +215,7 @@ func WithBindError(c *gin.Context, err error) {
 
 func validationMessage(fe validator.FieldError) string {
 	switch fe.Tag() {
-	case "required":
+	case "required", "required_without", "required_without_all":
 		return "is required"
 	case "email":
 		return "must be a valid email"
@@ -165,6 +225,16 @@ func validationMessage(fe validator.FieldError) string {
 		return "must be one of: " + fe.Param()
 	case "gt":
 		return "must be greater than " + fe.Param()
+	case "len":
+		return "must be exactly " + fe.Param() + " characters"
+	case "max":
+		return "must be at most " + fe.Param() + " characters"
+	case "uuid":
+		return "must be a valid UUID"
+	case "gte":
+		return "must be at least " + fe.Param()
+	case "lte":
+		return "must be at most " + fe.Param()
 	default:
 		return "is invalid"
 	}
@@ -13,7 +13,7 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func NewRouter(handler *handlers.Handler, jwtService *jwt.Service, logger *slog.Logger) *gin.Engine {
+func NewRouter(handler *handlers.Handler, jwtService *jwt.Service, adminChecker middleware.AdminChecker, logger *slog.Logger) *gin.Engine {
 	router := gin.New()
 	router.Use(gin.Logger(), gin.Recovery())
 
@@ -43,7 +43,8 @@ func NewRouter(handler *handlers.Handler, jwtService *jwt.Service, logger *slog.
 		
 
 		c.Writer.Header().Set("Access-Control-Allow-Origin", allowedOrigin)
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, Idempotency-Key")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "Idempotent-Replayed")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
 		
 		if allowedOrigin != "*" {
@@ -62,6 +63,14 @@ func NewRouter(handler *handlers.Handler, jwtService *jwt.Service, logger *slog.
 	authHandler := handlers.NewAuthHandler(handler)
 	accountHandler := handlers.NewAccountHandler(handler)
 	transactionHandler := handlers.NewTransactionHandler(handler)
+	currencyHandler := handlers.NewCurrencyHandler(handler)
+	fxHandler := handlers.NewFXHandler(handler)
+	interestHandler := handlers.NewInterestHandler(handler)
+	statementHandler := handlers.NewStatementHandler(handler)
+	holdHandler := handlers.NewHoldHandler(handler)
+	accountMemberHandler := handlers.NewAccountMemberHandler(handler)
+	scheduledTransferHandler := handlers.NewScheduledTransferHandler(handler)
+	standingOrderHandler := handlers.NewStandingOrderHandler(handler)
 
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
@@ -74,18 +83,70 @@ func NewRouter(handler *handlers.Handler, jwtService *jwt.Service, logger *slog.
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
 			auth.GET("/me", middleware.AuthMiddleware(jwtService), authHandler.GetMe)
+			auth.PUT("/me/preferred-currency", middleware.AuthMiddleware(jwtService), authHandler.SetPreferredCurrency)
 		}
 
+		api.GET("/currencies", currencyHandler.GetCurrencies)
+
 		protected := api.Group("")
 		protected.Use(middleware.AuthMiddleware(jwtService))
 		{
 			protected.GET("/accounts", accountHandler.GetAccounts)
+			protected.POST("/accounts", accountHandler.OpenAccount)
+			protected.POST("/accounts/:id/close", accountHandler.CloseAccount)
 			protected.GET("/accounts/:id/balance", accountHandler.GetBalance)
+			protected.GET("/accounts/:id/balance/history", accountHandler.GetBalanceHistory)
+			protected.GET("/accounts/:id/statements", statementHandler.GetStatement)
+			protected.GET("/accounts/:id/members", accountMemberHandler.GetMembers)
+			protected.POST("/accounts/:id/members", accountMemberHandler.AddMember)
+			protected.DELETE("/accounts/:id/members/:user_id", accountMemberHandler.RemoveMember)
 			protected.GET("/accounts/reconcile", accountHandler.ReconcileBalances)
 
 			protected.POST("/transactions/transfer", transactionHandler.Transfer)
 			protected.POST("/transactions/exchange", transactionHandler.Exchange)
+			protected.POST("/transactions/batch", transactionHandler.BatchTransfer)
+			protected.POST("/transactions/exchange/quote", transactionHandler.QuoteExchange)
 			protected.GET("/transactions", transactionHandler.GetTransactions)
+			protected.POST("/transactions/scheduled", scheduledTransferHandler.Schedule)
+			protected.GET("/transactions/scheduled", scheduledTransferHandler.GetScheduledTransfers)
+			protected.DELETE("/transactions/scheduled/:id", scheduledTransferHandler.Cancel)
+
+			protected.POST("/standing-orders", standingOrderHandler.CreateStandingOrder)
+			protected.GET("/standing-orders", standingOrderHandler.GetStandingOrders)
+			protected.GET("/standing-orders/:id/runs", standingOrderHandler.GetRuns)
+			protected.POST("/standing-orders/:id/pause", standingOrderHandler.Pause)
+			protected.POST("/standing-orders/:id/resume", standingOrderHandler.Resume)
+			protected.DELETE("/standing-orders/:id", standingOrderHandler.Cancel)
+
+			protected.POST("/holds", holdHandler.PlaceHold)
+			protected.GET("/holds", holdHandler.GetHolds)
+			protected.POST("/holds/:id/capture", holdHandler.CaptureHold)
+			protected.POST("/holds/:id/release", holdHandler.ReleaseHold)
+
+			protected.GET("/fx/rates", fxHandler.GetRate)
+			protected.POST("/fx/orders", fxHandler.PlaceOrder)
+			protected.GET("/fx/orders", fxHandler.GetOrders)
+			protected.DELETE("/fx/orders/:id", fxHandler.CancelOrder)
+		}
+
+		admin := protected.Group("/admin")
+		admin.Use(middleware.AdminMiddleware(adminChecker))
+		{
+			admin.POST("/fx/rates", fxHandler.PublishRate)
+			admin.GET("/fx/rates", fxHandler.GetRateHistory)
+			admin.GET("/fx/reference-rates", fxHandler.GetReferenceRate)
+			admin.PUT("/fx/spreads", fxHandler.SetSpread)
+			admin.GET("/fx/spreads", fxHandler.GetSpreads)
+			admin.GET("/fx/revenue", fxHandler.GetRevenue)
+			admin.GET("/fx/rounding", fxHandler.GetRoundingReport)
+			admin.GET("/fx/positions", fxHandler.GetPositions)
+			admin.PUT("/accounts/:id/status", accountHandler.SetStatus)
+			admin.GET("/accounts/:id/status", accountHandler.GetStatusHistory)
+			admin.PUT("/accounts/:id/credit-limit", accountHandler.SetCreditLimit)
+			admin.POST("/transactions/:id/reverse", transactionHandler.ReverseTransaction)
+			admin.PUT("/accounts/:id/interest-rate", interestHandler.SetInterestRate)
+			admin.POST("/interest/accrue", interestHandler.Accrue)
+			admin.POST("/interest/capitalize", interestHandler.Capitalize)
 		}
 	}
 
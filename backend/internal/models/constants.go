@@ -9,17 +9,11 @@ const (
 	TransactionTypeTransfer        = "transfer"
 	TransactionTypeExchange        = "exchange"
 	TransactionTypeInitialDeposit  = "initial_deposit"
+	TransactionTypeInterest        = "interest"
+	TransactionTypeReversal        = "reversal"
 )
 
 
-const (
-	ExchangeRateUSDtoEURNum   int64 = 23
-	ExchangeRateUSDtoEURDenom int64 = 25
-
-	ExchangeRateEURtoUSDNum   int64 = 25
-	ExchangeRateEURtoUSDDenom int64 = 23
-)
-
 const (
 	MinExchangeAmountCents int64 = 10
 )
@@ -27,5 +21,72 @@ const (
 const(
 	FXSystemUserID = "00000000-0000-0000-0000-000000000001"
 	FXSystemUserEmail = "fx@system.local"
+
+	RevenueSystemUserID    = "00000000-0000-0000-0000-000000000002"
+	RevenueSystemUserEmail = "revenue@system.local"
+
+	InterestSystemUserID    = "00000000-0000-0000-0000-000000000003"
+	InterestSystemUserEmail = "interest@system.local"
+)
+
+const (
+	BasisPointsDenominator int64 = 10000
+)
+
+const (
+	FXOrderStatusOpen      = "open"
+	FXOrderStatusFilled    = "filled"
+	FXOrderStatusCancelled = "cancelled"
+	FXOrderStatusExpired   = "expired"
+	FXOrderStatusFailed    = "failed"
+)
+
+const (
+	HoldStatusActive   = "active"
+	HoldStatusCaptured = "captured"
+	HoldStatusReleased = "released"
+	HoldStatusExpired  = "expired"
+)
+
+const (
+	ScheduledTransferStatusScheduled  = "scheduled"
+	ScheduledTransferStatusProcessing = "processing"
+	ScheduledTransferStatusExecuted   = "executed"
+	ScheduledTransferStatusFailed     = "failed"
+	ScheduledTransferStatusCancelled  = "cancelled"
+)
+
+const (
+	StandingOrderStatusActive    = "active"
+	StandingOrderStatusPaused    = "paused"
+	StandingOrderStatusCompleted = "completed"
+	StandingOrderStatusCancelled = "cancelled"
+)
+
+const (
+	StandingOrderRunProcessing = "processing"
+	StandingOrderRunExecuted   = "executed"
+	StandingOrderRunFailed     = "failed"
 )
 
+// The account's user_id is always an owner; the roles below are granted to
+// the other members of a shared account.
+const (
+	AccountRoleOwner       = "owner"
+	AccountRoleCanTransact = "can_transact"
+	AccountRoleViewOnly    = "view_only"
+)
+
+// Debit-frozen accounts still accept credits; frozen and closed accounts
+// accept no postings at all.
+const (
+	AccountStatusActive      = "active"
+	AccountStatusDebitFrozen = "debit_frozen"
+	AccountStatusFrozen      = "frozen"
+	AccountStatusClosed      = "closed"
+)
+
+const (
+	AccountTypeCurrent = "current"
+	AccountTypeSavings = "savings"
+)
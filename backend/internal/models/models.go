@@ -5,33 +5,66 @@ import (
 )
 
 type User struct {
-	ID        string    `db:"id" json:"id"`
-	Email     string    `db:"email" json:"email"`
-	Password  string    `db:"password" json:"-"`
-	FirstName string    `db:"first_name" json:"first_name"`
-	LastName  string    `db:"last_name" json:"last_name"`
-	CreatedAt time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	ID                string    `db:"id" json:"id"`
+	Email             string    `db:"email" json:"email"`
+	Password          string    `db:"password" json:"-"`
+	FirstName         string    `db:"first_name" json:"first_name"`
+	LastName          string    `db:"last_name" json:"last_name"`
+	IsAdmin           bool      `db:"is_admin" json:"is_admin"`
+	PreferredCurrency *string   `db:"preferred_currency" json:"preferred_currency,omitempty"`
+	CreatedAt         time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt         time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type Currency struct {
+	Code       string    `db:"code" json:"code"`
+	MinorUnits int       `db:"minor_units" json:"minor_units"`
+	Symbol     string    `db:"symbol" json:"symbol"`
+	Enabled    bool      `db:"enabled" json:"enabled"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
 }
 
 type Account struct {
-	ID           string    `db:"id" json:"id"`
-	UserID       string    `db:"user_id" json:"user_id"`
-	Currency     string    `db:"currency" json:"currency"`
-	BalanceCents int64     `db:"balance_cents" json:"balance_cents"`
-	CreatedAt    time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+	ID                    string    `db:"id" json:"id"`
+	AccountNumber         string    `db:"account_number" json:"account_number"`
+	UserID                string    `db:"user_id" json:"user_id"`
+	Currency              string    `db:"currency" json:"currency"`
+	Name                  *string   `db:"name" json:"name,omitempty"`
+	Type                  string    `db:"type" json:"type"`
+	Status                string    `db:"status" json:"status"`
+	BalanceCents          int64     `db:"balance_cents" json:"balance_cents"`
+	ReservedCents         int64     `db:"reserved_cents" json:"reserved_cents"`
+	CreditLimitCents      *int64    `db:"credit_limit_cents" json:"credit_limit_cents"`
+	OverdraftUsedCents    int64     `db:"-" json:"overdraft_used_cents"`
+	AvailableBalanceCents int64     `db:"-" json:"available_balance_cents"`
+	InterestRate          *string   `db:"interest_rate" json:"interest_rate,omitempty"`
+	InterestRateNum       *int64    `db:"interest_rate_num" json:"-"`
+	InterestRateDenom     *int64    `db:"interest_rate_denom" json:"-"`
+	InterestCarryMicros   int64     `db:"interest_carry_micros" json:"-"`
+	Role                  string    `db:"role" json:"role,omitempty"`
+	CreatedAt             time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt             time.Time `db:"updated_at" json:"updated_at"`
 }
 
 type Transaction struct {
-	ID          string    `db:"id" json:"id"`
-	Type        string    `db:"type" json:"type"`
-	FromUserID  string    `db:"from_user_id" json:"from_user_id"`
-	ToUserID    *string   `db:"to_user_id" json:"to_user_id,omitempty"`
-	Currency    string    `db:"currency" json:"currency"`
-	AmountCents int64     `db:"amount_cents" json:"amount_cents"`
-	Description string    `db:"description" json:"description"`
-	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	ID                      string    `db:"id" json:"id"`
+	Type                    string    `db:"type" json:"type"`
+	FromUserID              string    `db:"from_user_id" json:"from_user_id"`
+	ToUserID                *string   `db:"to_user_id" json:"to_user_id,omitempty"`
+	Currency                string    `db:"currency" json:"currency"`
+	AmountCents             int64     `db:"amount_cents" json:"amount_cents"`
+	ToCurrency              *string   `db:"to_currency" json:"to_currency,omitempty"`
+	ToAmountCents           *int64    `db:"to_amount_cents" json:"to_amount_cents,omitempty"`
+	Description             string    `db:"description" json:"description"`
+	FXRateID                *string   `db:"fx_rate_id" json:"fx_rate_id,omitempty"`
+	FXRateNum               *int64    `db:"fx_rate_num" json:"fx_rate_num,omitempty"`
+	FXRateDenom             *int64    `db:"fx_rate_denom" json:"fx_rate_denom,omitempty"`
+	FeeCents                int64     `db:"fee_cents" json:"fee_cents"`
+	ReversesTransactionID   *string   `db:"reverses_transaction_id" json:"reverses_transaction_id,omitempty"`
+	ReversedByTransactionID *string   `db:"reversed_by_transaction_id" json:"reversed_by_transaction_id,omitempty"`
+	RefundedCents           int64     `db:"refunded_cents" json:"refunded_cents"`
+	CreatedAt               time.Time `db:"created_at" json:"created_at"`
 }
 
 type LedgerEntry struct {
@@ -42,3 +75,254 @@ type LedgerEntry struct {
 	AmountCents   int64     `db:"amount_cents" json:"amount_cents"`
 	CreatedAt     time.Time `db:"created_at" json:"created_at"`
 }
+
+type FXRate struct {
+	ID            string    `db:"id" json:"id"`
+	BaseCurrency  string    `db:"base_currency" json:"base_currency"`
+	QuoteCurrency string    `db:"quote_currency" json:"quote_currency"`
+	RateNum       int64     `db:"rate_num" json:"rate_num"`
+	RateDenom     int64     `db:"rate_denom" json:"rate_denom"`
+	ValidFrom     time.Time `db:"valid_from" json:"valid_from"`
+	Source        string    `db:"source" json:"source"`
+	CreatedAt     time.Time `db:"created_at" json:"created_at"`
+}
+
+type FXQuote struct {
+	ID              string     `db:"id" json:"id"`
+	UserID          string     `db:"user_id" json:"user_id"`
+	FromCurrency    string     `db:"from_currency" json:"from_currency"`
+	ToCurrency      string     `db:"to_currency" json:"to_currency"`
+	FromAmountCents int64      `db:"from_amount_cents" json:"from_amount_cents"`
+	ToAmountCents   int64      `db:"to_amount_cents" json:"to_amount_cents"`
+	FeeCents        int64      `db:"fee_cents" json:"fee_cents"`
+	RoundingMode    string     `db:"rounding_mode" json:"rounding_mode"`
+	FXRateID        string     `db:"fx_rate_id" json:"fx_rate_id"`
+	RateNum         int64      `db:"rate_num" json:"rate_num"`
+	RateDenom       int64      `db:"rate_denom" json:"rate_denom"`
+	ExpiresAt       time.Time  `db:"expires_at" json:"expires_at"`
+	TransactionID   *string    `db:"transaction_id" json:"transaction_id,omitempty"`
+	UsedAt          *time.Time `db:"used_at" json:"used_at,omitempty"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+}
+
+type FXSpread struct {
+	BaseCurrency  string    `db:"base_currency" json:"base_currency"`
+	QuoteCurrency string    `db:"quote_currency" json:"quote_currency"`
+	BidBps        int64     `db:"bid_bps" json:"bid_bps"`
+	AskBps        int64     `db:"ask_bps" json:"ask_bps"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type CurrencyTotal struct {
+	Currency    string `db:"currency" json:"currency"`
+	AmountCents int64  `db:"amount_cents" json:"amount_cents"`
+}
+
+// RoundingEntry records the exact fraction of a cent dropped or added when an
+// exchange amount was rounded: exact = rounded + RemainderNum/RemainderDenom.
+type RoundingEntry struct {
+	TransactionID  string    `db:"transaction_id" json:"transaction_id"`
+	Currency       string    `db:"currency" json:"currency"`
+	RoundingMode   string    `db:"rounding_mode" json:"rounding_mode"`
+	RemainderNum   int64     `db:"remainder_num" json:"remainder_num"`
+	RemainderDenom int64     `db:"remainder_denom" json:"remainder_denom"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+}
+
+// RoundingSuspense holds the running remainder per currency. The fraction is
+// kept in NUMERIC columns because the denominators of different rates
+// multiply up beyond int64.
+type RoundingSuspense struct {
+	Currency       string    `db:"currency" json:"currency"`
+	RemainderNum   string    `db:"remainder_num" json:"remainder_num"`
+	RemainderDenom string    `db:"remainder_denom" json:"remainder_denom"`
+	ExchangeCount  int64     `db:"exchange_count" json:"exchange_count"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type RoundingRemainderSum struct {
+	Currency       string `db:"currency"`
+	RemainderNum   string `db:"remainder_num"`
+	RemainderDenom int64  `db:"remainder_denom"`
+	EntryCount     int64  `db:"entry_count"`
+}
+
+// FXOrder is a limit order that converts AmountCents of FromCurrency once the
+// from/to rate reaches TargetRate. The amount stays reserved on the source
+// account while the order is open.
+type FXOrder struct {
+	ID              string    `db:"id" json:"id"`
+	UserID          string    `db:"user_id" json:"user_id"`
+	FromCurrency    string    `db:"from_currency" json:"from_currency"`
+	ToCurrency      string    `db:"to_currency" json:"to_currency"`
+	AmountCents     int64     `db:"amount_cents" json:"amount_cents"`
+	TargetRate      string    `db:"target_rate" json:"target_rate"`
+	TargetRateNum   int64     `db:"target_rate_num" json:"-"`
+	TargetRateDenom int64     `db:"target_rate_denom" json:"-"`
+	Status          string    `db:"status" json:"status"`
+	ExpiresAt       time.Time `db:"expires_at" json:"expires_at"`
+	TransactionID   *string   `db:"transaction_id" json:"transaction_id,omitempty"`
+	FailureReason   *string   `db:"failure_reason" json:"failure_reason,omitempty"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Hold reserves part of an account's balance for a payee until it is
+// captured, released or expires.
+type Hold struct {
+	ID             string    `db:"id" json:"id"`
+	AccountID      string    `db:"account_id" json:"account_id"`
+	PayeeAccountID string    `db:"payee_account_id" json:"payee_account_id"`
+	Currency       string    `db:"currency" json:"currency"`
+	AmountCents    int64     `db:"amount_cents" json:"amount_cents"`
+	CapturedCents  int64     `db:"captured_cents" json:"captured_cents"`
+	Description    string    `db:"description" json:"description"`
+	Status         string    `db:"status" json:"status"`
+	ExpiresAt      time.Time `db:"expires_at" json:"expires_at"`
+	TransactionID  *string   `db:"transaction_id" json:"transaction_id,omitempty"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// ScheduledTransfer is a transfer request waiting for its execution time.
+type ScheduledTransfer struct {
+	ID              string     `db:"id" json:"id"`
+	UserID          string     `db:"user_id" json:"user_id"`
+	ToUserID        string     `db:"to_user_id" json:"to_user_id,omitempty"`
+	ToAccountID     *string    `db:"to_account_id" json:"to_account_id,omitempty"`
+	ToAccountNumber string     `db:"to_account_number" json:"to_account_number,omitempty"`
+	FromAccountID   *string    `db:"from_account_id" json:"from_account_id,omitempty"`
+	Currency        string     `db:"currency" json:"currency,omitempty"`
+	ToCurrency      string     `db:"to_currency" json:"to_currency,omitempty"`
+	AmountCents     int64      `db:"amount_cents" json:"amount_cents"`
+	ExecuteAt       time.Time  `db:"execute_at" json:"execute_at"`
+	Status          string     `db:"status" json:"status"`
+	FailureReason   string     `db:"failure_reason" json:"failure_reason,omitempty"`
+	TransactionID   *string    `db:"transaction_id" json:"transaction_id,omitempty"`
+	ExecutedAt      *time.Time `db:"executed_at" json:"executed_at,omitempty"`
+	CreatedAt       time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// StandingOrder pays a fixed amount between two accounts on a repeating
+// schedule. Dates are UTC days.
+type StandingOrder struct {
+	ID            string     `db:"id" json:"id"`
+	UserID        string     `db:"user_id" json:"user_id"`
+	FromAccountID string     `db:"from_account_id" json:"from_account_id"`
+	ToAccountID   string     `db:"to_account_id" json:"to_account_id"`
+	AmountCents   int64      `db:"amount_cents" json:"amount_cents"`
+	Description   string     `db:"description" json:"description"`
+	Frequency     string     `db:"frequency" json:"frequency"`
+	DayOfMonth    *int       `db:"day_of_month" json:"day_of_month,omitempty"`
+	MonthEndRule  string     `db:"month_end_rule" json:"month_end_rule"`
+	StartOn       time.Time  `db:"start_on" json:"start_on"`
+	EndOn         *time.Time `db:"end_on" json:"end_on,omitempty"`
+	NextRunOn     *time.Time `db:"next_run_on" json:"next_run_on,omitempty"`
+	Status        string     `db:"status" json:"status"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+type StandingOrderRun struct {
+	ID              string    `db:"id" json:"id"`
+	StandingOrderID string    `db:"standing_order_id" json:"standing_order_id"`
+	RunOn           time.Time `db:"run_on" json:"run_on"`
+	Status          string    `db:"status" json:"status"`
+	FailureReason   string    `db:"failure_reason" json:"failure_reason,omitempty"`
+	TransactionID   *string   `db:"transaction_id" json:"transaction_id,omitempty"`
+	CreatedAt       time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// AccountMember grants a user a role on a shared account.
+type AccountMember struct {
+	AccountID string    `db:"account_id" json:"account_id"`
+	UserID    string    `db:"user_id" json:"user_id"`
+	Email     string    `db:"email" json:"email"`
+	Role      string    `db:"role" json:"role"`
+	AddedBy   *string   `db:"added_by" json:"added_by,omitempty"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// IdempotencyKey remembers a client-supplied key with the fingerprint of the
+// request it came with and the transaction that request posted.
+type IdempotencyKey struct {
+	UserID        string    `db:"user_id"`
+	Key           string    `db:"idempotency_key"`
+	Endpoint      string    `db:"endpoint"`
+	RequestHash   string    `db:"request_hash"`
+	TransactionID string    `db:"transaction_id"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+type AccountStatusChange struct {
+	ID         string    `db:"id" json:"id"`
+	AccountID  string    `db:"account_id" json:"account_id"`
+	FromStatus string    `db:"from_status" json:"from_status"`
+	ToStatus   string    `db:"to_status" json:"to_status"`
+	Reason     string    `db:"reason" json:"reason"`
+	ChangedBy  *string   `db:"changed_by" json:"changed_by,omitempty"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+}
+
+type InterestAccrual struct {
+	ID            string     `db:"id" json:"id"`
+	AccountID     string     `db:"account_id" json:"account_id"`
+	AccrualDate   time.Time  `db:"accrual_date" json:"accrual_date"`
+	BalanceCents  int64      `db:"balance_cents" json:"balance_cents"`
+	RateNum       int64      `db:"rate_num" json:"-"`
+	RateDenom     int64      `db:"rate_denom" json:"-"`
+	DayCount      string     `db:"day_count" json:"day_count"`
+	AmountMicros  int64      `db:"amount_micros" json:"amount_micros"`
+	TransactionID *string    `db:"transaction_id" json:"transaction_id,omitempty"`
+	CapitalizedAt *time.Time `db:"capitalized_at" json:"capitalized_at,omitempty"`
+	CreatedAt     time.Time  `db:"created_at" json:"created_at"`
+}
+
+// InterestCandidate is a savings account with its balance at the end of the
+// accrual day.
+type InterestCandidate struct {
+	AccountID    string `db:"account_id"`
+	RateNum      int64  `db:"interest_rate_num"`
+	RateDenom    int64  `db:"interest_rate_denom"`
+	BalanceCents int64  `db:"balance_cents"`
+}
+
+// Statement is an issued account statement. Once stored it is never
+// recomputed.
+type Statement struct {
+	ID                  string          `db:"id" json:"id"`
+	AccountID           string          `db:"account_id" json:"account_id"`
+	Period              string          `db:"period" json:"period"`
+	PeriodStart         time.Time       `db:"period_start" json:"period_start"`
+	PeriodEnd           time.Time       `db:"period_end" json:"period_end"`
+	Currency            string          `db:"currency" json:"currency"`
+	OpeningBalanceCents int64           `db:"opening_balance_cents" json:"opening_balance_cents"`
+	ClosingBalanceCents int64           `db:"closing_balance_cents" json:"closing_balance_cents"`
+	TotalCreditsCents   int64           `db:"total_credits_cents" json:"total_credits_cents"`
+	TotalDebitsCents    int64           `db:"total_debits_cents" json:"total_debits_cents"`
+	CreatedAt           time.Time       `db:"created_at" json:"issued_at"`
+	Lines               []StatementLine `db:"-" json:"lines"`
+}
+
+type StatementLine struct {
+	Position        int       `db:"position" json:"-"`
+	LedgerEntryID   string    `db:"ledger_entry_id" json:"ledger_entry_id"`
+	TransactionID   string    `db:"transaction_id" json:"transaction_id"`
+	TransactionType string    `db:"transaction_type" json:"type"`
+	Description     string    `db:"description" json:"description"`
+	AmountCents     int64     `db:"amount_cents" json:"amount_cents"`
+	BalanceCents    int64     `db:"balance_cents" json:"balance_cents"`
+	PostedAt        time.Time `db:"posted_at" json:"posted_at"`
+}
+
+type DailyLedgerTotal struct {
+	Day         time.Time `db:"day"`
+	AmountCents int64     `db:"amount_cents"`
+}
+
+type BalancePoint struct {
+	Date         string `json:"date"`
+	BalanceCents int64  `json:"balance_cents"`
+}
@@ -0,0 +1,79 @@
+package payout
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Line is one payment of a bulk payout. Recipient is an email, a user ID or
+// an account number. Error is set when the line could not be read.
+type Line struct {
+	Recipient   string
+	Currency    string
+	AmountCents int64
+	Error       string
+}
+
+var requiredColumns = []string{"recipient", "currency", "amount_cents"}
+
+// ParseCSV reads a payout file with the header recipient,currency,amount_cents.
+// Columns may come in any order and extra columns are ignored. Only the file
+// itself is checked here: a line with missing fields or an amount that is not
+// a whole number keeps its place and carries Error, so the caller can report
+// it next to the others.
+func ParseCSV(r io.Reader) ([]Line, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("payout: invalid CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range requiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("payout: CSV header is missing the %s column", name)
+		}
+	}
+
+	var lines []Line
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("payout: line %d: %w", line, err)
+		}
+		field := func(name string) string {
+			if i := columns[name]; i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		parsed := Line{
+			Recipient: field("recipient"),
+			Currency:  strings.ToUpper(field("currency")),
+		}
+		amount := field("amount_cents")
+		if len(record) < len(header) {
+			parsed.Error = fmt.Sprintf("line %d: expected %d fields, got %d", line, len(header), len(record))
+		} else if parsed.AmountCents, err = strconv.ParseInt(amount, 10, 64); err != nil {
+			parsed.Error = fmt.Sprintf("line %d: amount_cents must be a whole number of cents, got %q", line, amount)
+		}
+		lines = append(lines, parsed)
+	}
+
+	if len(lines) == 0 {
+		return nil, errors.New("payout: no lines found in CSV")
+	}
+	return lines, nil
+}
@@ -0,0 +1,64 @@
+package payout
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCSV(t *testing.T) {
+	input := "amount_cents, recipient, currency, note\n" +
+		"150000, alice@example.com, usd, March salary\n" +
+		"99, MB34 0000 0000 0000 0001, EUR,\n"
+
+	lines, err := ParseCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseCSV failed: %v", err)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+	if lines[0] != (Line{Recipient: "alice@example.com", Currency: "USD", AmountCents: 150000}) {
+		t.Errorf("Unexpected first line %+v", lines[0])
+	}
+	if lines[1].Recipient != "MB34 0000 0000 0000 0001" || lines[1].AmountCents != 99 {
+		t.Errorf("Unexpected second line %+v", lines[1])
+	}
+}
+
+func TestParseCSV_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"missing column", "recipient,amount_cents\nalice@example.com,100\n", "missing the currency column"},
+		{"no lines", "recipient,currency,amount_cents\n", "no lines"},
+	}
+	for _, tt := range tests {
+		_, err := ParseCSV(strings.NewReader(tt.input))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestParseCSV_LineErrors(t *testing.T) {
+	input := "recipient,currency,amount_cents\n" +
+		"alice@example.com,USD,1.50\n" +
+		"bob@example.com,USD\n" +
+		"carol@example.com,USD,100\n"
+
+	lines, err := ParseCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseCSV failed: %v", err)
+	}
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d", len(lines))
+	}
+	if !strings.Contains(lines[0].Error, "whole number") || !strings.Contains(lines[1].Error, "expected 3 fields") {
+		t.Errorf("Expected per-line errors, got %q and %q", lines[0].Error, lines[1].Error)
+	}
+	if lines[1].Recipient != "bob@example.com" || lines[2].Error != "" || lines[2].AmountCents != 100 {
+		t.Errorf("Unexpected lines %+v", lines[1:])
+	}
+}
@@ -23,12 +23,13 @@ func NewAccountRepository(db *sqlx.DB, logger *slog.Logger) *AccountRepository {
 
 func (r *AccountRepository) Create(ctx context.Context, account *models.Account) error {
 	query := `
-		INSERT INTO accounts (user_id, currency, balance_cents)
-		VALUES ($1, $2, $3)
-		RETURNING id, created_at, updated_at
+		INSERT INTO accounts (user_id, currency, name, type, balance_cents, interest_rate, interest_rate_num, interest_rate_denom)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, account_number, type, status, created_at, updated_at
 	`
-	err := r.db.QueryRowContext(ctx, query, account.UserID, account.Currency, account.BalanceCents).
-		Scan(&account.ID, &account.CreatedAt, &account.UpdatedAt)
+	err := r.db.QueryRowContext(ctx, query, account.UserID, account.Currency, account.Name, account.Type, account.BalanceCents,
+		account.InterestRate, account.InterestRateNum, account.InterestRateDenom).
+		Scan(&account.ID, &account.AccountNumber, &account.Type, &account.Status, &account.CreatedAt, &account.UpdatedAt)
 
 	if err != nil {
 		r.logger.Error("repository: failed to create account", "error", err, "userID", account.UserID)
@@ -41,12 +42,12 @@ func (r *AccountRepository) Create(ctx context.Context, account *models.Account)
 
 func (r *AccountRepository) CreateInTx(ctx context.Context, tx *sqlx.Tx, account *models.Account) error {
 	query := `
-		INSERT INTO accounts (user_id, currency, balance_cents)
-		VALUES ($1, $2, $3)
-		RETURNING id, created_at, updated_at
+		INSERT INTO accounts (user_id, currency, name, balance_cents)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, account_number, type, status, created_at, updated_at
 	`
-	err := tx.QueryRowContext(ctx, query, account.UserID, account.Currency, account.BalanceCents).
-		Scan(&account.ID, &account.CreatedAt, &account.UpdatedAt)
+	err := tx.QueryRowContext(ctx, query, account.UserID, account.Currency, account.Name, account.BalanceCents).
+		Scan(&account.ID, &account.AccountNumber, &account.Type, &account.Status, &account.CreatedAt, &account.UpdatedAt)
 
 	if err != nil {
 		r.logger.Error("repository: failed to create account in tx", "error", err, "userID", account.UserID)
@@ -66,15 +67,19 @@ func (r *AccountRepository) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
 	return tx, nil
 }
 
-func (r *AccountRepository) FindByUserID(ctx context.Context, userID string) ([]models.Account, error) {
+// FindByUserID returns the user's accounts; closed accounts only when
+// includeClosed is set.
+func (r *AccountRepository) FindByUserID(ctx context.Context, userID string, includeClosed bool) ([]models.Account, error) {
 	var accounts []models.Account
 	query := `
-		SELECT id, user_id, currency, balance_cents, created_at, updated_at
+		SELECT id, account_number, user_id, currency, name, type, status, balance_cents, reserved_cents,
+		       credit_limit_cents, interest_rate, interest_rate_num, interest_rate_denom, interest_carry_micros,
+		       created_at, updated_at
 		FROM accounts
-		WHERE user_id = $1
-		ORDER BY currency
+		WHERE user_id = $1 AND ($2 OR status <> 'closed')
+		ORDER BY currency, name NULLS FIRST, created_at
 	`
-	err := r.db.SelectContext(ctx, &accounts, query, userID)
+	err := r.db.SelectContext(ctx, &accounts, query, userID, includeClosed)
 	if err != nil {
 		r.logger.Error("repository: failed to find accounts", "error", err, "userID", userID)
 		return nil, fmt.Errorf("repository: error finding accounts: %w", err)
@@ -83,10 +88,35 @@ func (r *AccountRepository) FindByUserID(ctx context.Context, userID string) ([]
 	return accounts, nil
 }
 
+// FindSharedWithUser returns the accounts the user is a member of without
+// being the account holder, with the user's role on each. Closed accounts are
+// included only when includeClosed is set.
+func (r *AccountRepository) FindSharedWithUser(ctx context.Context, userID string, includeClosed bool) ([]models.Account, error) {
+	var accounts []models.Account
+	query := `
+		SELECT a.id, a.account_number, a.user_id, a.currency, a.name, a.type, a.status, a.balance_cents,
+		       a.reserved_cents, a.credit_limit_cents, a.interest_rate, a.interest_rate_num, a.interest_rate_denom,
+		       a.interest_carry_micros, a.created_at, a.updated_at, m.role
+		FROM accounts a
+		JOIN account_members m ON m.account_id = a.id
+		WHERE m.user_id = $1 AND ($2 OR a.status <> 'closed')
+		ORDER BY a.currency, a.name NULLS FIRST, a.created_at
+	`
+	err := r.db.SelectContext(ctx, &accounts, query, userID, includeClosed)
+	if err != nil {
+		r.logger.Error("repository: failed to find shared accounts", "error", err, "userID", userID)
+		return nil, fmt.Errorf("repository: error finding shared accounts: %w", err)
+	}
+
+	return accounts, nil
+}
+
 func (r *AccountRepository) FindByID(ctx context.Context, id string) (*models.Account, error) {
 	var account models.Account
 	query := `
-		SELECT id, user_id, currency, balance_cents, created_at, updated_at
+		SELECT id, account_number, user_id, currency, name, type, status, balance_cents, reserved_cents,
+		       credit_limit_cents, interest_rate, interest_rate_num, interest_rate_denom, interest_carry_micros,
+		       created_at, updated_at
 		FROM accounts
 		WHERE id = $1
 	`
@@ -102,12 +132,37 @@ func (r *AccountRepository) FindByID(ctx context.Context, id string) (*models.Ac
 	return &account, nil
 }
 
+func (r *AccountRepository) FindByAccountNumber(ctx context.Context, number string) (*models.Account, error) {
+	var account models.Account
+	query := `
+		SELECT id, account_number, user_id, currency, name, type, status, balance_cents, reserved_cents,
+		       credit_limit_cents, interest_rate, interest_rate_num, interest_rate_denom, interest_carry_micros,
+		       created_at, updated_at
+		FROM accounts
+		WHERE account_number = $1
+	`
+	err := r.db.GetContext(ctx, &account, query, number)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorsx.ErrAccountNotFound
+		}
+		r.logger.Error("repository: failed to find account by number", "error", err)
+		return nil, fmt.Errorf("repository: error finding account: %w", err)
+	}
+
+	return &account, nil
+}
+
+// FindByUserAndCurrency returns the user's open primary (unnamed) account in
+// the currency.
 func (r *AccountRepository) FindByUserAndCurrency(ctx context.Context, userID, currency string) (*models.Account, error) {
 	var account models.Account
 	query := `
-		SELECT id, user_id, currency, balance_cents, created_at, updated_at
+		SELECT id, account_number, user_id, currency, name, type, status, balance_cents, reserved_cents,
+		       credit_limit_cents, interest_rate, interest_rate_num, interest_rate_denom, interest_carry_micros,
+		       created_at, updated_at
 		FROM accounts
-		WHERE user_id = $1 AND currency = $2
+		WHERE user_id = $1 AND currency = $2 AND name IS NULL AND status <> 'closed'
 	`
 	err := r.db.GetContext(ctx, &account, query, userID, currency)
 	if err != nil {
@@ -121,11 +176,35 @@ func (r *AccountRepository) FindByUserAndCurrency(ctx context.Context, userID, c
 	return &account, nil
 }
 
+func (r *AccountRepository) FindByUserAndName(ctx context.Context, userID, name string) (*models.Account, error) {
+	var account models.Account
+	query := `
+		SELECT id, account_number, user_id, currency, name, type, status, balance_cents, reserved_cents,
+		       credit_limit_cents, interest_rate, interest_rate_num, interest_rate_denom, interest_carry_micros,
+		       created_at, updated_at
+		FROM accounts
+		WHERE user_id = $1 AND LOWER(name) = LOWER($2) AND status <> 'closed'
+	`
+	err := r.db.GetContext(ctx, &account, query, userID, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorsx.ErrAccountNotFound
+		}
+		r.logger.Error("repository: failed to find account by name", "error", err, "userID", userID)
+		return nil, fmt.Errorf("repository: error finding account: %w", err)
+	}
+
+	return &account, nil
+}
+
+// UpdateBalanceCents applies a posting to the balance. The account status is
+// enforced here so every posting path honours it: debit-frozen accounts accept
+// only credits, frozen and closed accounts accept nothing.
 func (r *AccountRepository) UpdateBalanceCents(ctx context.Context, tx *sqlx.Tx, accountID string, amountCents int64) error {
 	query := `
 		UPDATE accounts
 		SET balance_cents = balance_cents + $1, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $2
+		WHERE id = $2 AND (status = 'active' OR (status = 'debit_frozen' AND $1 > 0))
 	`
 	result, err := tx.ExecContext(ctx, query, amountCents, accountID)
 	if err != nil {
@@ -138,9 +217,170 @@ func (r *AccountRepository) UpdateBalanceCents(ctx context.Context, tx *sqlx.Tx,
 		return err
 	}
 	if rows == 0 {
-		return errorsx.ErrAccountNotFound
+		return r.statusError(ctx, tx, accountID)
+	}
+
+	return nil
+}
+
+// statusError explains why a posting matched no row.
+func (r *AccountRepository) statusError(ctx context.Context, tx *sqlx.Tx, accountID string) error {
+	var status string
+	err := tx.GetContext(ctx, &status, `SELECT status FROM accounts WHERE id = $1`, accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errorsx.ErrAccountNotFound
+		}
+		return fmt.Errorf("repository: error getting account status: %w", err)
+	}
+	if status == models.AccountStatusClosed {
+		return errorsx.ErrAccountClosed
+	}
+	return errorsx.ErrAccountFrozen
+}
+
+func (r *AccountRepository) UpdateStatus(ctx context.Context, tx *sqlx.Tx, change *models.AccountStatusChange) error {
+	if _, err := tx.ExecContext(ctx, `UPDATE accounts SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`,
+		change.ToStatus, change.AccountID); err != nil {
+		r.logger.Error("repository: failed to update account status", "error", err, "accountID", change.AccountID)
+		return fmt.Errorf("repository: error updating account status: %w", err)
+	}
+
+	query := `
+		INSERT INTO account_status_changes (account_id, from_status, to_status, reason, changed_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+	err := tx.QueryRowContext(ctx, query, change.AccountID, change.FromStatus, change.ToStatus, change.Reason, change.ChangedBy).
+		Scan(&change.ID, &change.CreatedAt)
+	if err != nil {
+		r.logger.Error("repository: failed to record account status change", "error", err, "accountID", change.AccountID)
+		return fmt.Errorf("repository: error recording account status change: %w", err)
+	}
+
+	r.logger.Info("repository: account status changed", "accountID", change.AccountID, "from", change.FromStatus, "to", change.ToStatus)
+	return nil
+}
+
+func (r *AccountRepository) UpdateCreditLimit(ctx context.Context, tx *sqlx.Tx, accountID string, creditLimitCents int64) error {
+	query := `UPDATE accounts SET credit_limit_cents = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+	if _, err := tx.ExecContext(ctx, query, creditLimitCents, accountID); err != nil {
+		r.logger.Error("repository: failed to update credit limit", "error", err, "accountID", accountID)
+		return fmt.Errorf("repository: error updating credit limit: %w", err)
+	}
+	return nil
+}
+
+func (r *AccountRepository) UpdateInterestRate(ctx context.Context, account *models.Account) error {
+	query := `
+		UPDATE accounts
+		SET interest_rate = $1, interest_rate_num = $2, interest_rate_denom = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4
+	`
+	if _, err := r.db.ExecContext(ctx, query, account.InterestRate, account.InterestRateNum, account.InterestRateDenom, account.ID); err != nil {
+		r.logger.Error("repository: failed to update interest rate", "error", err, "accountID", account.ID)
+		return fmt.Errorf("repository: error updating interest rate: %w", err)
+	}
+	return nil
+}
+
+func (r *AccountRepository) UpdateInterestCarry(ctx context.Context, tx *sqlx.Tx, accountID string, carryMicros int64) error {
+	query := `UPDATE accounts SET interest_carry_micros = $1 WHERE id = $2`
+	if _, err := tx.ExecContext(ctx, query, carryMicros, accountID); err != nil {
+		r.logger.Error("repository: failed to update interest carry", "error", err, "accountID", accountID)
+		return fmt.Errorf("repository: error updating interest carry: %w", err)
+	}
+	return nil
+}
+
+func (r *AccountRepository) FindStatusChanges(ctx context.Context, accountID string) ([]models.AccountStatusChange, error) {
+	changes := []models.AccountStatusChange{}
+	query := `
+		SELECT id, account_id, from_status, to_status, reason, changed_by, created_at
+		FROM account_status_changes
+		WHERE account_id = $1
+		ORDER BY created_at, id
+	`
+	if err := r.db.SelectContext(ctx, &changes, query, accountID); err != nil {
+		r.logger.Error("repository: failed to find account status changes", "error", err, "accountID", accountID)
+		return nil, fmt.Errorf("repository: error finding account status changes: %w", err)
+	}
+	return changes, nil
+}
+
+// FindMemberRole returns the user's role on the account from its member
+// list; the account holder is not listed there.
+func (r *AccountRepository) FindMemberRole(ctx context.Context, accountID, userID string) (string, error) {
+	var role string
+	query := `SELECT role FROM account_members WHERE account_id = $1 AND user_id = $2`
+	err := r.db.GetContext(ctx, &role, query, accountID, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", errorsx.ErrAccountNotFound
+		}
+		r.logger.Error("repository: failed to find account member", "error", err, "accountID", accountID, "userID", userID)
+		return "", fmt.Errorf("repository: error finding account member: %w", err)
+	}
+	return role, nil
+}
+
+func (r *AccountRepository) FindMembers(ctx context.Context, accountID string) ([]models.AccountMember, error) {
+	members := []models.AccountMember{}
+	query := `
+		SELECT m.account_id, m.user_id, u.email, m.role, m.added_by, m.created_at
+		FROM account_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.account_id = $1
+		ORDER BY m.created_at, u.email
+	`
+	if err := r.db.SelectContext(ctx, &members, query, accountID); err != nil {
+		r.logger.Error("repository: failed to find account members", "error", err, "accountID", accountID)
+		return nil, fmt.Errorf("repository: error finding account members: %w", err)
+	}
+	return members, nil
+}
+
+// SaveMember adds a member or changes the role of an existing one.
+func (r *AccountRepository) SaveMember(ctx context.Context, member *models.AccountMember) error {
+	query := `
+		INSERT INTO account_members (account_id, user_id, role, added_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (account_id, user_id) DO UPDATE SET role = EXCLUDED.role, updated_at = CURRENT_TIMESTAMP
+		RETURNING added_by, created_at
+	`
+	err := r.db.QueryRowContext(ctx, query, member.AccountID, member.UserID, member.Role, member.AddedBy).
+		Scan(&member.AddedBy, &member.CreatedAt)
+	if err != nil {
+		r.logger.Error("repository: failed to save account member", "error", err, "accountID", member.AccountID, "userID", member.UserID)
+		return fmt.Errorf("repository: error saving account member: %w", err)
+	}
+	return nil
+}
+
+// DeleteMembersInTx removes every member of an account, for accounts that are
+// being closed. It returns the number removed.
+func (r *AccountRepository) DeleteMembersInTx(ctx context.Context, tx *sqlx.Tx, accountID string) (int64, error) {
+	result, err := tx.ExecContext(ctx, `DELETE FROM account_members WHERE account_id = $1`, accountID)
+	if err != nil {
+		r.logger.Error("repository: failed to delete account members", "error", err, "accountID", accountID)
+		return 0, fmt.Errorf("repository: error deleting account members: %w", err)
 	}
+	return result.RowsAffected()
+}
 
+func (r *AccountRepository) DeleteMember(ctx context.Context, accountID, userID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM account_members WHERE account_id = $1 AND user_id = $2`, accountID, userID)
+	if err != nil {
+		r.logger.Error("repository: failed to delete account member", "error", err, "accountID", accountID, "userID", userID)
+		return fmt.Errorf("repository: error deleting account member: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errorsx.ErrUserNotFound
+	}
 	return nil
 }
 
@@ -189,6 +429,28 @@ func (r *AccountRepository) LockAccountsForUpdate(ctx context.Context, tx *sqlx.
 	return nil
 }
 
+func (r *AccountRepository) FindByIDForUpdate(ctx context.Context, tx *sqlx.Tx, id string) (*models.Account, error) {
+	var account models.Account
+	query := `
+		SELECT id, account_number, user_id, currency, name, type, status, balance_cents, reserved_cents,
+		       credit_limit_cents, interest_rate, interest_rate_num, interest_rate_denom, interest_carry_micros,
+		       created_at, updated_at
+		FROM accounts
+		WHERE id = $1
+		FOR UPDATE
+	`
+	err := tx.GetContext(ctx, &account, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorsx.ErrAccountNotFound
+		}
+		r.logger.Error("repository: failed to find account for update", "error", err, "accountID", id)
+		return nil, fmt.Errorf("repository: error finding account: %w", err)
+	}
+
+	return &account, nil
+}
+
 func (r *AccountRepository) GetBalanceCentsForUpdate(ctx context.Context, tx *sqlx.Tx, accountID string) (int64, error) {
 	var balanceCents int64
 	query := `SELECT balance_cents FROM accounts WHERE id = $1 FOR UPDATE`
@@ -211,6 +473,64 @@ func (r *AccountRepository) GetBalanceCents(ctx context.Context, tx *sqlx.Tx, ac
 	return balanceCents, nil
 }
 
+// GetAvailableCents returns what can still be spent: the balance not reserved
+// by open orders plus the unused credit limit.
+func (r *AccountRepository) GetAvailableCents(ctx context.Context, tx *sqlx.Tx, accountID string) (int64, error) {
+	var availableCents int64
+	query := `
+		SELECT CASE WHEN credit_limit_cents IS NULL THEN 9223372036854775807
+		            ELSE balance_cents - reserved_cents + credit_limit_cents END
+		FROM accounts
+		WHERE id = $1
+	`
+	err := tx.GetContext(ctx, &availableCents, query, accountID)
+	if err != nil {
+		r.logger.Error("repository: failed to get available balance", "error", err, "accountID", accountID)
+		return 0, fmt.Errorf("repository: error getting available balance: %w", err)
+	}
+	return availableCents, nil
+}
+
+func (r *AccountRepository) UpdateReservedCents(ctx context.Context, tx *sqlx.Tx, accountID string, amountCents int64) error {
+	query := `
+		UPDATE accounts
+		SET reserved_cents = reserved_cents + $1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2
+	`
+	result, err := tx.ExecContext(ctx, query, amountCents, accountID)
+	if err != nil {
+		r.logger.Error("repository: failed to update reserved amount", "error", err, "accountID", accountID)
+		return fmt.Errorf("repository: error updating reserved amount: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errorsx.ErrAccountNotFound
+	}
+
+	return nil
+}
+
+func (r *AccountRepository) FindOrCreateSystemAccount(ctx context.Context, userID, currency string, unlimitedCredit bool) (*models.Account, error) {
+	query := `
+		INSERT INTO accounts (user_id, currency, balance_cents, credit_limit_cents)
+		VALUES ($1, $2, 0, CASE WHEN $3 THEN NULL ELSE 0 END)
+		ON CONFLICT (user_id, currency) WHERE name IS NULL AND status <> 'closed' DO NOTHING
+	`
+	if _, err := r.db.ExecContext(ctx, query, userID, currency, unlimitedCredit); err != nil {
+		r.logger.Error("repository: failed to ensure system account", "error", err, "userID", userID, "currency", currency)
+		return nil, fmt.Errorf("repository: error ensuring system account: %w", err)
+	}
+	return r.FindByUserAndCurrency(ctx, userID, currency)
+}
+
 func (r *AccountRepository) FindFXAccountByCurrency(ctx context.Context, currency string) (*models.Account, error) {
-	return r.FindByUserAndCurrency(ctx, models.FXSystemUserID, currency)
+	return r.FindOrCreateSystemAccount(ctx, models.FXSystemUserID, currency, true)
+}
+
+func (r *AccountRepository) FindRevenueAccountByCurrency(ctx context.Context, currency string) (*models.Account, error) {
+	return r.FindOrCreateSystemAccount(ctx, models.RevenueSystemUserID, currency, false)
 }
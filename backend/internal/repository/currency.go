@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type CurrencyRepository struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+func NewCurrencyRepository(db *sqlx.DB, logger *slog.Logger) *CurrencyRepository {
+	return &CurrencyRepository{db: db, logger: logger}
+}
+
+func (r *CurrencyRepository) FindAll(ctx context.Context) ([]models.Currency, error) {
+	var currencies []models.Currency
+	query := `
+		SELECT code, minor_units, symbol, enabled, created_at, updated_at
+		FROM currencies
+		ORDER BY code
+	`
+	err := r.db.SelectContext(ctx, &currencies, query)
+	if err != nil {
+		r.logger.Error("repository: failed to find currencies", "error", err)
+		return nil, fmt.Errorf("repository: error finding currencies: %w", err)
+	}
+
+	return currencies, nil
+}
+
+func (r *CurrencyRepository) FindEnabled(ctx context.Context) ([]models.Currency, error) {
+	var currencies []models.Currency
+	query := `
+		SELECT code, minor_units, symbol, enabled, created_at, updated_at
+		FROM currencies
+		WHERE enabled = TRUE
+		ORDER BY code
+	`
+	err := r.db.SelectContext(ctx, &currencies, query)
+	if err != nil {
+		r.logger.Error("repository: failed to find enabled currencies", "error", err)
+		return nil, fmt.Errorf("repository: error finding currencies: %w", err)
+	}
+
+	return currencies, nil
+}
+
+func (r *CurrencyRepository) FindByCode(ctx context.Context, code string) (*models.Currency, error) {
+	var currency models.Currency
+	query := `
+		SELECT code, minor_units, symbol, enabled, created_at, updated_at
+		FROM currencies
+		WHERE code = $1
+	`
+	err := r.db.GetContext(ctx, &currency, query, code)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorsx.ErrInvalidCurrency
+		}
+		r.logger.Error("repository: failed to find currency", "error", err, "code", code)
+		return nil, fmt.Errorf("repository: error finding currency: %w", err)
+	}
+
+	return &currency, nil
+}
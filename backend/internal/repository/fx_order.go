@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const fxOrderColumns = `id, user_id, from_currency, to_currency, amount_cents, target_rate, target_rate_num, target_rate_denom,
+		       status, expires_at, transaction_id, failure_reason, created_at, updated_at`
+
+type FXOrderRepository struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+func NewFXOrderRepository(db *sqlx.DB, logger *slog.Logger) *FXOrderRepository {
+	return &FXOrderRepository{db: db, logger: logger}
+}
+
+func (r *FXOrderRepository) Create(ctx context.Context, tx *sqlx.Tx, order *models.FXOrder) error {
+	query := `
+		INSERT INTO fx_orders (user_id, from_currency, to_currency, amount_cents, target_rate,
+		                       target_rate_num, target_rate_denom, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at
+	`
+	err := tx.QueryRowContext(ctx, query,
+		order.UserID,
+		order.FromCurrency,
+		order.ToCurrency,
+		order.AmountCents,
+		order.TargetRate,
+		order.TargetRateNum,
+		order.TargetRateDenom,
+		order.Status,
+		order.ExpiresAt,
+	).Scan(&order.ID, &order.CreatedAt, &order.UpdatedAt)
+
+	if err != nil {
+		r.logger.Error("repository: failed to create fx order", "error", err, "userID", order.UserID)
+		return fmt.Errorf("repository: error creating fx order: %w", err)
+	}
+
+	r.logger.Info("repository: fx order created", "orderID", order.ID, "userID", order.UserID)
+	return nil
+}
+
+func (r *FXOrderRepository) FindByIDForUpdate(ctx context.Context, tx *sqlx.Tx, id string) (*models.FXOrder, error) {
+	var order models.FXOrder
+	query := `SELECT ` + fxOrderColumns + ` FROM fx_orders WHERE id = $1 FOR UPDATE`
+	err := tx.GetContext(ctx, &order, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorsx.ErrOrderNotFound
+		}
+		r.logger.Error("repository: failed to find fx order", "error", err, "orderID", id)
+		return nil, fmt.Errorf("repository: error finding fx order: %w", err)
+	}
+
+	return &order, nil
+}
+
+func (r *FXOrderRepository) FindByUser(ctx context.Context, userID, status string) ([]models.FXOrder, error) {
+	orders := []models.FXOrder{}
+	query := `
+		SELECT ` + fxOrderColumns + `
+		FROM fx_orders
+		WHERE user_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC
+	`
+	err := r.db.SelectContext(ctx, &orders, query, userID, status)
+	if err != nil {
+		r.logger.Error("repository: failed to find fx orders", "error", err, "userID", userID)
+		return nil, fmt.Errorf("repository: error finding fx orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+// FindOpenIDs returns open orders oldest first, so earlier orders fill first
+// when several become executable on the same rate.
+func (r *FXOrderRepository) FindOpenIDs(ctx context.Context) ([]string, error) {
+	var ids []string
+	query := `SELECT id FROM fx_orders WHERE status = 'open' ORDER BY created_at, id`
+	err := r.db.SelectContext(ctx, &ids, query)
+	if err != nil {
+		r.logger.Error("repository: failed to find open fx orders", "error", err)
+		return nil, fmt.Errorf("repository: error finding open fx orders: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (r *FXOrderRepository) UpdateStatus(ctx context.Context, tx *sqlx.Tx, order *models.FXOrder) error {
+	query := `
+		UPDATE fx_orders
+		SET status = $1, transaction_id = $2, failure_reason = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4
+		RETURNING updated_at
+	`
+	err := tx.QueryRowContext(ctx, query, order.Status, order.TransactionID, order.FailureReason, order.ID).Scan(&order.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errorsx.ErrOrderNotFound
+		}
+		r.logger.Error("repository: failed to update fx order", "error", err, "orderID", order.ID)
+		return fmt.Errorf("repository: error updating fx order: %w", err)
+	}
+
+	return nil
+}
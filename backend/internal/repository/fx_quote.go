@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type FXQuoteRepository struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+func NewFXQuoteRepository(db *sqlx.DB, logger *slog.Logger) *FXQuoteRepository {
+	return &FXQuoteRepository{db: db, logger: logger}
+}
+
+func (r *FXQuoteRepository) Create(ctx context.Context, quote *models.FXQuote) error {
+	query := `
+		INSERT INTO fx_quotes (user_id, from_currency, to_currency, from_amount_cents, to_amount_cents,
+		                       fee_cents, rounding_mode, fx_rate_id, rate_num, rate_denom, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, created_at
+	`
+	err := r.db.QueryRowContext(ctx, query,
+		quote.UserID,
+		quote.FromCurrency,
+		quote.ToCurrency,
+		quote.FromAmountCents,
+		quote.ToAmountCents,
+		quote.FeeCents,
+		quote.RoundingMode,
+		quote.FXRateID,
+		quote.RateNum,
+		quote.RateDenom,
+		quote.ExpiresAt,
+	).Scan(&quote.ID, &quote.CreatedAt)
+
+	if err != nil {
+		r.logger.Error("repository: failed to create fx quote", "error", err, "userID", quote.UserID)
+		return fmt.Errorf("repository: error creating fx quote: %w", err)
+	}
+
+	r.logger.Info("repository: fx quote created", "quoteID", quote.ID, "userID", quote.UserID)
+	return nil
+}
+
+func (r *FXQuoteRepository) FindByIDForUpdate(ctx context.Context, tx *sqlx.Tx, id string) (*models.FXQuote, error) {
+	var quote models.FXQuote
+	query := `
+		SELECT id, user_id, from_currency, to_currency, from_amount_cents, to_amount_cents,
+		       fee_cents, rounding_mode, fx_rate_id, rate_num, rate_denom, expires_at, transaction_id, used_at, created_at
+		FROM fx_quotes
+		WHERE id = $1
+		FOR UPDATE
+	`
+	err := tx.GetContext(ctx, &quote, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorsx.ErrQuoteNotFound
+		}
+		r.logger.Error("repository: failed to find fx quote", "error", err, "quoteID", id)
+		return nil, fmt.Errorf("repository: error finding fx quote: %w", err)
+	}
+
+	return &quote, nil
+}
+
+func (r *FXQuoteRepository) MarkUsed(ctx context.Context, tx *sqlx.Tx, id, transactionID string) error {
+	query := `
+		UPDATE fx_quotes
+		SET transaction_id = $1, used_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND used_at IS NULL
+	`
+	result, err := tx.ExecContext(ctx, query, transactionID, id)
+	if err != nil {
+		r.logger.Error("repository: failed to mark fx quote used", "error", err, "quoteID", id)
+		return fmt.Errorf("repository: error marking fx quote used: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errorsx.ErrQuoteUsed
+	}
+
+	return nil
+}
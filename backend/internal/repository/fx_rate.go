@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/models"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type FXRateRepository struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+func NewFXRateRepository(db *sqlx.DB, logger *slog.Logger) *FXRateRepository {
+	return &FXRateRepository{db: db, logger: logger}
+}
+
+func (r *FXRateRepository) Create(ctx context.Context, rate *models.FXRate) error {
+	query := `
+		INSERT INTO fx_rates (base_currency, quote_currency, rate_num, rate_denom, valid_from, source)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+	err := r.db.QueryRowContext(ctx, query,
+		rate.BaseCurrency,
+		rate.QuoteCurrency,
+		rate.RateNum,
+		rate.RateDenom,
+		rate.ValidFrom,
+		rate.Source,
+	).Scan(&rate.ID, &rate.CreatedAt)
+
+	if err != nil {
+		r.logger.Error("repository: failed to create fx rate", "error", err, "base", rate.BaseCurrency, "quote", rate.QuoteCurrency)
+		return fmt.Errorf("repository: error creating fx rate: %w", err)
+	}
+
+	r.logger.Info("repository: fx rate created", "rateID", rate.ID, "base", rate.BaseCurrency, "quote", rate.QuoteCurrency)
+	return nil
+}
+
+// CreateReferenceIfMissing stores an imported historical rate in
+// fx_reference_rates unless the same pair, valid_from and source is already
+// there, so imports can be re-run safely. Reference rates are kept apart from
+// fx_rates and are never used for live pricing.
+func (r *FXRateRepository) CreateReferenceIfMissing(ctx context.Context, tx *sqlx.Tx, rate *models.FXRate) (bool, error) {
+	query := `
+		INSERT INTO fx_reference_rates (base_currency, quote_currency, rate_num, rate_denom, valid_from, source)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (base_currency, quote_currency, valid_from, source) DO NOTHING
+		RETURNING id, created_at
+	`
+	err := tx.QueryRowContext(ctx, query,
+		rate.BaseCurrency,
+		rate.QuoteCurrency,
+		rate.RateNum,
+		rate.RateDenom,
+		rate.ValidFrom,
+		rate.Source,
+	).Scan(&rate.ID, &rate.CreatedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		r.logger.Error("repository: failed to import fx rate", "error", err, "base", rate.BaseCurrency, "quote", rate.QuoteCurrency)
+		return false, fmt.Errorf("repository: error importing fx rate: %w", err)
+	}
+
+	return true, nil
+}
+
+// FindReferenceEffective is FindEffective over the imported reference rates.
+func (r *FXRateRepository) FindReferenceEffective(ctx context.Context, baseCurrency, quoteCurrency string, at time.Time) (*models.FXRate, error) {
+	var rate models.FXRate
+	query := `
+		SELECT id, base_currency, quote_currency, rate_num, rate_denom, valid_from, source, created_at
+		FROM fx_reference_rates
+		WHERE base_currency = $1 AND quote_currency = $2 AND valid_from <= $3
+		ORDER BY valid_from DESC, created_at DESC
+		LIMIT 1
+	`
+	err := r.db.GetContext(ctx, &rate, query, baseCurrency, quoteCurrency, at)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorsx.ErrRateUnavailable
+		}
+		r.logger.Error("repository: failed to find reference fx rate", "error", err, "base", baseCurrency, "quote", quoteCurrency)
+		return nil, fmt.Errorf("repository: error finding reference fx rate: %w", err)
+	}
+
+	return &rate, nil
+}
+
+func (r *FXRateRepository) FindEffective(ctx context.Context, baseCurrency, quoteCurrency string, at time.Time) (*models.FXRate, error) {
+	var rate models.FXRate
+	query := `
+		SELECT id, base_currency, quote_currency, rate_num, rate_denom, valid_from, source, created_at
+		FROM fx_rates
+		WHERE base_currency = $1 AND quote_currency = $2 AND valid_from <= $3
+		ORDER BY valid_from DESC, created_at DESC
+		LIMIT 1
+	`
+	err := r.db.GetContext(ctx, &rate, query, baseCurrency, quoteCurrency, at)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorsx.ErrRateUnavailable
+		}
+		r.logger.Error("repository: failed to find fx rate", "error", err, "base", baseCurrency, "quote", quoteCurrency)
+		return nil, fmt.Errorf("repository: error finding fx rate: %w", err)
+	}
+
+	return &rate, nil
+}
+
+func (r *FXRateRepository) FindByPair(ctx context.Context, baseCurrency, quoteCurrency string, limit int) ([]models.FXRate, error) {
+	var rates []models.FXRate
+	query := `
+		SELECT id, base_currency, quote_currency, rate_num, rate_denom, valid_from, source, created_at
+		FROM fx_rates
+		WHERE base_currency = $1 AND quote_currency = $2
+		ORDER BY valid_from DESC, created_at DESC
+		LIMIT $3
+	`
+	err := r.db.SelectContext(ctx, &rates, query, baseCurrency, quoteCurrency, limit)
+	if err != nil {
+		r.logger.Error("repository: failed to find fx rates", "error", err, "base", baseCurrency, "quote", quoteCurrency)
+		return nil, fmt.Errorf("repository: error finding fx rates: %w", err)
+	}
+
+	return rates, nil
+}
+
+// FindHistory returns every rate for the pair that took effect before until,
+// newest first.
+func (r *FXRateRepository) FindHistory(ctx context.Context, baseCurrency, quoteCurrency string, until time.Time) ([]models.FXRate, error) {
+	var rates []models.FXRate
+	query := `
+		SELECT id, base_currency, quote_currency, rate_num, rate_denom, valid_from, source, created_at
+		FROM fx_rates
+		WHERE base_currency = $1 AND quote_currency = $2 AND valid_from <= $3
+		ORDER BY valid_from DESC, created_at DESC
+	`
+	err := r.db.SelectContext(ctx, &rates, query, baseCurrency, quoteCurrency, until)
+	if err != nil {
+		r.logger.Error("repository: failed to find fx rate history", "error", err, "base", baseCurrency, "quote", quoteCurrency)
+		return nil, fmt.Errorf("repository: error finding fx rate history: %w", err)
+	}
+
+	return rates, nil
+}
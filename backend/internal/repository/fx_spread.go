@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"mini-banking-platform/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type FXSpreadRepository struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+func NewFXSpreadRepository(db *sqlx.DB, logger *slog.Logger) *FXSpreadRepository {
+	return &FXSpreadRepository{db: db, logger: logger}
+}
+
+func (r *FXSpreadRepository) Upsert(ctx context.Context, spread *models.FXSpread) error {
+	query := `
+		INSERT INTO fx_spreads (base_currency, quote_currency, bid_bps, ask_bps)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (base_currency, quote_currency)
+		DO UPDATE SET bid_bps = EXCLUDED.bid_bps, ask_bps = EXCLUDED.ask_bps, updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at
+	`
+	err := r.db.QueryRowContext(ctx, query, spread.BaseCurrency, spread.QuoteCurrency, spread.BidBps, spread.AskBps).
+		Scan(&spread.UpdatedAt)
+	if err != nil {
+		r.logger.Error("repository: failed to upsert fx spread", "error", err, "base", spread.BaseCurrency, "quote", spread.QuoteCurrency)
+		return fmt.Errorf("repository: error saving fx spread: %w", err)
+	}
+
+	return nil
+}
+
+func (r *FXSpreadRepository) FindAll(ctx context.Context) ([]models.FXSpread, error) {
+	var spreads []models.FXSpread
+	query := `
+		SELECT base_currency, quote_currency, bid_bps, ask_bps, updated_at
+		FROM fx_spreads
+		ORDER BY base_currency, quote_currency
+	`
+	err := r.db.SelectContext(ctx, &spreads, query)
+	if err != nil {
+		r.logger.Error("repository: failed to find fx spreads", "error", err)
+		return nil, fmt.Errorf("repository: error finding fx spreads: %w", err)
+	}
+
+	return spreads, nil
+}
+
+// FindByPair returns nil without error when no spread is configured.
+func (r *FXSpreadRepository) FindByPair(ctx context.Context, baseCurrency, quoteCurrency string) (*models.FXSpread, error) {
+	var spread models.FXSpread
+	query := `
+		SELECT base_currency, quote_currency, bid_bps, ask_bps, updated_at
+		FROM fx_spreads
+		WHERE base_currency = $1 AND quote_currency = $2
+	`
+	err := r.db.GetContext(ctx, &spread, query, baseCurrency, quoteCurrency)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("repository: failed to find fx spread", "error", err, "base", baseCurrency, "quote", quoteCurrency)
+		return nil, fmt.Errorf("repository: error finding fx spread: %w", err)
+	}
+
+	return &spread, nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/models"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const holdColumns = `id, account_id, payee_account_id, currency, amount_cents, captured_cents, description, status,
+		       expires_at, transaction_id, created_at, updated_at`
+
+type HoldRepository struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+func NewHoldRepository(db *sqlx.DB, logger *slog.Logger) *HoldRepository {
+	return &HoldRepository{db: db, logger: logger}
+}
+
+func (r *HoldRepository) Create(ctx context.Context, tx *sqlx.Tx, hold *models.Hold) error {
+	query := `
+		INSERT INTO holds (account_id, payee_account_id, currency, amount_cents, description, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at
+	`
+	err := tx.QueryRowContext(ctx, query,
+		hold.AccountID,
+		hold.PayeeAccountID,
+		hold.Currency,
+		hold.AmountCents,
+		hold.Description,
+		hold.Status,
+		hold.ExpiresAt,
+	).Scan(&hold.ID, &hold.CreatedAt, &hold.UpdatedAt)
+
+	if err != nil {
+		r.logger.Error("repository: failed to create hold", "error", err, "accountID", hold.AccountID)
+		return fmt.Errorf("repository: error creating hold: %w", err)
+	}
+
+	r.logger.Info("repository: hold created", "holdID", hold.ID, "accountID", hold.AccountID)
+	return nil
+}
+
+func (r *HoldRepository) FindByIDForUpdate(ctx context.Context, tx *sqlx.Tx, id string) (*models.Hold, error) {
+	var hold models.Hold
+	query := `SELECT ` + holdColumns + ` FROM holds WHERE id = $1 FOR UPDATE`
+	err := tx.GetContext(ctx, &hold, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorsx.ErrHoldNotFound
+		}
+		r.logger.Error("repository: failed to find hold", "error", err, "holdID", id)
+		return nil, fmt.Errorf("repository: error finding hold: %w", err)
+	}
+
+	return &hold, nil
+}
+
+// FindByUser returns holds on the accounts the user holds or is a member of,
+// and holds in favour of those accounts, newest first.
+func (r *HoldRepository) FindByUser(ctx context.Context, userID, status string) ([]models.Hold, error) {
+	holds := []models.Hold{}
+	query := `
+		WITH user_accounts AS (
+			SELECT id FROM accounts WHERE user_id = $1
+			UNION
+			SELECT account_id FROM account_members WHERE user_id = $1
+		)
+		SELECT ` + holdColumns + `
+		FROM holds
+		WHERE (account_id IN (SELECT id FROM user_accounts)
+		       OR payee_account_id IN (SELECT id FROM user_accounts))
+		  AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC
+	`
+	err := r.db.SelectContext(ctx, &holds, query, userID, status)
+	if err != nil {
+		r.logger.Error("repository: failed to find holds", "error", err, "userID", userID)
+		return nil, fmt.Errorf("repository: error finding holds: %w", err)
+	}
+
+	return holds, nil
+}
+
+func (r *HoldRepository) FindExpiredIDs(ctx context.Context, now time.Time) ([]string, error) {
+	var ids []string
+	query := `SELECT id FROM holds WHERE status = 'active' AND expires_at <= $1 ORDER BY expires_at, id`
+	err := r.db.SelectContext(ctx, &ids, query, now)
+	if err != nil {
+		r.logger.Error("repository: failed to find expired holds", "error", err)
+		return nil, fmt.Errorf("repository: error finding expired holds: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (r *HoldRepository) UpdateStatus(ctx context.Context, tx *sqlx.Tx, hold *models.Hold) error {
+	query := `
+		UPDATE holds
+		SET status = $1, captured_cents = $2, transaction_id = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4
+		RETURNING updated_at
+	`
+	err := tx.QueryRowContext(ctx, query, hold.Status, hold.CapturedCents, hold.TransactionID, hold.ID).Scan(&hold.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errorsx.ErrHoldNotFound
+		}
+		r.logger.Error("repository: failed to update hold", "error", err, "holdID", hold.ID)
+		return fmt.Errorf("repository: error updating hold: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"mini-banking-platform/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type IdempotencyRepository struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+func NewIdempotencyRepository(db *sqlx.DB, logger *slog.Logger) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db, logger: logger}
+}
+
+// Find returns nil without error when the user has not used the key.
+func (r *IdempotencyRepository) Find(ctx context.Context, userID, key string) (*models.IdempotencyKey, error) {
+	var stored models.IdempotencyKey
+	query := `
+		SELECT user_id, idempotency_key, endpoint, request_hash, transaction_id, created_at
+		FROM idempotency_keys
+		WHERE user_id = $1 AND idempotency_key = $2
+	`
+	if err := r.db.GetContext(ctx, &stored, query, userID, key); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("repository: failed to find idempotency key", "error", err, "userID", userID)
+		return nil, fmt.Errorf("repository: error finding idempotency key: %w", err)
+	}
+	return &stored, nil
+}
+
+// CreateInTx stores the key with its transaction in the posting's DB
+// transaction. It reports false when another request has already stored the
+// key; a concurrent request holding it blocks the insert until it finishes.
+func (r *IdempotencyRepository) CreateInTx(ctx context.Context, tx *sqlx.Tx, key *models.IdempotencyKey) (bool, error) {
+	query := `
+		INSERT INTO idempotency_keys (user_id, idempotency_key, endpoint, request_hash, transaction_id)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, idempotency_key) DO NOTHING
+		RETURNING created_at
+	`
+	err := tx.QueryRowContext(ctx, query, key.UserID, key.Key, key.Endpoint, key.RequestHash, key.TransactionID).Scan(&key.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		r.logger.Error("repository: failed to store idempotency key", "error", err, "userID", key.UserID)
+		return false, fmt.Errorf("repository: error storing idempotency key: %w", err)
+	}
+	return true, nil
+}
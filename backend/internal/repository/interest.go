@@ -0,0 +1,171 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"mini-banking-platform/internal/models"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type InterestRepository struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+func NewInterestRepository(db *sqlx.DB, logger *slog.Logger) *InterestRepository {
+	return &InterestRepository{db: db, logger: logger}
+}
+
+// FindAccrualCandidates returns the savings accounts that have not accrued
+// for day yet, with the ledger balance as of the end of that day.
+func (r *InterestRepository) FindAccrualCandidates(ctx context.Context, day time.Time) ([]models.InterestCandidate, error) {
+	var candidates []models.InterestCandidate
+	query := `
+		SELECT a.id AS account_id, a.interest_rate_num, a.interest_rate_denom,
+		       COALESCE(SUM(le.amount_cents), 0) AS balance_cents
+		FROM accounts a
+		LEFT JOIN ledger_entries le ON le.account_id = a.id AND le.created_at < $2
+		WHERE a.type = 'savings'
+		  AND a.status <> 'closed'
+		  AND a.interest_rate_num > 0
+		  AND a.created_at < $2
+		  AND NOT EXISTS (
+		      SELECT 1 FROM interest_accruals ia WHERE ia.account_id = a.id AND ia.accrual_date = $1
+		  )
+		GROUP BY a.id
+		ORDER BY a.id
+	`
+	dayEnd := day.AddDate(0, 0, 1)
+	if err := r.db.SelectContext(ctx, &candidates, query, day, dayEnd); err != nil {
+		r.logger.Error("repository: failed to find interest accrual candidates", "error", err, "day", day)
+		return nil, fmt.Errorf("repository: error finding interest accrual candidates: %w", err)
+	}
+	return candidates, nil
+}
+
+// FindAccountAccrualCandidate is FindAccrualCandidates for a single account
+// inside tx. It returns nil when the account has already accrued for day or
+// earns no interest.
+func (r *InterestRepository) FindAccountAccrualCandidate(ctx context.Context, tx *sqlx.Tx, accountID string, day time.Time) (*models.InterestCandidate, error) {
+	var candidates []models.InterestCandidate
+	query := `
+		SELECT a.id AS account_id, a.interest_rate_num, a.interest_rate_denom,
+		       COALESCE(SUM(le.amount_cents), 0) AS balance_cents
+		FROM accounts a
+		LEFT JOIN ledger_entries le ON le.account_id = a.id AND le.created_at < $2
+		WHERE a.id = $3
+		  AND a.type = 'savings'
+		  AND a.interest_rate_num > 0
+		  AND a.created_at < $2
+		  AND NOT EXISTS (
+		      SELECT 1 FROM interest_accruals ia WHERE ia.account_id = a.id AND ia.accrual_date = $1
+		  )
+		GROUP BY a.id
+	`
+	dayEnd := day.AddDate(0, 0, 1)
+	if err := tx.SelectContext(ctx, &candidates, query, day, dayEnd, accountID); err != nil {
+		r.logger.Error("repository: failed to find interest accrual candidate", "error", err, "accountID", accountID, "day", day)
+		return nil, fmt.Errorf("repository: error finding interest accrual candidate: %w", err)
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	return &candidates[0], nil
+}
+
+// CreateAccrual records one day of interest. It reports false when the
+// account already has an accrual for that day.
+func (r *InterestRepository) CreateAccrual(ctx context.Context, accrual *models.InterestAccrual) (bool, error) {
+	query := `
+		INSERT INTO interest_accruals (account_id, accrual_date, balance_cents, rate_num, rate_denom, day_count, amount_micros)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (account_id, accrual_date) DO NOTHING
+	`
+	res, err := r.db.ExecContext(ctx, query,
+		accrual.AccountID,
+		accrual.AccrualDate,
+		accrual.BalanceCents,
+		accrual.RateNum,
+		accrual.RateDenom,
+		accrual.DayCount,
+		accrual.AmountMicros,
+	)
+	if err != nil {
+		r.logger.Error("repository: failed to create interest accrual", "error", err, "accountID", accrual.AccountID)
+		return false, fmt.Errorf("repository: error creating interest accrual: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("repository: error getting rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+func (r *InterestRepository) CreateAccrualInTx(ctx context.Context, tx *sqlx.Tx, accrual *models.InterestAccrual) error {
+	query := `
+		INSERT INTO interest_accruals (account_id, accrual_date, balance_cents, rate_num, rate_denom, day_count, amount_micros)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (account_id, accrual_date) DO NOTHING
+	`
+	_, err := tx.ExecContext(ctx, query,
+		accrual.AccountID,
+		accrual.AccrualDate,
+		accrual.BalanceCents,
+		accrual.RateNum,
+		accrual.RateDenom,
+		accrual.DayCount,
+		accrual.AmountMicros,
+	)
+	if err != nil {
+		r.logger.Error("repository: failed to create interest accrual", "error", err, "accountID", accrual.AccountID)
+		return fmt.Errorf("repository: error creating interest accrual: %w", err)
+	}
+	return nil
+}
+
+// FindAccountsWithPendingAccruals returns accounts holding accruals dated
+// before the given day that have not been capitalized yet.
+func (r *InterestRepository) FindAccountsWithPendingAccruals(ctx context.Context, before time.Time) ([]string, error) {
+	var ids []string
+	query := `
+		SELECT DISTINCT account_id
+		FROM interest_accruals
+		WHERE capitalized_at IS NULL AND accrual_date < $1
+		ORDER BY account_id
+	`
+	if err := r.db.SelectContext(ctx, &ids, query, before); err != nil {
+		r.logger.Error("repository: failed to find pending interest accruals", "error", err)
+		return nil, fmt.Errorf("repository: error finding pending interest accruals: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *InterestRepository) SumPendingAccruals(ctx context.Context, tx *sqlx.Tx, accountID string, before time.Time) (int64, error) {
+	var sum int64
+	query := `
+		SELECT COALESCE(SUM(amount_micros), 0)
+		FROM interest_accruals
+		WHERE account_id = $1 AND capitalized_at IS NULL AND accrual_date < $2
+	`
+	if err := tx.GetContext(ctx, &sum, query, accountID, before); err != nil {
+		r.logger.Error("repository: failed to sum pending interest accruals", "error", err, "accountID", accountID)
+		return 0, fmt.Errorf("repository: error summing pending interest accruals: %w", err)
+	}
+	return sum, nil
+}
+
+func (r *InterestRepository) MarkCapitalized(ctx context.Context, tx *sqlx.Tx, accountID string, before time.Time, transactionID *string) error {
+	query := `
+		UPDATE interest_accruals
+		SET capitalized_at = CURRENT_TIMESTAMP, transaction_id = $3
+		WHERE account_id = $1 AND capitalized_at IS NULL AND accrual_date < $2
+	`
+	if _, err := tx.ExecContext(ctx, query, accountID, before, transactionID); err != nil {
+		r.logger.Error("repository: failed to mark interest accruals capitalized", "error", err, "accountID", accountID)
+		return fmt.Errorf("repository: error marking interest accruals capitalized: %w", err)
+	}
+	return nil
+}
@@ -10,6 +10,18 @@ type Repositories struct {
 	User        *UserRepository
 	Account     *AccountRepository
 	Transaction *TransactionRepository
+	Currency    *CurrencyRepository
+	FXRate      *FXRateRepository
+	FXQuote     *FXQuoteRepository
+	FXSpread    *FXSpreadRepository
+	Rounding    *RoundingRepository
+	FXOrder     *FXOrderRepository
+	Interest    *InterestRepository
+	Statement   *StatementRepository
+	Hold        *HoldRepository
+	Idempotency *IdempotencyRepository
+	Scheduled   *ScheduledTransferRepository
+	Standing    *StandingOrderRepository
 }
 
 func NewRepositories(db *sqlx.DB, logger *slog.Logger) *Repositories {
@@ -17,5 +29,17 @@ func NewRepositories(db *sqlx.DB, logger *slog.Logger) *Repositories {
 		User:        NewUserRepository(db, logger),
 		Account:     NewAccountRepository(db, logger),
 		Transaction: NewTransactionRepository(db, logger),
+		Currency:    NewCurrencyRepository(db, logger),
+		FXRate:      NewFXRateRepository(db, logger),
+		FXQuote:     NewFXQuoteRepository(db, logger),
+		FXSpread:    NewFXSpreadRepository(db, logger),
+		Rounding:    NewRoundingRepository(db, logger),
+		FXOrder:     NewFXOrderRepository(db, logger),
+		Interest:    NewInterestRepository(db, logger),
+		Statement:   NewStatementRepository(db, logger),
+		Hold:        NewHoldRepository(db, logger),
+		Idempotency: NewIdempotencyRepository(db, logger),
+		Scheduled:   NewScheduledTransferRepository(db, logger),
+		Standing:    NewStandingOrderRepository(db, logger),
 	}
 }
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"mini-banking-platform/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type RoundingRepository struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+func NewRoundingRepository(db *sqlx.DB, logger *slog.Logger) *RoundingRepository {
+	return &RoundingRepository{db: db, logger: logger}
+}
+
+func (r *RoundingRepository) CreateEntry(ctx context.Context, tx *sqlx.Tx, entry *models.RoundingEntry) error {
+	query := `
+		INSERT INTO fx_rounding_entries (transaction_id, currency, rounding_mode, remainder_num, remainder_denom)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at
+	`
+	err := tx.QueryRowContext(ctx, query,
+		entry.TransactionID,
+		entry.Currency,
+		entry.RoundingMode,
+		entry.RemainderNum,
+		entry.RemainderDenom,
+	).Scan(&entry.CreatedAt)
+
+	if err != nil {
+		r.logger.Error("repository: failed to create rounding entry", "error", err, "transactionID", entry.TransactionID)
+		return fmt.Errorf("repository: error creating rounding entry: %w", err)
+	}
+
+	return nil
+}
+
+// FindEntry returns the rounding entry of an exchange, or nil when the
+// transaction did not convert anything.
+func (r *RoundingRepository) FindEntry(ctx context.Context, tx *sqlx.Tx, transactionID string) (*models.RoundingEntry, error) {
+	var entry models.RoundingEntry
+	query := `
+		SELECT transaction_id, currency, rounding_mode, remainder_num, remainder_denom, created_at
+		FROM fx_rounding_entries
+		WHERE transaction_id = $1
+	`
+	err := tx.GetContext(ctx, &entry, query, transactionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("repository: failed to find rounding entry", "error", err, "transactionID", transactionID)
+		return nil, fmt.Errorf("repository: error finding rounding entry: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// FindSuspenseForUpdate returns the locked suspense row for currency, creating
+// an empty one on first use.
+func (r *RoundingRepository) FindSuspenseForUpdate(ctx context.Context, tx *sqlx.Tx, currency string) (*models.RoundingSuspense, error) {
+	insertQuery := `
+		INSERT INTO rounding_suspense (currency)
+		VALUES ($1)
+		ON CONFLICT (currency) DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, insertQuery, currency); err != nil {
+		r.logger.Error("repository: failed to ensure rounding suspense", "error", err, "currency", currency)
+		return nil, fmt.Errorf("repository: error ensuring rounding suspense: %w", err)
+	}
+
+	var suspense models.RoundingSuspense
+	query := `
+		SELECT currency, remainder_num::text AS remainder_num, remainder_denom::text AS remainder_denom,
+		       exchange_count, updated_at
+		FROM rounding_suspense
+		WHERE currency = $1
+		FOR UPDATE
+	`
+	if err := tx.GetContext(ctx, &suspense, query, currency); err != nil {
+		r.logger.Error("repository: failed to lock rounding suspense", "error", err, "currency", currency)
+		return nil, fmt.Errorf("repository: error locking rounding suspense: %w", err)
+	}
+
+	return &suspense, nil
+}
+
+func (r *RoundingRepository) UpdateSuspense(ctx context.Context, tx *sqlx.Tx, suspense *models.RoundingSuspense) error {
+	query := `
+		UPDATE rounding_suspense
+		SET remainder_num = $2::numeric, remainder_denom = $3::numeric, exchange_count = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE currency = $1
+	`
+	_, err := tx.ExecContext(ctx, query, suspense.Currency, suspense.RemainderNum, suspense.RemainderDenom, suspense.ExchangeCount)
+	if err != nil {
+		r.logger.Error("repository: failed to update rounding suspense", "error", err, "currency", suspense.Currency)
+		return fmt.Errorf("repository: error updating rounding suspense: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RoundingRepository) FindAllSuspense(ctx context.Context) ([]models.RoundingSuspense, error) {
+	var suspense []models.RoundingSuspense
+	query := `
+		SELECT currency, remainder_num::text AS remainder_num, remainder_denom::text AS remainder_denom,
+		       exchange_count, updated_at
+		FROM rounding_suspense
+		ORDER BY currency
+	`
+	if err := r.db.SelectContext(ctx, &suspense, query); err != nil {
+		r.logger.Error("repository: failed to find rounding suspense", "error", err)
+		return nil, fmt.Errorf("repository: error finding rounding suspense: %w", err)
+	}
+
+	return suspense, nil
+}
+
+// SumEntries totals the per-exchange remainders, grouped by denominator so
+// the caller can add the fractions exactly.
+func (r *RoundingRepository) SumEntries(ctx context.Context) ([]models.RoundingRemainderSum, error) {
+	var sums []models.RoundingRemainderSum
+	query := `
+		SELECT currency, SUM(remainder_num)::text AS remainder_num, remainder_denom, COUNT(*) AS entry_count
+		FROM fx_rounding_entries
+		GROUP BY currency, remainder_denom
+		ORDER BY currency
+	`
+	if err := r.db.SelectContext(ctx, &sums, query); err != nil {
+		r.logger.Error("repository: failed to sum rounding entries", "error", err)
+		return nil, fmt.Errorf("repository: error summing rounding entries: %w", err)
+	}
+
+	return sums, nil
+}
@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/models"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const scheduledTransferColumns = `id, user_id, to_user_id, to_account_id, to_account_number, from_account_id, currency,
+		       to_currency, amount_cents, execute_at, status, failure_reason, transaction_id, executed_at,
+		       created_at, updated_at`
+
+type ScheduledTransferRepository struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+func NewScheduledTransferRepository(db *sqlx.DB, logger *slog.Logger) *ScheduledTransferRepository {
+	return &ScheduledTransferRepository{db: db, logger: logger}
+}
+
+func (r *ScheduledTransferRepository) Create(ctx context.Context, transfer *models.ScheduledTransfer) error {
+	query := `
+		INSERT INTO scheduled_transfers (user_id, to_user_id, to_account_id, to_account_number, from_account_id,
+		                                 currency, to_currency, amount_cents, execute_at, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at, updated_at
+	`
+	err := r.db.QueryRowContext(ctx, query,
+		transfer.UserID,
+		transfer.ToUserID,
+		transfer.ToAccountID,
+		transfer.ToAccountNumber,
+		transfer.FromAccountID,
+		transfer.Currency,
+		transfer.ToCurrency,
+		transfer.AmountCents,
+		transfer.ExecuteAt,
+		transfer.Status,
+	).Scan(&transfer.ID, &transfer.CreatedAt, &transfer.UpdatedAt)
+
+	if err != nil {
+		r.logger.Error("repository: failed to create scheduled transfer", "error", err, "userID", transfer.UserID)
+		return fmt.Errorf("repository: error creating scheduled transfer: %w", err)
+	}
+
+	r.logger.Info("repository: scheduled transfer created", "scheduleID", transfer.ID, "executeAt", transfer.ExecuteAt)
+	return nil
+}
+
+func (r *ScheduledTransferRepository) FindByID(ctx context.Context, id string) (*models.ScheduledTransfer, error) {
+	var transfer models.ScheduledTransfer
+	query := `SELECT ` + scheduledTransferColumns + ` FROM scheduled_transfers WHERE id = $1`
+	err := r.db.GetContext(ctx, &transfer, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorsx.ErrScheduleNotFound
+		}
+		r.logger.Error("repository: failed to find scheduled transfer", "error", err, "scheduleID", id)
+		return nil, fmt.Errorf("repository: error finding scheduled transfer: %w", err)
+	}
+
+	return &transfer, nil
+}
+
+// FindByUser returns the user's scheduled transfers, next due first.
+func (r *ScheduledTransferRepository) FindByUser(ctx context.Context, userID, status string) ([]models.ScheduledTransfer, error) {
+	transfers := []models.ScheduledTransfer{}
+	query := `
+		SELECT ` + scheduledTransferColumns + `
+		FROM scheduled_transfers
+		WHERE user_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY execute_at, created_at
+	`
+	err := r.db.SelectContext(ctx, &transfers, query, userID, status)
+	if err != nil {
+		r.logger.Error("repository: failed to find scheduled transfers", "error", err, "userID", userID)
+		return nil, fmt.Errorf("repository: error finding scheduled transfers: %w", err)
+	}
+
+	return transfers, nil
+}
+
+func (r *ScheduledTransferRepository) FindDueIDs(ctx context.Context, now time.Time) ([]string, error) {
+	var ids []string
+	query := `SELECT id FROM scheduled_transfers WHERE status = 'scheduled' AND execute_at <= $1 ORDER BY execute_at, id`
+	err := r.db.SelectContext(ctx, &ids, query, now)
+	if err != nil {
+		r.logger.Error("repository: failed to find due scheduled transfers", "error", err)
+		return nil, fmt.Errorf("repository: error finding due scheduled transfers: %w", err)
+	}
+
+	return ids, nil
+}
+
+// Claim moves a due transfer to processing. It reports false when another
+// worker got there first or the user cancelled it.
+func (r *ScheduledTransferRepository) Claim(ctx context.Context, id string, now time.Time) (*models.ScheduledTransfer, bool, error) {
+	var transfer models.ScheduledTransfer
+	query := `
+		UPDATE scheduled_transfers
+		SET status = 'processing', updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'scheduled' AND execute_at <= $2
+		RETURNING ` + scheduledTransferColumns
+	err := r.db.GetContext(ctx, &transfer, query, id, now)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+		r.logger.Error("repository: failed to claim scheduled transfer", "error", err, "scheduleID", id)
+		return nil, false, fmt.Errorf("repository: error claiming scheduled transfer: %w", err)
+	}
+
+	return &transfer, true, nil
+}
+
+const finishScheduledTransferQuery = `
+		UPDATE scheduled_transfers
+		SET status = $1, failure_reason = $2, transaction_id = $3, executed_at = $4, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $5 AND status = 'processing'
+		RETURNING updated_at
+	`
+
+// Finish records the outcome of a claimed transfer.
+func (r *ScheduledTransferRepository) Finish(ctx context.Context, transfer *models.ScheduledTransfer) error {
+	err := r.db.QueryRowContext(ctx, finishScheduledTransferQuery, transfer.Status, transfer.FailureReason,
+		transfer.TransactionID, transfer.ExecutedAt, transfer.ID).Scan(&transfer.UpdatedAt)
+	if err != nil {
+		r.logger.Error("repository: failed to finish scheduled transfer", "error", err, "scheduleID", transfer.ID)
+		return fmt.Errorf("repository: error finishing scheduled transfer: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ScheduledTransferRepository) FinishInTx(ctx context.Context, tx *sqlx.Tx, transfer *models.ScheduledTransfer) error {
+	err := tx.QueryRowContext(ctx, finishScheduledTransferQuery, transfer.Status, transfer.FailureReason,
+		transfer.TransactionID, transfer.ExecutedAt, transfer.ID).Scan(&transfer.UpdatedAt)
+	if err != nil {
+		r.logger.Error("repository: failed to finish scheduled transfer", "error", err, "scheduleID", transfer.ID)
+		return fmt.Errorf("repository: error finishing scheduled transfer: %w", err)
+	}
+
+	return nil
+}
+
+// LinkTransactionInTx stamps the schedule ID on the transaction posted for
+// it. The schedule row stays locked until tx commits, so recovery cannot
+// settle it halfway. It reports false when the schedule is no longer in
+// processing, in which case the posting must be rolled back.
+func (r *ScheduledTransferRepository) LinkTransactionInTx(ctx context.Context, tx *sqlx.Tx, id, transactionID string) (bool, error) {
+	var locked string
+	query := `SELECT id FROM scheduled_transfers WHERE id = $1 AND status = 'processing' FOR UPDATE`
+	if err := tx.GetContext(ctx, &locked, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		r.logger.Error("repository: failed to lock scheduled transfer", "error", err, "scheduleID", id)
+		return false, fmt.Errorf("repository: error locking scheduled transfer: %w", err)
+	}
+
+	query = `UPDATE transactions SET scheduled_transfer_id = $1 WHERE id = $2`
+	if _, err := tx.ExecContext(ctx, query, id, transactionID); err != nil {
+		r.logger.Error("repository: failed to link scheduled transfer", "error", err, "scheduleID", id, "transactionID", transactionID)
+		return false, fmt.Errorf("repository: error linking scheduled transfer: %w", err)
+	}
+	return true, nil
+}
+
+// FindStuckIDs returns the transfers claimed before claimedBefore that are
+// still in processing.
+func (r *ScheduledTransferRepository) FindStuckIDs(ctx context.Context, claimedBefore time.Time) ([]string, error) {
+	var ids []string
+	query := `SELECT id FROM scheduled_transfers WHERE status = 'processing' AND updated_at < $1 ORDER BY updated_at, id`
+	err := r.db.SelectContext(ctx, &ids, query, claimedBefore)
+	if err != nil {
+		r.logger.Error("repository: failed to find stuck scheduled transfers", "error", err)
+		return nil, fmt.Errorf("repository: error finding stuck scheduled transfers: %w", err)
+	}
+
+	return ids, nil
+}
+
+// FindStuckForUpdate locks a transfer that is still in processing since
+// before claimedBefore. It reports false when the transfer has been settled
+// in the meantime.
+func (r *ScheduledTransferRepository) FindStuckForUpdate(ctx context.Context, tx *sqlx.Tx, id string, claimedBefore time.Time) (*models.ScheduledTransfer, bool, error) {
+	var transfer models.ScheduledTransfer
+	query := `
+		SELECT ` + scheduledTransferColumns + `
+		FROM scheduled_transfers
+		WHERE id = $1 AND status = 'processing' AND updated_at < $2
+		FOR UPDATE
+	`
+	err := tx.GetContext(ctx, &transfer, query, id, claimedBefore)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+		r.logger.Error("repository: failed to lock stuck scheduled transfer", "error", err, "scheduleID", id)
+		return nil, false, fmt.Errorf("repository: error locking scheduled transfer: %w", err)
+	}
+
+	return &transfer, true, nil
+}
+
+// FindPostedTransaction returns the transaction posted for the schedule, or
+// nil if none was.
+func (r *ScheduledTransferRepository) FindPostedTransaction(ctx context.Context, tx *sqlx.Tx, id string) (*models.Transaction, error) {
+	var transaction models.Transaction
+	query := `
+		SELECT id, type, from_user_id, to_user_id, currency, amount_cents, to_currency, to_amount_cents, description,
+		       fee_cents, created_at
+		FROM transactions
+		WHERE scheduled_transfer_id = $1
+	`
+	err := tx.GetContext(ctx, &transaction, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("repository: failed to find posted transaction", "error", err, "scheduleID", id)
+		return nil, fmt.Errorf("repository: error finding posted transaction: %w", err)
+	}
+
+	return &transaction, nil
+}
+
+// Requeue puts a claimed transfer back, for runs interrupted before the
+// transfer was attempted.
+func (r *ScheduledTransferRepository) Requeue(ctx context.Context, id string) error {
+	query := `UPDATE scheduled_transfers SET status = 'scheduled', updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND status = 'processing'`
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		r.logger.Error("repository: failed to requeue scheduled transfer", "error", err, "scheduleID", id)
+		return fmt.Errorf("repository: error requeuing scheduled transfer: %w", err)
+	}
+	return nil
+}
+
+// CancelForAccountInTx cancels every pending transfer from or to an account
+// that is being closed, including those addressed by its account number. It
+// returns the number cancelled.
+func (r *ScheduledTransferRepository) CancelForAccountInTx(ctx context.Context, tx *sqlx.Tx, accountID, accountNumber, reason string) (int64, error) {
+	query := `
+		UPDATE scheduled_transfers
+		SET status = 'cancelled', failure_reason = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE status = 'scheduled'
+		  AND (from_account_id = $1 OR to_account_id = $1
+		       OR ($2 <> '' AND UPPER(REGEXP_REPLACE(to_account_number, '\s', '', 'g')) = $2))
+	`
+	result, err := tx.ExecContext(ctx, query, accountID, accountNumber, reason)
+	if err != nil {
+		r.logger.Error("repository: failed to cancel scheduled transfers for account", "error", err, "accountID", accountID)
+		return 0, fmt.Errorf("repository: error cancelling scheduled transfers: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// Cancel cancels a transfer that has not started running yet.
+func (r *ScheduledTransferRepository) Cancel(ctx context.Context, id, userID string) (*models.ScheduledTransfer, error) {
+	var transfer models.ScheduledTransfer
+	query := `
+		UPDATE scheduled_transfers
+		SET status = 'cancelled', updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND user_id = $2 AND status = 'scheduled'
+		RETURNING ` + scheduledTransferColumns
+	err := r.db.GetContext(ctx, &transfer, query, id, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorsx.ErrScheduleNotPending
+		}
+		r.logger.Error("repository: failed to cancel scheduled transfer", "error", err, "scheduleID", id)
+		return nil, fmt.Errorf("repository: error cancelling scheduled transfer: %w", err)
+	}
+
+	return &transfer, nil
+}
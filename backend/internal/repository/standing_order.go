@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/models"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const standingOrderColumns = `id, user_id, from_account_id, to_account_id, amount_cents, description, frequency,
+		       day_of_month, month_end_rule, start_on, end_on, next_run_on, status, created_at, updated_at`
+
+const standingOrderRunColumns = `id, standing_order_id, run_on, status, failure_reason, transaction_id, created_at, updated_at`
+
+type StandingOrderRepository struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+func NewStandingOrderRepository(db *sqlx.DB, logger *slog.Logger) *StandingOrderRepository {
+	return &StandingOrderRepository{db: db, logger: logger}
+}
+
+func (r *StandingOrderRepository) Create(ctx context.Context, order *models.StandingOrder) error {
+	query := `
+		INSERT INTO standing_orders (user_id, from_account_id, to_account_id, amount_cents, description, frequency,
+		                             day_of_month, month_end_rule, start_on, end_on, next_run_on, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9::date, $10::date, $11::date, $12)
+		RETURNING id, created_at, updated_at
+	`
+	err := r.db.QueryRowContext(ctx, query,
+		order.UserID,
+		order.FromAccountID,
+		order.ToAccountID,
+		order.AmountCents,
+		order.Description,
+		order.Frequency,
+		order.DayOfMonth,
+		order.MonthEndRule,
+		order.StartOn,
+		order.EndOn,
+		order.NextRunOn,
+		order.Status,
+	).Scan(&order.ID, &order.CreatedAt, &order.UpdatedAt)
+
+	if err != nil {
+		r.logger.Error("repository: failed to create standing order", "error", err, "userID", order.UserID)
+		return fmt.Errorf("repository: error creating standing order: %w", err)
+	}
+
+	r.logger.Info("repository: standing order created", "standingOrderID", order.ID, "frequency", order.Frequency)
+	return nil
+}
+
+func (r *StandingOrderRepository) FindByID(ctx context.Context, id string) (*models.StandingOrder, error) {
+	var order models.StandingOrder
+	query := `SELECT ` + standingOrderColumns + ` FROM standing_orders WHERE id = $1`
+	err := r.db.GetContext(ctx, &order, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorsx.ErrStandingOrderNotFound
+		}
+		r.logger.Error("repository: failed to find standing order", "error", err, "standingOrderID", id)
+		return nil, fmt.Errorf("repository: error finding standing order: %w", err)
+	}
+
+	return &order, nil
+}
+
+func (r *StandingOrderRepository) FindByUser(ctx context.Context, userID, status string) ([]models.StandingOrder, error) {
+	orders := []models.StandingOrder{}
+	query := `
+		SELECT ` + standingOrderColumns + `
+		FROM standing_orders
+		WHERE user_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC
+	`
+	err := r.db.SelectContext(ctx, &orders, query, userID, status)
+	if err != nil {
+		r.logger.Error("repository: failed to find standing orders", "error", err, "userID", userID)
+		return nil, fmt.Errorf("repository: error finding standing orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+func (r *StandingOrderRepository) FindDueIDs(ctx context.Context, today time.Time) ([]string, error) {
+	var ids []string
+	query := `SELECT id FROM standing_orders WHERE status = 'active' AND next_run_on <= $1::date ORDER BY next_run_on, id`
+	err := r.db.SelectContext(ctx, &ids, query, today)
+	if err != nil {
+		r.logger.Error("repository: failed to find due standing orders", "error", err)
+		return nil, fmt.Errorf("repository: error finding due standing orders: %w", err)
+	}
+
+	return ids, nil
+}
+
+// UpdateState moves an order from one status to another and sets its next
+// run date. It returns ErrStandingOrderState when the order is no longer in
+// fromStatus.
+func (r *StandingOrderRepository) UpdateState(ctx context.Context, id, fromStatus, status string, nextRunOn *time.Time) (*models.StandingOrder, error) {
+	var order models.StandingOrder
+	query := `
+		UPDATE standing_orders
+		SET status = $1, next_run_on = $2::date, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3 AND status = $4
+		RETURNING ` + standingOrderColumns
+	err := r.db.GetContext(ctx, &order, query, status, nextRunOn, id, fromStatus)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorsx.ErrStandingOrderState
+		}
+		r.logger.Error("repository: failed to update standing order", "error", err, "standingOrderID", id)
+		return nil, fmt.Errorf("repository: error updating standing order: %w", err)
+	}
+
+	return &order, nil
+}
+
+// CancelForAccountInTx cancels every active or paused order paying from or
+// to an account that is being closed. It returns the number cancelled.
+func (r *StandingOrderRepository) CancelForAccountInTx(ctx context.Context, tx *sqlx.Tx, accountID string) (int64, error) {
+	query := `
+		UPDATE standing_orders
+		SET status = 'cancelled', next_run_on = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE status IN ('active', 'paused') AND (from_account_id = $1 OR to_account_id = $1)
+	`
+	result, err := tx.ExecContext(ctx, query, accountID)
+	if err != nil {
+		r.logger.Error("repository: failed to cancel standing orders for account", "error", err, "accountID", accountID)
+		return 0, fmt.Errorf("repository: error cancelling standing orders: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// AdvanceNextRun moves an active order past runOn. It reports false when the
+// order was paused, cancelled or already advanced by another worker.
+func (r *StandingOrderRepository) AdvanceNextRun(ctx context.Context, tx *sqlx.Tx, id string, runOn time.Time, nextRunOn *time.Time, status string) (bool, error) {
+	query := `
+		UPDATE standing_orders
+		SET next_run_on = $1::date, status = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3 AND status = 'active' AND next_run_on = $4::date
+	`
+	result, err := tx.ExecContext(ctx, query, nextRunOn, status, id, runOn)
+	if err != nil {
+		r.logger.Error("repository: failed to advance standing order", "error", err, "standingOrderID", id)
+		return false, fmt.Errorf("repository: error advancing standing order: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+// RestoreNextRun undoes AdvanceNextRun for a run that never got to the
+// transfer, unless the order has been changed since.
+func (r *StandingOrderRepository) RestoreNextRun(ctx context.Context, tx *sqlx.Tx, id string, runOn time.Time, advancedTo *time.Time) error {
+	query := `
+		UPDATE standing_orders
+		SET next_run_on = $1::date, status = 'active', updated_at = CURRENT_TIMESTAMP
+		WHERE id = $2 AND status IN ('active', 'completed') AND next_run_on IS NOT DISTINCT FROM $3::date
+	`
+	if _, err := tx.ExecContext(ctx, query, runOn, id, advancedTo); err != nil {
+		r.logger.Error("repository: failed to restore standing order", "error", err, "standingOrderID", id)
+		return fmt.Errorf("repository: error restoring standing order: %w", err)
+	}
+	return nil
+}
+
+func (r *StandingOrderRepository) CreateRun(ctx context.Context, tx *sqlx.Tx, run *models.StandingOrderRun) error {
+	query := `
+		INSERT INTO standing_order_runs (standing_order_id, run_on, status)
+		VALUES ($1, $2::date, $3)
+		RETURNING id, created_at, updated_at
+	`
+	err := tx.QueryRowContext(ctx, query, run.StandingOrderID, run.RunOn, run.Status).
+		Scan(&run.ID, &run.CreatedAt, &run.UpdatedAt)
+	if err != nil {
+		r.logger.Error("repository: failed to create standing order run", "error", err, "standingOrderID", run.StandingOrderID)
+		return fmt.Errorf("repository: error creating standing order run: %w", err)
+	}
+
+	return nil
+}
+
+const finishStandingOrderRunQuery = `
+		UPDATE standing_order_runs
+		SET status = $1, failure_reason = $2, transaction_id = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND status = 'processing'
+		RETURNING updated_at
+	`
+
+// FinishRun records the outcome of a run that is still in processing.
+func (r *StandingOrderRepository) FinishRun(ctx context.Context, run *models.StandingOrderRun) error {
+	err := r.db.QueryRowContext(ctx, finishStandingOrderRunQuery, run.Status, run.FailureReason, run.TransactionID, run.ID).Scan(&run.UpdatedAt)
+	if err != nil {
+		r.logger.Error("repository: failed to finish standing order run", "error", err, "runID", run.ID)
+		return fmt.Errorf("repository: error finishing standing order run: %w", err)
+	}
+
+	return nil
+}
+
+func (r *StandingOrderRepository) FinishRunInTx(ctx context.Context, tx *sqlx.Tx, run *models.StandingOrderRun) error {
+	err := tx.QueryRowContext(ctx, finishStandingOrderRunQuery, run.Status, run.FailureReason, run.TransactionID, run.ID).Scan(&run.UpdatedAt)
+	if err != nil {
+		r.logger.Error("repository: failed to finish standing order run", "error", err, "runID", run.ID)
+		return fmt.Errorf("repository: error finishing standing order run: %w", err)
+	}
+
+	return nil
+}
+
+// LinkTransactionInTx locks a run that is still in processing and stamps its
+// ID on the transaction posted for it. It reports false when the run has
+// been settled meanwhile, in which case the posting must be rolled back.
+func (r *StandingOrderRepository) LinkTransactionInTx(ctx context.Context, tx *sqlx.Tx, runID, transactionID string) (bool, error) {
+	var locked string
+	query := `SELECT id FROM standing_order_runs WHERE id = $1 AND status = 'processing' FOR UPDATE`
+	if err := tx.GetContext(ctx, &locked, query, runID); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		r.logger.Error("repository: failed to lock standing order run", "error", err, "runID", runID)
+		return false, fmt.Errorf("repository: error locking standing order run: %w", err)
+	}
+
+	query = `UPDATE transactions SET standing_order_run_id = $1 WHERE id = $2`
+	if _, err := tx.ExecContext(ctx, query, runID, transactionID); err != nil {
+		r.logger.Error("repository: failed to link standing order run", "error", err, "runID", runID, "transactionID", transactionID)
+		return false, fmt.Errorf("repository: error linking standing order run: %w", err)
+	}
+	return true, nil
+}
+
+// FindStuckRunIDs returns the runs claimed before claimedBefore that are
+// still in processing.
+func (r *StandingOrderRepository) FindStuckRunIDs(ctx context.Context, claimedBefore time.Time) ([]string, error) {
+	var ids []string
+	query := `SELECT id FROM standing_order_runs WHERE status = 'processing' AND updated_at < $1 ORDER BY updated_at, id`
+	err := r.db.SelectContext(ctx, &ids, query, claimedBefore)
+	if err != nil {
+		r.logger.Error("repository: failed to find stuck standing order runs", "error", err)
+		return nil, fmt.Errorf("repository: error finding stuck standing order runs: %w", err)
+	}
+
+	return ids, nil
+}
+
+// FindStuckRunForUpdate locks a run that is still in processing since before
+// claimedBefore. It reports false when the run has been settled meanwhile.
+func (r *StandingOrderRepository) FindStuckRunForUpdate(ctx context.Context, tx *sqlx.Tx, runID string, claimedBefore time.Time) (*models.StandingOrderRun, bool, error) {
+	var run models.StandingOrderRun
+	query := `
+		SELECT ` + standingOrderRunColumns + `
+		FROM standing_order_runs
+		WHERE id = $1 AND status = 'processing' AND updated_at < $2
+		FOR UPDATE
+	`
+	err := tx.GetContext(ctx, &run, query, runID, claimedBefore)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+		r.logger.Error("repository: failed to lock stuck standing order run", "error", err, "runID", runID)
+		return nil, false, fmt.Errorf("repository: error locking standing order run: %w", err)
+	}
+
+	return &run, true, nil
+}
+
+// FindRunTransactionID returns the ID of the transaction posted for the run,
+// or nil if none was.
+func (r *StandingOrderRepository) FindRunTransactionID(ctx context.Context, tx *sqlx.Tx, runID string) (*string, error) {
+	var transactionID string
+	query := `SELECT id FROM transactions WHERE standing_order_run_id = $1`
+	err := tx.GetContext(ctx, &transactionID, query, runID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("repository: failed to find standing order run transaction", "error", err, "runID", runID)
+		return nil, fmt.Errorf("repository: error finding standing order run transaction: %w", err)
+	}
+
+	return &transactionID, nil
+}
+
+func (r *StandingOrderRepository) DeleteRun(ctx context.Context, tx *sqlx.Tx, runID string) error {
+	query := `DELETE FROM standing_order_runs WHERE id = $1 AND status = 'processing'`
+	if _, err := tx.ExecContext(ctx, query, runID); err != nil {
+		r.logger.Error("repository: failed to delete standing order run", "error", err, "runID", runID)
+		return fmt.Errorf("repository: error deleting standing order run: %w", err)
+	}
+	return nil
+}
+
+// FindRuns returns the execution history of an order, newest first.
+func (r *StandingOrderRepository) FindRuns(ctx context.Context, standingOrderID string) ([]models.StandingOrderRun, error) {
+	runs := []models.StandingOrderRun{}
+	query := `
+		SELECT ` + standingOrderRunColumns + `
+		FROM standing_order_runs
+		WHERE standing_order_id = $1
+		ORDER BY run_on DESC
+	`
+	err := r.db.SelectContext(ctx, &runs, query, standingOrderID)
+	if err != nil {
+		r.logger.Error("repository: failed to find standing order runs", "error", err, "standingOrderID", standingOrderID)
+		return nil, fmt.Errorf("repository: error finding standing order runs: %w", err)
+	}
+
+	return runs, nil
+}
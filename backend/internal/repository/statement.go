@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"mini-banking-platform/internal/models"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type StatementRepository struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+func NewStatementRepository(db *sqlx.DB, logger *slog.Logger) *StatementRepository {
+	return &StatementRepository{db: db, logger: logger}
+}
+
+func (r *StatementRepository) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		r.logger.Error("repository: failed to begin transaction", "error", err)
+		return nil, fmt.Errorf("repository: error beginning transaction: %w", err)
+	}
+	return tx, nil
+}
+
+// FindByAccountAndPeriod returns the issued statement with its lines, or nil
+// if none has been issued yet.
+func (r *StatementRepository) FindByAccountAndPeriod(ctx context.Context, accountID, period string) (*models.Statement, error) {
+	var statement models.Statement
+	query := `
+		SELECT id, account_id, period, period_start, period_end, currency, opening_balance_cents,
+		       closing_balance_cents, total_credits_cents, total_debits_cents, created_at
+		FROM statements
+		WHERE account_id = $1 AND period = $2
+	`
+	err := r.db.GetContext(ctx, &statement, query, accountID, period)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("repository: failed to find statement", "error", err, "accountID", accountID, "period", period)
+		return nil, fmt.Errorf("repository: error finding statement: %w", err)
+	}
+
+	linesQuery := `
+		SELECT position, ledger_entry_id, transaction_id, transaction_type, description, amount_cents, balance_cents, posted_at
+		FROM statement_lines
+		WHERE statement_id = $1
+		ORDER BY position
+	`
+	statement.Lines = []models.StatementLine{}
+	if err := r.db.SelectContext(ctx, &statement.Lines, linesQuery, statement.ID); err != nil {
+		r.logger.Error("repository: failed to get statement lines", "error", err, "statementID", statement.ID)
+		return nil, fmt.Errorf("repository: error getting statement lines: %w", err)
+	}
+	return &statement, nil
+}
+
+// Create stores a statement and its lines. It reports false, storing nothing,
+// when a statement for the same account and period already exists.
+func (r *StatementRepository) Create(ctx context.Context, tx *sqlx.Tx, statement *models.Statement) (bool, error) {
+	query := `
+		INSERT INTO statements (account_id, period, period_start, period_end, currency, opening_balance_cents,
+		                        closing_balance_cents, total_credits_cents, total_debits_cents)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (account_id, period) DO NOTHING
+		RETURNING id, created_at
+	`
+	err := tx.QueryRowContext(ctx, query,
+		statement.AccountID,
+		statement.Period,
+		statement.PeriodStart,
+		statement.PeriodEnd,
+		statement.Currency,
+		statement.OpeningBalanceCents,
+		statement.ClosingBalanceCents,
+		statement.TotalCreditsCents,
+		statement.TotalDebitsCents,
+	).Scan(&statement.ID, &statement.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		r.logger.Error("repository: failed to create statement", "error", err, "accountID", statement.AccountID)
+		return false, fmt.Errorf("repository: error creating statement: %w", err)
+	}
+
+	lineQuery := `
+		INSERT INTO statement_lines (statement_id, position, ledger_entry_id, transaction_id, transaction_type,
+		                             description, amount_cents, balance_cents, posted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	for _, line := range statement.Lines {
+		_, err := tx.ExecContext(ctx, lineQuery,
+			statement.ID,
+			line.Position,
+			line.LedgerEntryID,
+			line.TransactionID,
+			line.TransactionType,
+			line.Description,
+			line.AmountCents,
+			line.BalanceCents,
+			line.PostedAt,
+		)
+		if err != nil {
+			r.logger.Error("repository: failed to create statement line", "error", err, "statementID", statement.ID)
+			return false, fmt.Errorf("repository: error creating statement line: %w", err)
+		}
+	}
+
+	r.logger.Info("repository: statement issued", "statementID", statement.ID, "accountID", statement.AccountID, "period", statement.Period)
+	return true, nil
+}
+
+// FindMovements returns the account's ledger entries posted in [from, to),
+// oldest first, with their transaction details.
+func (r *StatementRepository) FindMovements(ctx context.Context, tx *sqlx.Tx, accountID string, from, to time.Time) ([]models.StatementLine, error) {
+	var lines []models.StatementLine
+	query := `
+		SELECT le.id AS ledger_entry_id, le.transaction_id, t.type AS transaction_type, t.description,
+		       le.amount_cents, le.created_at AS posted_at
+		FROM ledger_entries le
+		JOIN transactions t ON t.id = le.transaction_id
+		WHERE le.account_id = $1 AND le.created_at >= $2 AND le.created_at < $3
+		ORDER BY le.created_at, t.created_at, le.id
+	`
+	if err := tx.SelectContext(ctx, &lines, query, accountID, from, to); err != nil {
+		r.logger.Error("repository: failed to get statement movements", "error", err, "accountID", accountID)
+		return nil, fmt.Errorf("repository: error getting statement movements: %w", err)
+	}
+	return lines, nil
+}
+
+func (r *StatementRepository) GetBalanceBefore(ctx context.Context, tx *sqlx.Tx, accountID string, before time.Time) (int64, error) {
+	var sum int64
+	query := `SELECT COALESCE(SUM(amount_cents), 0) FROM ledger_entries WHERE account_id = $1 AND created_at < $2`
+	if err := tx.GetContext(ctx, &sum, query, accountID, before); err != nil {
+		r.logger.Error("repository: failed to get opening balance", "error", err, "accountID", accountID)
+		return 0, fmt.Errorf("repository: error getting opening balance: %w", err)
+	}
+	return sum, nil
+}
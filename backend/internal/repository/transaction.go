@@ -5,7 +5,9 @@ import (
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"mini-banking-platform/internal/errorsx"
 	"mini-banking-platform/internal/models"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -30,8 +32,9 @@ func (r *TransactionRepository) BeginTx(ctx context.Context) (*sqlx.Tx, error) {
 
 func (r *TransactionRepository) Create(ctx context.Context, tx *sqlx.Tx, transaction *models.Transaction) error {
 	query := `
-		INSERT INTO transactions (type, from_user_id, to_user_id, currency, amount_cents, description)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO transactions (type, from_user_id, to_user_id, currency, amount_cents, to_currency, to_amount_cents,
+		                          description, fx_rate_id, fx_rate_num, fx_rate_denom, fee_cents, reverses_transaction_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		RETURNING id, created_at
 	`
 	err := tx.QueryRowContext(ctx, query,
@@ -40,7 +43,14 @@ func (r *TransactionRepository) Create(ctx context.Context, tx *sqlx.Tx, transac
 		transaction.ToUserID,
 		transaction.Currency,
 		transaction.AmountCents,
+		transaction.ToCurrency,
+		transaction.ToAmountCents,
 		transaction.Description,
+		transaction.FXRateID,
+		transaction.FXRateNum,
+		transaction.FXRateDenom,
+		transaction.FeeCents,
+		transaction.ReversesTransactionID,
 	).Scan(&transaction.ID, &transaction.CreatedAt)
 
 	if err != nil {
@@ -77,11 +87,71 @@ func (r *TransactionRepository) CreateLedgerEntryInTx(ctx context.Context, tx *s
 	return r.CreateLedgerEntry(ctx, tx, entry)
 }
 
+func (r *TransactionRepository) FindByID(ctx context.Context, id string) (*models.Transaction, error) {
+	var transaction models.Transaction
+	query := `
+		SELECT id, type, from_user_id, to_user_id, currency, amount_cents, to_currency, to_amount_cents, description,
+		       fx_rate_id, fx_rate_num, fx_rate_denom, fee_cents, reverses_transaction_id, reversed_by_transaction_id,
+		       refunded_cents, created_at
+		FROM transactions
+		WHERE id = $1
+	`
+	err := r.db.GetContext(ctx, &transaction, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorsx.ErrTransactionNotFound
+		}
+		r.logger.Error("repository: failed to find transaction", "error", err, "transactionID", id)
+		return nil, fmt.Errorf("repository: error finding transaction: %w", err)
+	}
+
+	return &transaction, nil
+}
+
+func (r *TransactionRepository) FindByIDForUpdate(ctx context.Context, tx *sqlx.Tx, id string) (*models.Transaction, error) {
+	var transaction models.Transaction
+	query := `
+		SELECT id, type, from_user_id, to_user_id, currency, amount_cents, to_currency, to_amount_cents, description,
+		       fx_rate_id, fx_rate_num, fx_rate_denom, fee_cents, reverses_transaction_id, reversed_by_transaction_id,
+		       refunded_cents, created_at
+		FROM transactions
+		WHERE id = $1
+		FOR UPDATE
+	`
+	err := tx.GetContext(ctx, &transaction, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errorsx.ErrTransactionNotFound
+		}
+		r.logger.Error("repository: failed to find transaction for update", "error", err, "transactionID", id)
+		return nil, fmt.Errorf("repository: error finding transaction: %w", err)
+	}
+
+	return &transaction, nil
+}
+
+// AddRefund links the original to its latest reversal and adds the refunded
+// amount to its running total.
+func (r *TransactionRepository) AddRefund(ctx context.Context, tx *sqlx.Tx, transactionID, reversalID string, amountCents int64) error {
+	query := `
+		UPDATE transactions
+		SET reversed_by_transaction_id = $1, refunded_cents = refunded_cents + $2
+		WHERE id = $3
+	`
+	if _, err := tx.ExecContext(ctx, query, reversalID, amountCents, transactionID); err != nil {
+		r.logger.Error("repository: failed to record refund", "error", err, "transactionID", transactionID)
+		return fmt.Errorf("repository: error recording refund: %w", err)
+	}
+	return nil
+}
+
 func (r *TransactionRepository) FindByUserID(ctx context.Context, userID string, transactionType string, page, limit int) ([]models.Transaction, int, error) {
 	offset := (page - 1) * limit
 
 	baseQuery := `
-		SELECT id, type, from_user_id, to_user_id, currency, amount_cents, description, created_at
+		SELECT id, type, from_user_id, to_user_id, currency, amount_cents, to_currency, to_amount_cents, description,
+		       fx_rate_id, fx_rate_num, fx_rate_denom, fee_cents, reverses_transaction_id, reversed_by_transaction_id,
+		       refunded_cents, created_at
 		FROM transactions
 		WHERE (from_user_id = $1 OR to_user_id = $1)
 	`
@@ -140,3 +210,92 @@ func (r *TransactionRepository) GetLedgerSumCents(ctx context.Context, accountID
 
 	return sumCents.Int64, nil
 }
+
+// GetLedgerSumCentsAt returns the account balance as of at, counting every
+// entry posted up to and including that instant.
+func (r *TransactionRepository) GetLedgerSumCentsAt(ctx context.Context, accountID string, at time.Time) (int64, error) {
+	var sumCents int64
+	query := `SELECT COALESCE(SUM(amount_cents), 0) FROM ledger_entries WHERE account_id = $1 AND created_at <= $2`
+
+	err := r.db.GetContext(ctx, &sumCents, query, accountID, at)
+	if err != nil {
+		r.logger.Error("repository: failed to get ledger sum", "error", err, "accountID", accountID)
+		return 0, fmt.Errorf("repository: error getting ledger sum: %w", err)
+	}
+
+	return sumCents, nil
+}
+
+// GetDailyLedgerTotals returns the net movement per UTC day in [from, to).
+// Days without entries are omitted.
+func (r *TransactionRepository) GetDailyLedgerTotals(ctx context.Context, accountID string, from, to time.Time) ([]models.DailyLedgerTotal, error) {
+	var totals []models.DailyLedgerTotal
+	query := `
+		SELECT DATE(created_at) AS day, SUM(amount_cents) AS amount_cents
+		FROM ledger_entries
+		WHERE account_id = $1 AND created_at >= $2 AND created_at < $3
+		GROUP BY DATE(created_at)
+		ORDER BY day
+	`
+	err := r.db.SelectContext(ctx, &totals, query, accountID, from, to)
+	if err != nil {
+		r.logger.Error("repository: failed to get daily ledger totals", "error", err, "accountID", accountID)
+		return nil, fmt.Errorf("repository: error getting daily ledger totals: %w", err)
+	}
+
+	return totals, nil
+}
+
+func (r *TransactionRepository) GetLedgerTotalsByUser(ctx context.Context, userID string, from, to time.Time) ([]models.CurrencyTotal, error) {
+	var totals []models.CurrencyTotal
+	query := `
+		SELECT le.currency, COALESCE(SUM(le.amount_cents), 0) AS amount_cents
+		FROM ledger_entries le
+		JOIN accounts a ON a.id = le.account_id
+		WHERE a.user_id = $1 AND le.created_at >= $2 AND le.created_at < $3
+		GROUP BY le.currency
+		ORDER BY le.currency
+	`
+	err := r.db.SelectContext(ctx, &totals, query, userID, from, to)
+	if err != nil {
+		r.logger.Error("repository: failed to get ledger totals", "error", err, "userID", userID)
+		return nil, fmt.Errorf("repository: error getting ledger totals: %w", err)
+	}
+
+	return totals, nil
+}
+
+func (r *TransactionRepository) FindLedgerEntriesByUser(ctx context.Context, userID string, before time.Time) ([]models.LedgerEntry, error) {
+	var entries []models.LedgerEntry
+	query := `
+		SELECT le.id, le.transaction_id, le.account_id, le.currency, le.amount_cents, le.created_at
+		FROM ledger_entries le
+		JOIN accounts a ON a.id = le.account_id
+		WHERE a.user_id = $1 AND le.created_at < $2
+		ORDER BY le.created_at, le.id
+	`
+	err := r.db.SelectContext(ctx, &entries, query, userID, before)
+	if err != nil {
+		r.logger.Error("repository: failed to find ledger entries", "error", err, "userID", userID)
+		return nil, fmt.Errorf("repository: error finding ledger entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (r *TransactionRepository) FindLedgerEntriesByTransaction(ctx context.Context, tx *sqlx.Tx, transactionID string) ([]models.LedgerEntry, error) {
+	var entries []models.LedgerEntry
+	query := `
+		SELECT id, transaction_id, account_id, currency, amount_cents, created_at
+		FROM ledger_entries
+		WHERE transaction_id = $1
+		ORDER BY created_at, id
+	`
+	err := tx.SelectContext(ctx, &entries, query, transactionID)
+	if err != nil {
+		r.logger.Error("repository: failed to find ledger entries", "error", err, "transactionID", transactionID)
+		return nil, fmt.Errorf("repository: error finding ledger entries: %w", err)
+	}
+
+	return entries, nil
+}
@@ -40,11 +40,11 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User, hashedPa
 
 func (r *UserRepository) CreateInTx(ctx context.Context, tx *sqlx.Tx, user *models.User, hashedPassword string) error {
 	query := `
-		INSERT INTO users (email, password, first_name, last_name)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO users (email, password, first_name, last_name, is_admin)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id, created_at, updated_at
 	`
-	err := tx.QueryRowContext(ctx, query, user.Email, hashedPassword, user.FirstName, user.LastName).
+	err := tx.QueryRowContext(ctx, query, user.Email, hashedPassword, user.FirstName, user.LastName, user.IsAdmin).
 		Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
 	
 	if err != nil {
@@ -59,7 +59,7 @@ func (r *UserRepository) CreateInTx(ctx context.Context, tx *sqlx.Tx, user *mode
 func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
 	query := `
-		SELECT id, email, password, first_name, last_name, created_at, updated_at
+		SELECT id, email, password, first_name, last_name, is_admin, preferred_currency, created_at, updated_at
 		FROM users
 		WHERE email = $1
 	`
@@ -78,7 +78,7 @@ func (r *UserRepository) FindByEmail(ctx context.Context, email string) (*models
 func (r *UserRepository) FindByID(ctx context.Context, id string) (*models.User, error) {
 	var user models.User
 	query := `
-		SELECT id, email, password, first_name, last_name, created_at, updated_at
+		SELECT id, email, password, first_name, last_name, is_admin, preferred_currency, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
@@ -94,3 +94,22 @@ func (r *UserRepository) FindByID(ctx context.Context, id string) (*models.User,
 	return &user, nil
 }
 
+
+func (r *UserRepository) SetPreferredCurrency(ctx context.Context, userID string, currency *string) error {
+	query := `UPDATE users SET preferred_currency = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+	result, err := r.db.ExecContext(ctx, query, currency, userID)
+	if err != nil {
+		r.logger.Error("repository: failed to update preferred currency", "error", err, "userID", userID)
+		return fmt.Errorf("repository: error updating preferred currency: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errorsx.ErrUserNotFound
+	}
+
+	return nil
+}
@@ -5,33 +5,121 @@ import (
 	"fmt"
 	"log/slog"
 	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
 	"mini-banking-platform/internal/models"
 	"mini-banking-platform/internal/repository"
+	"mini-banking-platform/pkg/decimal"
+	"strings"
+	"time"
 )
 
 type AccountService struct {
 	accountRepo     *repository.AccountRepository
 	transactionRepo *repository.TransactionRepository
+	currencyRepo    *repository.CurrencyRepository
 	logger          *slog.Logger
 }
 
-func NewAccountService(accountRepo *repository.AccountRepository, transactionRepo *repository.TransactionRepository, logger *slog.Logger) *AccountService {
+func NewAccountService(accountRepo *repository.AccountRepository, transactionRepo *repository.TransactionRepository, currencyRepo *repository.CurrencyRepository, logger *slog.Logger) *AccountService {
 	return &AccountService{
 		accountRepo:     accountRepo,
 		transactionRepo: transactionRepo,
+		currencyRepo:    currencyRepo,
 		logger:          logger,
 	}
 }
 
-func (s *AccountService) GetUserAccounts(ctx context.Context, userID string) ([]models.Account, error) {
-	accounts, err := s.accountRepo.FindByUserID(ctx, userID)
+// GetUserAccounts returns the user's own accounts followed by the accounts
+// shared with them, each with the user's role on it. Closed accounts are left
+// out unless includeClosed is set.
+func (s *AccountService) GetUserAccounts(ctx context.Context, userID string, includeClosed bool) ([]models.Account, error) {
+	accounts, err := s.accountRepo.FindByUserID(ctx, userID, includeClosed)
 	if err != nil {
 		s.logger.Error("failed to get user accounts", "error", err, "userID", userID)
 		return nil, fmt.Errorf("error getting accounts: %w", err)
 	}
+	for i := range accounts {
+		accounts[i].Role = models.AccountRoleOwner
+	}
+	shared, err := s.accountRepo.FindSharedWithUser(ctx, userID, includeClosed)
+	if err != nil {
+		s.logger.Error("failed to get shared accounts", "error", err, "userID", userID)
+		return nil, fmt.Errorf("error getting accounts: %w", err)
+	}
+	accounts = append(accounts, shared...)
+	for i := range accounts {
+		setDerivedBalances(&accounts[i])
+	}
 	return accounts, nil
 }
 
+// setDerivedBalances fills the figures computed from the ledger balance: the
+// overdraft in use and what can still be spent, which is the balance left
+// after holds and open orders plus the unused credit limit. It matches
+// AccountRepository.GetAvailableCents, which postings are checked against;
+// system accounts without a limit report the balance after holds.
+func setDerivedBalances(account *models.Account) {
+	account.OverdraftUsedCents = 0
+	if account.BalanceCents < 0 {
+		account.OverdraftUsedCents = -account.BalanceCents
+	}
+	account.AvailableBalanceCents = account.BalanceCents - account.ReservedCents
+	if account.CreditLimitCents != nil {
+		account.AvailableBalanceCents += *account.CreditLimitCents
+	}
+}
+
+// OpenAccount opens a current or savings account. Savings accounts are
+// always named pockets and start at savingsRate.
+func (s *AccountService) OpenAccount(ctx context.Context, userID string, req dto.OpenAccountRequest, savingsRate string) (*models.Account, error) {
+	currency, err := enabledCurrency(ctx, s.currencyRepo, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	account := &models.Account{
+		UserID:   userID,
+		Currency: currency.Code,
+		Type:     models.AccountTypeCurrent,
+	}
+
+	if req.Type == models.AccountTypeSavings {
+		if strings.TrimSpace(req.Name) == "" {
+			return nil, errorsx.BadRequest("savings accounts require a name")
+		}
+		num, denom, err := decimal.ParseRational(savingsRate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid savings interest rate: %w", err)
+		}
+		account.Type = models.AccountTypeSavings
+		account.InterestRate = &savingsRate
+		account.InterestRateNum = &num
+		account.InterestRateDenom = &denom
+	}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		existing, _ := s.accountRepo.FindByUserAndName(ctx, userID, name)
+		if existing != nil {
+			s.logger.Warn("account name already in use", "userID", userID, "name", name)
+			return nil, errorsx.ErrAccountExists
+		}
+		account.Name = &name
+	} else {
+		existing, _ := s.accountRepo.FindByUserAndCurrency(ctx, userID, currency.Code)
+		if existing != nil {
+			s.logger.Warn("account already exists", "userID", userID, "currency", currency.Code)
+			return nil, errorsx.ErrAccountExists
+		}
+	}
+	if err := s.accountRepo.Create(ctx, account); err != nil {
+		s.logger.Error("failed to open account", "error", err, "userID", userID, "currency", currency.Code)
+		return nil, fmt.Errorf("error opening account: %w", err)
+	}
+
+	s.logger.Info("account opened", "userID", userID, "accountID", account.ID, "currency", account.Currency)
+	return account, nil
+}
+
 func (s *AccountService) GetAccountBalance(ctx context.Context, userID, accountID string) (*models.Account, error) {
 	account, err := s.accountRepo.FindByID(ctx, accountID)
 	if err != nil {
@@ -39,14 +127,188 @@ func (s *AccountService) GetAccountBalance(ctx context.Context, userID, accountI
 		return nil, err
 	}
 
-	if account.UserID != userID {
+	if err := requireAccountRole(ctx, s.accountRepo, account, userID, models.AccountRoleViewOnly); err != nil {
 		s.logger.Warn("unauthorized account access", "userID", userID, "accountID", accountID)
-		return nil, errorsx.ErrAccountNotFound
+		return nil, err
 	}
 
+	setDerivedBalances(account)
 	return account, nil
 }
 
+// maxBalanceHistoryDays bounds one balance history request to about a year.
+const maxBalanceHistoryDays = 366
+
+// GetAccountBalanceAt reconstructs the balance at a past instant from the
+// ledger.
+func (s *AccountService) GetAccountBalanceAt(ctx context.Context, userID, accountID string, at time.Time) (*models.Account, int64, error) {
+	account, err := s.GetAccountBalance(ctx, userID, accountID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	balanceCents, err := s.transactionRepo.GetLedgerSumCentsAt(ctx, account.ID, at)
+	if err != nil {
+		return nil, 0, err
+	}
+	return account, balanceCents, nil
+}
+
+// GetBalanceHistory returns the end-of-day balance (UTC) for every day from
+// from to to inclusive, derived from the ledger.
+func (s *AccountService) GetBalanceHistory(ctx context.Context, userID, accountID string, from, to time.Time) ([]models.BalancePoint, error) {
+	from, to = truncateToDay(from), truncateToDay(to)
+	if to.Before(from) {
+		return nil, errorsx.BadRequest("from must not be after to")
+	}
+	if int(to.Sub(from).Hours()/24) >= maxBalanceHistoryDays {
+		return nil, errorsx.BadRequest(fmt.Sprintf("history is limited to %d days", maxBalanceHistoryDays))
+	}
+
+	account, err := s.GetAccountBalance(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Timestamps are stored to the microsecond, so this is the balance just
+	// before from.
+	balanceCents, err := s.transactionRepo.GetLedgerSumCentsAt(ctx, account.ID, from.Add(-time.Microsecond))
+	if err != nil {
+		return nil, err
+	}
+	end := to.AddDate(0, 0, 1)
+	totals, err := s.transactionRepo.GetDailyLedgerTotals(ctx, account.ID, from, end)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]models.BalancePoint, 0, int(end.Sub(from).Hours()/24))
+	next := 0
+	for day := from; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if next < len(totals) && truncateToDay(totals[next].Day).Equal(day) {
+			balanceCents += totals[next].AmountCents
+			next++
+		}
+		points = append(points, models.BalancePoint{Date: day.Format(time.DateOnly), BalanceCents: balanceCents})
+	}
+	return points, nil
+}
+
+// SetStatus moves an account through its lifecycle and records who did it and
+// why. Closed is final, and only an empty account can be closed.
+func (s *AccountService) SetStatus(ctx context.Context, adminID, accountID string, req dto.SetAccountStatusRequest) (*models.Account, error) {
+	tx, err := s.accountRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	account, err := s.accountRepo.FindByIDForUpdate(ctx, tx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account.UserID == models.FXSystemUserID || account.UserID == models.RevenueSystemUserID || account.UserID == models.InterestSystemUserID {
+		return nil, errorsx.BadRequest("system accounts cannot change status")
+	}
+	if account.Status == models.AccountStatusClosed {
+		return nil, errorsx.ErrAccountClosed
+	}
+	if account.Status == req.Status {
+		return nil, errorsx.BadRequest(fmt.Sprintf("account is already %s", req.Status))
+	}
+	if req.Status == models.AccountStatusClosed && (account.BalanceCents != 0 || account.ReservedCents != 0) {
+		return nil, errorsx.BadRequest("account balance must be zero to close")
+	}
+
+	change := &models.AccountStatusChange{
+		AccountID:  account.ID,
+		FromStatus: account.Status,
+		ToStatus:   req.Status,
+		Reason:     strings.TrimSpace(req.Reason),
+		ChangedBy:  &adminID,
+	}
+	if err := s.accountRepo.UpdateStatus(ctx, tx, change); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit account status change", "error", err)
+		return nil, fmt.Errorf("error committing account status change: %w", err)
+	}
+
+	s.logger.Info("account status changed", "accountID", account.ID, "from", change.FromStatus, "to", change.ToStatus, "adminID", adminID)
+	account.Status = req.Status
+	return account, nil
+}
+
+// SetCreditLimit lets a customer account go negative down to the limit. A
+// limit below the overdraft already in use is rejected.
+func (s *AccountService) SetCreditLimit(ctx context.Context, accountID string, req dto.SetCreditLimitRequest) (*models.Account, error) {
+	tx, err := s.accountRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	account, err := s.accountRepo.FindByIDForUpdate(ctx, tx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account.CreditLimitCents == nil || account.UserID == models.RevenueSystemUserID {
+		return nil, errorsx.BadRequest("system accounts cannot change credit limit")
+	}
+	if account.Status == models.AccountStatusClosed {
+		return nil, errorsx.ErrAccountClosed
+	}
+	if account.BalanceCents < -req.CreditLimitCents {
+		return nil, errorsx.BadRequest("credit limit is below the overdraft in use")
+	}
+
+	if err := s.accountRepo.UpdateCreditLimit(ctx, tx, account.ID, req.CreditLimitCents); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit credit limit change", "error", err)
+		return nil, fmt.Errorf("error committing credit limit change: %w", err)
+	}
+
+	s.logger.Info("credit limit changed", "accountID", account.ID, "creditLimitCents", req.CreditLimitCents)
+	account.CreditLimitCents = &req.CreditLimitCents
+	setDerivedBalances(account)
+	return account, nil
+}
+
+func (s *AccountService) GetStatusHistory(ctx context.Context, accountID string) ([]models.AccountStatusChange, error) {
+	if _, err := s.accountRepo.FindByID(ctx, accountID); err != nil {
+		return nil, err
+	}
+	changes, err := s.accountRepo.FindStatusChanges(ctx, accountID)
+	if err != nil {
+		s.logger.Error("failed to get account status history", "error", err, "accountID", accountID)
+		return nil, fmt.Errorf("error getting account status history: %w", err)
+	}
+	return changes, nil
+}
+
+// checkAccountStatus rejects early what UpdateBalanceCents would refuse
+// anyway, so callers get the status error rather than a funds error.
+func checkAccountStatus(account *models.Account, debit bool) error {
+	switch account.Status {
+	case models.AccountStatusActive:
+		return nil
+	case models.AccountStatusDebitFrozen:
+		if debit {
+			return errorsx.ErrAccountFrozen
+		}
+		return nil
+	case models.AccountStatusClosed:
+		return errorsx.ErrAccountClosed
+	default:
+		return errorsx.ErrAccountFrozen
+	}
+}
+
 type ReconciliationResult struct {
 	AccountID      string `json:"account_id"`
 	Currency       string `json:"currency"`
@@ -57,7 +319,7 @@ type ReconciliationResult struct {
 }
 
 func (s *AccountService) ReconcileBalances(ctx context.Context, userID string) ([]ReconciliationResult, error) {
-	accounts, err := s.accountRepo.FindByUserID(ctx, userID)
+	accounts, err := s.accountRepo.FindByUserID(ctx, userID, true)
 	if err != nil {
 		s.logger.Error("failed to get user accounts for reconciliation", "error", err, "userID", userID)
 		return nil, fmt.Errorf("error getting accounts: %w", err)
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/pkg/accountnumber"
+	"strings"
+	"time"
+)
+
+// CloseAccount pays out any interest a savings account has earned, sweeps
+// the remaining balance to another of the holder's accounts, converting it
+// when the currencies differ, and closes the account in the same DB
+// transaction. The sweep is posted like any other transfer, so
+// the closed account keeps its full ledger history. Pending scheduled
+// transfers and active or paused standing orders that use the account are
+// cancelled and its members removed in that same transaction, so nothing
+// keeps pointing at a closed account.
+func (s *TransactionService) CloseAccount(ctx context.Context, userID, accountID string, req dto.CloseAccountRequest) (*models.Account, *models.Transaction, error) {
+	account, err := s.accountRepo.FindByID(ctx, accountID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := requireAccountRole(ctx, s.accountRepo, account, userID, models.AccountRoleOwner); err != nil {
+		return nil, nil, err
+	}
+	if err := checkAccountStatus(account, true); err != nil {
+		return nil, nil, err
+	}
+	if account.ReservedCents != 0 {
+		return nil, nil, errorsx.BadRequest("release holds and cancel open orders before closing the account")
+	}
+	if account.BalanceCents < 0 {
+		return nil, nil, errorsx.BadRequest("repay the overdraft before closing the account")
+	}
+
+	var target *models.Account
+	var holder *models.User
+	if account.BalanceCents > 0 || req.ToAccountID != "" {
+		if req.ToAccountID == "" {
+			return nil, nil, errorsx.BadRequest("to_account_id is required to sweep the remaining balance")
+		}
+		target, err = s.accountRepo.FindByID(ctx, req.ToAccountID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if target.ID == account.ID {
+			return nil, nil, errorsx.ErrCannotTransferToSelf
+		}
+		if target.UserID != account.UserID {
+			return nil, nil, errorsx.BadRequest("the balance can only be swept to another account of the account holder")
+		}
+		if err := checkAccountStatus(target, false); err != nil {
+			return nil, nil, err
+		}
+		holder, err = s.userRepo.FindByID(ctx, account.UserID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var expenseAccount *models.Account
+	if account.Type == models.AccountTypeSavings {
+		expenseAccount, err = s.interest.expenseAccount(ctx, account.Currency)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	tx, err := s.transactionRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	// Interest earned so far is paid out before the sweep; a closed account is
+	// never capitalized again.
+	now := time.Now().UTC()
+	var interestCents int64
+	if expenseAccount != nil {
+		interestCents, err = s.interest.closingInterest(ctx, tx, account, now)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	sweepCents := account.BalanceCents + interestCents
+	if sweepCents > 0 && target == nil {
+		return nil, nil, errorsx.BadRequest("to_account_id is required to sweep the remaining balance")
+	}
+
+	var pricing *exchangePricing
+	var fxAccounts *conversionAccounts
+	lockIDs := []string{account.ID}
+	if interestCents > 0 {
+		lockIDs = append(lockIDs, expenseAccount.ID)
+	}
+	if sweepCents > 0 {
+		lockIDs = append(lockIDs, target.ID)
+		if target.Currency != account.Currency {
+			pricing, err = s.priceExchange(ctx, account.Currency, target.Currency, sweepCents, now)
+			if err != nil {
+				return nil, nil, err
+			}
+			fxAccounts, err = s.findConversionAccounts(ctx, pricing)
+			if err != nil {
+				return nil, nil, err
+			}
+			lockIDs = append(lockIDs, fxAccounts.IDs()...)
+		}
+	}
+
+	if err := s.accountRepo.LockAccountsForUpdate(ctx, tx, lockIDs); err != nil {
+		return nil, nil, err
+	}
+	locked, err := s.accountRepo.FindByIDForUpdate(ctx, tx, account.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	// The sweep was priced from the unlocked read; anything that moved since
+	// means the caller has to look again.
+	if locked.BalanceCents != account.BalanceCents || locked.ReservedCents != 0 || locked.Status != account.Status {
+		return nil, nil, errorsx.BadRequest("account changed while closing, please retry")
+	}
+	if expenseAccount != nil {
+		credited, err := s.interest.capitalizeForClosure(ctx, tx, locked, expenseAccount, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		if credited != interestCents {
+			return nil, nil, errorsx.BadRequest("account changed while closing, please retry")
+		}
+	}
+
+	var transaction *models.Transaction
+	if sweepCents > 0 {
+		transaction = &models.Transaction{
+			Type:        models.TransactionTypeTransfer,
+			FromUserID:  userID,
+			ToUserID:    &holder.ID,
+			Currency:    account.Currency,
+			AmountCents: sweepCents,
+			Description: fmt.Sprintf("Balance sweep on closing account %s", account.AccountNumber),
+		}
+		if pricing != nil {
+			rate := pricing.Rate
+			transaction.ToCurrency = &pricing.ToCurrency
+			transaction.ToAmountCents = &pricing.ToAmountCents
+			transaction.FXRateID = &rate.ID
+			transaction.FXRateNum = &rate.RateNum
+			transaction.FXRateDenom = &rate.RateDenom
+			transaction.FeeCents = pricing.FeeCents
+			transaction.Description = fmt.Sprintf("%s: %d cents %s to %d cents %s (rate: %d/%d, fee: %d cents)",
+				transaction.Description, pricing.FromAmountCents, pricing.FromCurrency,
+				pricing.ToAmountCents, pricing.ToCurrency, rate.RateNum, rate.RateDenom, pricing.FeeCents)
+		}
+
+		if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
+			return nil, nil, err
+		}
+		entries := []*models.LedgerEntry{
+			{TransactionID: transaction.ID, AccountID: account.ID, Currency: account.Currency, AmountCents: -sweepCents},
+			{TransactionID: transaction.ID, AccountID: target.ID, Currency: account.Currency, AmountCents: sweepCents},
+		}
+		if pricing != nil {
+			entries = conversionEntries(transaction.ID, account.ID, target.ID, fxAccounts, pricing)
+		}
+		if err := s.postLedgerEntries(ctx, tx, entries); err != nil {
+			return nil, nil, err
+		}
+		if pricing != nil {
+			if err := s.recordRounding(ctx, tx, transaction.ID, pricing); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		reason = "closed by account owner"
+	}
+	change := &models.AccountStatusChange{
+		AccountID:  account.ID,
+		FromStatus: locked.Status,
+		ToStatus:   models.AccountStatusClosed,
+		Reason:     reason,
+		ChangedBy:  &userID,
+	}
+	if err := s.accountRepo.UpdateStatus(ctx, tx, change); err != nil {
+		return nil, nil, err
+	}
+	cancelledSchedules, err := s.scheduledRepo.CancelForAccountInTx(ctx, tx, account.ID,
+		accountnumber.Normalize(account.AccountNumber), "account closed")
+	if err != nil {
+		return nil, nil, err
+	}
+	cancelledOrders, err := s.standingRepo.CancelForAccountInTx(ctx, tx, account.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	removedMembers, err := s.accountRepo.DeleteMembersInTx(ctx, tx, account.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit account closure", "error", err)
+		return nil, nil, fmt.Errorf("error committing account closure: %w", err)
+	}
+
+	s.logger.Info("account closed", "accountID", account.ID, "userID", userID, "sweptCents", sweepCents, "interestCents", interestCents,
+		"cancelledSchedules", cancelledSchedules, "cancelledOrders", cancelledOrders, "removedMembers", removedMembers)
+	account.Status = models.AccountStatusClosed
+	account.BalanceCents = 0
+	account.InterestCarryMicros = 0
+	setDerivedBalances(account)
+	return account, transaction, nil
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/internal/repository"
+	"mini-banking-platform/pkg/interest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCloseAccount_SweepsBalanceAndHidesAccount(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	accounts := NewAccountService(repos.Account, repos.Transaction, repos.Currency, logger)
+	transactions := newTestTransactionService(repos, logger)
+	ctx := context.Background()
+
+	createFXSystemAccounts(t, db)
+
+	user := createTestUser(t, db, "closer@test.com")
+	createTestAccount(t, db, user.ID, "USD", 20000)
+	eur := createTestAccount(t, db, user.ID, "EUR", 0)
+	other := createTestUser(t, db, "other@test.com")
+	otherUSD := createTestAccount(t, db, other.ID, "USD", 0)
+
+	pocket, err := accounts.OpenAccount(ctx, user.ID, dto.OpenAccountRequest{Currency: "USD", Name: "Old"}, "0.02")
+	if err != nil {
+		t.Fatalf("OpenAccount failed: %v", err)
+	}
+	if _, err := transactions.Transfer(ctx, user.ID, dto.TransferRequest{ToAccountID: pocket.ID, Currency: "USD", AmountCents: 10000}); err != nil {
+		t.Fatalf("Transfer to pocket failed: %v", err)
+	}
+
+	if _, _, err := transactions.CloseAccount(ctx, user.ID, pocket.ID, dto.CloseAccountRequest{}); err == nil {
+		t.Error("Expected closing a funded account without a sweep target to fail")
+	}
+	if _, _, err := transactions.CloseAccount(ctx, user.ID, pocket.ID, dto.CloseAccountRequest{ToAccountID: otherUSD.ID}); err == nil {
+		t.Error("Expected sweeping to another user's account to be rejected")
+	}
+	if _, _, err := transactions.CloseAccount(ctx, other.ID, pocket.ID, dto.CloseAccountRequest{ToAccountID: otherUSD.ID}); err != errorsx.ErrAccountNotFound {
+		t.Errorf("Expected ErrAccountNotFound for a stranger, got %v", err)
+	}
+
+	closed, sweep, err := transactions.CloseAccount(ctx, user.ID, pocket.ID, dto.CloseAccountRequest{ToAccountID: eur.ID})
+	if err != nil {
+		t.Fatalf("CloseAccount failed: %v", err)
+	}
+	if closed.Status != models.AccountStatusClosed {
+		t.Errorf("Expected closed status, got %s", closed.Status)
+	}
+	if sweep == nil || sweep.ToAmountCents == nil || *sweep.ToAmountCents != 9200 {
+		t.Fatalf("Expected a 9200 EUR sweep, got %+v", sweep)
+	}
+
+	var pocketBalance, eurBalance int64
+	db.Get(&pocketBalance, "SELECT balance_cents FROM accounts WHERE id = $1", pocket.ID)
+	db.Get(&eurBalance, "SELECT balance_cents FROM accounts WHERE id = $1", eur.ID)
+	if pocketBalance != 0 || eurBalance != 9200 {
+		t.Errorf("Expected balances 0 and 9200, got %d and %d", pocketBalance, eurBalance)
+	}
+	ledgerSum, err := repos.Transaction.GetLedgerSumCents(ctx, pocket.ID)
+	if err != nil || ledgerSum != 0 {
+		t.Errorf("Expected the closed account's ledger to sum to 0, got %d, %v", ledgerSum, err)
+	}
+
+	visible, err := accounts.GetUserAccounts(ctx, user.ID, false)
+	if err != nil {
+		t.Fatalf("GetUserAccounts failed: %v", err)
+	}
+	for _, account := range visible {
+		if account.ID == pocket.ID {
+			t.Error("Expected the closed account to be hidden by default")
+		}
+	}
+	all, err := accounts.GetUserAccounts(ctx, user.ID, true)
+	if err != nil {
+		t.Fatalf("GetUserAccounts failed: %v", err)
+	}
+	if len(all) != len(visible)+1 {
+		t.Errorf("Expected include_closed to add the closed account, got %d vs %d", len(all), len(visible))
+	}
+
+	if _, err := transactions.Transfer(ctx, user.ID, dto.TransferRequest{ToAccountID: pocket.ID, Currency: "USD", AmountCents: 100}); err != errorsx.ErrAccountClosed {
+		t.Errorf("Expected ErrAccountClosed for a transfer to the closed account, got %v", err)
+	}
+	if _, err := accounts.OpenAccount(ctx, user.ID, dto.OpenAccountRequest{Currency: "USD", Name: "Old"}, "0.02"); err != nil {
+		t.Errorf("Expected the pocket name to be reusable after closing, got %v", err)
+	}
+}
+
+func TestCloseAccount_PaysOutPendingInterest(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	accounts := NewAccountService(repos.Account, repos.Transaction, repos.Currency, logger)
+	transactions := newTestTransactionService(repos, logger)
+	interestService := NewInterestService(repos.Interest, repos.Account, repos.Transaction, interest.Actual365, logger)
+	ctx := context.Background()
+
+	createInterestSystemUser(t, db)
+	user := createTestUser(t, db, "closing-saver@test.com")
+	primary := createTestAccount(t, db, user.ID, "USD", 2000000)
+
+	savings, err := accounts.OpenAccount(ctx, user.ID, dto.OpenAccountRequest{Currency: "USD", Name: "Rainy day", Type: "savings"}, "0.0365")
+	if err != nil {
+		t.Fatalf("OpenAccount failed: %v", err)
+	}
+	if _, err := transactions.Transfer(ctx, user.ID, dto.TransferRequest{ToAccountID: savings.ID, Currency: "USD", AmountCents: 1000005}); err != nil {
+		t.Fatalf("Transfer to savings failed: %v", err)
+	}
+
+	// Three finished days at 100 cents and 500 micros each: the first is
+	// already accrued, the other two are left for the closure to accrue.
+	opened := truncateToDay(time.Now()).AddDate(0, 0, -3)
+	if _, err := db.Exec(`UPDATE accounts SET created_at = $1, interest_carry_micros = 999000 WHERE id = $2`, opened, savings.ID); err != nil {
+		t.Fatalf("Failed to backdate account: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE ledger_entries SET created_at = $1 WHERE account_id = $2`, opened, savings.ID); err != nil {
+		t.Fatalf("Failed to backdate ledger entries: %v", err)
+	}
+	if created, err := interestService.AccrueDay(ctx, opened); err != nil || created != 1 {
+		t.Fatalf("Expected 1 accrual, got %d, %v", created, err)
+	}
+
+	closed, _, err := transactions.CloseAccount(ctx, user.ID, savings.ID, dto.CloseAccountRequest{ToAccountID: primary.ID})
+	if err != nil {
+		t.Fatalf("CloseAccount failed: %v", err)
+	}
+	if closed.InterestCarryMicros != 0 {
+		t.Errorf("Expected the carry to be cleared, got %d", closed.InterestCarryMicros)
+	}
+
+	// 3 * 100,000,500 + 999,000 micros pays 301 cents.
+	var savingsBalance, primaryBalance, pending, accrued int64
+	db.Get(&savingsBalance, "SELECT balance_cents FROM accounts WHERE id = $1", savings.ID)
+	db.Get(&primaryBalance, "SELECT balance_cents FROM accounts WHERE id = $1", primary.ID)
+	db.Get(&pending, "SELECT COUNT(*) FROM interest_accruals WHERE account_id = $1 AND capitalized_at IS NULL", savings.ID)
+	db.Get(&accrued, "SELECT COUNT(*) FROM interest_accruals WHERE account_id = $1", savings.ID)
+	if savingsBalance != 0 || primaryBalance != 2000000-1000005+1000005+301 {
+		t.Errorf("Expected balances 0 and %d, got %d and %d", 2000000+301, savingsBalance, primaryBalance)
+	}
+	if accrued != 3 || pending != 0 {
+		t.Errorf("Expected 3 accruals, all capitalized, got %d with %d pending", accrued, pending)
+	}
+
+	expense, err := repos.Account.FindByUserAndCurrency(ctx, models.InterestSystemUserID, "USD")
+	if err != nil {
+		t.Fatalf("Interest expense account not found: %v", err)
+	}
+	if expense.BalanceCents != -301 {
+		t.Errorf("Expected expense balance -301, got %d", expense.BalanceCents)
+	}
+	ledgerSum, err := repos.Transaction.GetLedgerSumCents(ctx, savings.ID)
+	if err != nil || ledgerSum != 0 {
+		t.Errorf("Expected the closed account's ledger to sum to 0, got %d, %v", ledgerSum, err)
+	}
+}
+
+func TestCloseAccount_CancelsSchedulesOrdersAndMembers(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	accounts := NewAccountService(repos.Account, repos.Transaction, repos.Currency, logger)
+	members := NewAccountMemberService(repos.Account, repos.User, logger)
+	transactions := newTestTransactionService(repos, logger)
+	scheduled := NewScheduledTransferService(repos.Scheduled, transactions, logger)
+	orders := NewStandingOrderService(repos.Standing, repos.Transaction, transactions, logger)
+	ctx := context.Background()
+
+	user := createTestUser(t, db, "closer@test.com")
+	primary := createTestAccount(t, db, user.ID, "USD", 20000)
+	partner := createTestUser(t, db, "partner@test.com")
+	partnerUSD := createTestAccount(t, db, partner.ID, "USD", 5000)
+
+	pocket, err := accounts.OpenAccount(ctx, user.ID, dto.OpenAccountRequest{Currency: "USD", Name: "Shared"}, "0")
+	if err != nil {
+		t.Fatalf("OpenAccount failed: %v", err)
+	}
+	if _, err := members.AddMember(ctx, user.ID, pocket.ID, dto.AddAccountMemberRequest{UserID: partner.ID, Role: models.AccountRoleCanTransact}); err != nil {
+		t.Fatalf("AddMember failed: %v", err)
+	}
+
+	tomorrow := time.Now().UTC().Add(24 * time.Hour)
+	fromPocket, err := scheduled.Schedule(ctx, user.ID, dto.ScheduleTransferRequest{
+		TransferRequest: dto.TransferRequest{FromAccountID: pocket.ID, ToAccountID: partnerUSD.ID, Currency: "USD", AmountCents: 100},
+		ExecuteAt:       tomorrow,
+	})
+	if err != nil {
+		t.Fatalf("Schedule from the pocket failed: %v", err)
+	}
+	toPocket, err := scheduled.Schedule(ctx, partner.ID, dto.ScheduleTransferRequest{
+		TransferRequest: dto.TransferRequest{ToAccountNumber: pocket.AccountNumber, Currency: "USD", AmountCents: 100},
+		ExecuteAt:       tomorrow,
+	})
+	if err != nil {
+		t.Fatalf("Schedule to the pocket failed: %v", err)
+	}
+	unrelated, err := scheduled.Schedule(ctx, user.ID, dto.ScheduleTransferRequest{
+		TransferRequest: dto.TransferRequest{FromAccountID: primary.ID, ToAccountID: partnerUSD.ID, Currency: "USD", AmountCents: 100},
+		ExecuteAt:       tomorrow,
+	})
+	if err != nil {
+		t.Fatalf("Schedule between other accounts failed: %v", err)
+	}
+
+	today := time.Now().UTC().Format("2006-01-02")
+	funding, err := orders.CreateStandingOrder(ctx, partner.ID, dto.CreateStandingOrderRequest{
+		FromAccountID: partnerUSD.ID, ToAccountID: pocket.ID, AmountCents: 100, Frequency: "weekly", StartOn: today,
+	})
+	if err != nil {
+		t.Fatalf("CreateStandingOrder to the pocket failed: %v", err)
+	}
+	paused, err := orders.CreateStandingOrder(ctx, user.ID, dto.CreateStandingOrderRequest{
+		FromAccountID: pocket.ID, ToAccountID: partnerUSD.ID, AmountCents: 100, Frequency: "weekly", StartOn: today,
+	})
+	if err != nil {
+		t.Fatalf("CreateStandingOrder from the pocket failed: %v", err)
+	}
+	if _, err := orders.Pause(ctx, user.ID, paused.ID); err != nil {
+		t.Fatalf("Pause failed: %v", err)
+	}
+
+	if _, _, err := transactions.CloseAccount(ctx, user.ID, pocket.ID, dto.CloseAccountRequest{}); err != nil {
+		t.Fatalf("CloseAccount failed: %v", err)
+	}
+
+	for _, id := range []string{fromPocket.ID, toPocket.ID} {
+		transfer, err := repos.Scheduled.FindByID(ctx, id)
+		if err != nil {
+			t.Fatalf("FindByID failed: %v", err)
+		}
+		if transfer.Status != models.ScheduledTransferStatusCancelled {
+			t.Errorf("Expected scheduled transfer %s to be cancelled, got %s", id, transfer.Status)
+		}
+	}
+	transfer, err := repos.Scheduled.FindByID(ctx, unrelated.ID)
+	if err != nil || transfer.Status != models.ScheduledTransferStatusScheduled {
+		t.Errorf("Expected the unrelated transfer to stay scheduled, got %+v, %v", transfer, err)
+	}
+
+	for _, id := range []string{funding.ID, paused.ID} {
+		order, err := repos.Standing.FindByID(ctx, id)
+		if err != nil {
+			t.Fatalf("FindByID failed: %v", err)
+		}
+		if order.Status != models.StandingOrderStatusCancelled || order.NextRunOn != nil {
+			t.Errorf("Expected standing order %s to be cancelled with no next run, got %+v", id, order)
+		}
+	}
+	executed, err := orders.ExecuteDue(ctx, time.Now())
+	if err != nil {
+		t.Fatalf("ExecuteDue failed: %v", err)
+	}
+	if executed != 0 {
+		t.Errorf("Expected no standing order runs into the closed account, got %d", executed)
+	}
+
+	var memberCount int
+	if err := db.Get(&memberCount, "SELECT COUNT(*) FROM account_members WHERE account_id = $1", pocket.ID); err != nil {
+		t.Fatalf("Counting members failed: %v", err)
+	}
+	if memberCount != 0 {
+		t.Errorf("Expected the closed account's members to be removed, got %d", memberCount)
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/internal/repository"
+	"strings"
+)
+
+// accountRole returns the user's role on the account. The account holder is
+// always an owner; users who are neither holder nor member get
+// ErrAccountNotFound so the account's existence is not revealed.
+func accountRole(ctx context.Context, accountRepo *repository.AccountRepository, account *models.Account, userID string) (string, error) {
+	if account.UserID == userID {
+		return models.AccountRoleOwner, nil
+	}
+	return accountRepo.FindMemberRole(ctx, account.ID, userID)
+}
+
+// requireAccountRole checks that the user holds at least the wanted role:
+// owners can do everything, can-transact members can also move money and
+// view-only members can only read.
+func requireAccountRole(ctx context.Context, accountRepo *repository.AccountRepository, account *models.Account, userID, wanted string) error {
+	role, err := accountRole(ctx, accountRepo, account, userID)
+	if err != nil {
+		return err
+	}
+	switch wanted {
+	case models.AccountRoleViewOnly:
+		return nil
+	case models.AccountRoleCanTransact:
+		if role == models.AccountRoleOwner || role == models.AccountRoleCanTransact {
+			return nil
+		}
+	case models.AccountRoleOwner:
+		if role == models.AccountRoleOwner {
+			return nil
+		}
+	}
+	return errorsx.ErrAccountAccessDenied
+}
+
+type AccountMemberService struct {
+	accountRepo *repository.AccountRepository
+	userRepo    *repository.UserRepository
+	logger      *slog.Logger
+}
+
+func NewAccountMemberService(accountRepo *repository.AccountRepository, userRepo *repository.UserRepository, logger *slog.Logger) *AccountMemberService {
+	return &AccountMemberService{
+		accountRepo: accountRepo,
+		userRepo:    userRepo,
+		logger:      logger,
+	}
+}
+
+// GetMembers lists everyone with access to the account, the account holder
+// first.
+func (s *AccountMemberService) GetMembers(ctx context.Context, userID, accountID string) ([]models.AccountMember, error) {
+	account, err := s.accountRepo.FindByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireAccountRole(ctx, s.accountRepo, account, userID, models.AccountRoleViewOnly); err != nil {
+		return nil, err
+	}
+
+	holder, err := s.userRepo.FindByID(ctx, account.UserID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.accountRepo.FindMembers(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]models.AccountMember{{
+		AccountID: account.ID,
+		UserID:    holder.ID,
+		Email:     holder.Email,
+		Role:      models.AccountRoleOwner,
+		CreatedAt: account.CreatedAt,
+	}}, members...), nil
+}
+
+// AddMember invites a user, by email or ID, onto the account, or changes the
+// role of an existing member. Only owners can manage members.
+func (s *AccountMemberService) AddMember(ctx context.Context, userID, accountID string, req dto.AddAccountMemberRequest) (*models.AccountMember, error) {
+	account, err := s.accountRepo.FindByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireAccountRole(ctx, s.accountRepo, account, userID, models.AccountRoleOwner); err != nil {
+		return nil, err
+	}
+	if account.Status == models.AccountStatusClosed {
+		return nil, errorsx.ErrAccountClosed
+	}
+
+	var member *models.User
+	if strings.Contains(req.UserID, "@") {
+		member, err = s.userRepo.FindByEmail(ctx, req.UserID)
+	} else {
+		member, err = s.userRepo.FindByID(ctx, req.UserID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if member.ID == account.UserID {
+		return nil, errorsx.BadRequest("the account holder is already an owner")
+	}
+
+	saved := &models.AccountMember{
+		AccountID: account.ID,
+		UserID:    member.ID,
+		Email:     member.Email,
+		Role:      req.Role,
+		AddedBy:   &userID,
+	}
+	if err := s.accountRepo.SaveMember(ctx, saved); err != nil {
+		s.logger.Error("failed to add account member", "error", err, "accountID", account.ID, "memberID", member.ID)
+		return nil, fmt.Errorf("error adding account member: %w", err)
+	}
+
+	s.logger.Info("account member saved", "accountID", account.ID, "memberID", member.ID, "role", req.Role, "by", userID)
+	return saved, nil
+}
+
+// RemoveMember revokes a member's access. Owners can remove anyone but the
+// account holder; other members can only remove themselves.
+func (s *AccountMemberService) RemoveMember(ctx context.Context, userID, accountID, memberID string) error {
+	account, err := s.accountRepo.FindByID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	wanted := models.AccountRoleOwner
+	if memberID == userID {
+		wanted = models.AccountRoleViewOnly
+	}
+	if err := requireAccountRole(ctx, s.accountRepo, account, userID, wanted); err != nil {
+		return err
+	}
+	if memberID == account.UserID {
+		return errorsx.BadRequest("the account holder cannot be removed")
+	}
+
+	if err := s.accountRepo.DeleteMember(ctx, account.ID, memberID); err != nil {
+		return err
+	}
+
+	s.logger.Info("account member removed", "accountID", account.ID, "memberID", memberID, "by", userID)
+	return nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/internal/repository"
+	"os"
+	"testing"
+)
+
+func TestAccountMembers_RolesEnforced(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	accounts := NewAccountService(repos.Account, repos.Transaction, repos.Currency, logger)
+	members := NewAccountMemberService(repos.Account, repos.User, logger)
+	transactions := newTestTransactionService(repos, logger)
+	ctx := context.Background()
+
+	holder := createTestUser(t, db, "holder@test.com")
+	partner := createTestUser(t, db, "partner@test.com")
+	viewer := createTestUser(t, db, "viewer@test.com")
+	outsider := createTestUser(t, db, "outsider@test.com")
+	joint := createTestAccount(t, db, holder.ID, "USD", 100000)
+	createTestAccount(t, db, outsider.ID, "USD", 0)
+
+	if _, err := members.AddMember(ctx, holder.ID, joint.ID, dto.AddAccountMemberRequest{UserID: partner.Email, Role: models.AccountRoleCanTransact}); err != nil {
+		t.Fatalf("AddMember failed: %v", err)
+	}
+	if _, err := members.AddMember(ctx, holder.ID, joint.ID, dto.AddAccountMemberRequest{UserID: viewer.ID, Role: models.AccountRoleViewOnly}); err != nil {
+		t.Fatalf("AddMember failed: %v", err)
+	}
+	if _, err := members.AddMember(ctx, partner.ID, joint.ID, dto.AddAccountMemberRequest{UserID: outsider.ID, Role: models.AccountRoleOwner}); !errors.Is(err, errorsx.ErrAccountAccessDenied) {
+		t.Errorf("Expected a non-owner invite to be denied, got %v", err)
+	}
+
+	list, err := members.GetMembers(ctx, viewer.ID, joint.ID)
+	if err != nil {
+		t.Fatalf("GetMembers failed: %v", err)
+	}
+	if len(list) != 3 || list[0].UserID != holder.ID {
+		t.Errorf("Expected the holder and two members, got %+v", list)
+	}
+
+	shared, err := accounts.GetUserAccounts(ctx, viewer.ID, false)
+	if err != nil {
+		t.Fatalf("GetUserAccounts failed: %v", err)
+	}
+	if len(shared) != 1 || shared[0].ID != joint.ID || shared[0].Role != models.AccountRoleViewOnly {
+		t.Errorf("Expected the joint account listed as view_only, got %+v", shared)
+	}
+
+	if _, err := accounts.GetAccountBalance(ctx, viewer.ID, joint.ID); err != nil {
+		t.Errorf("Expected a view-only member to see the balance, got %v", err)
+	}
+	if _, err := accounts.GetAccountBalance(ctx, outsider.ID, joint.ID); !errors.Is(err, errorsx.ErrAccountNotFound) {
+		t.Errorf("Expected ErrAccountNotFound for an outsider, got %v", err)
+	}
+
+	transfer := dto.TransferRequest{FromAccountID: joint.ID, ToUserID: outsider.ID, AmountCents: 2500}
+	if _, err := transactions.Transfer(ctx, viewer.ID, transfer); !errors.Is(err, errorsx.ErrAccountAccessDenied) {
+		t.Errorf("Expected a view-only transfer to be denied, got %v", err)
+	}
+	if _, err := transactions.Transfer(ctx, partner.ID, transfer); err != nil {
+		t.Fatalf("Expected a can-transact member to transfer, got %v", err)
+	}
+
+	if err := members.RemoveMember(ctx, viewer.ID, joint.ID, partner.ID); !errors.Is(err, errorsx.ErrAccountAccessDenied) {
+		t.Errorf("Expected a view-only member removing another to be denied, got %v", err)
+	}
+	if err := members.RemoveMember(ctx, holder.ID, joint.ID, holder.ID); err == nil {
+		t.Error("Expected the account holder to be irremovable")
+	}
+	if err := members.RemoveMember(ctx, holder.ID, joint.ID, partner.ID); err != nil {
+		t.Fatalf("RemoveMember failed: %v", err)
+	}
+	if _, err := transactions.Transfer(ctx, partner.ID, transfer); !errors.Is(err, errorsx.ErrAccountNotFound) {
+		t.Errorf("Expected a removed member to lose access, got %v", err)
+	}
+
+	balance, err := accounts.GetAccountBalance(ctx, holder.ID, joint.ID)
+	if err != nil {
+		t.Fatalf("GetAccountBalance failed: %v", err)
+	}
+	if balance.BalanceCents != 97500 {
+		t.Errorf("Expected balance 97500, got %d", balance.BalanceCents)
+	}
+}
@@ -3,9 +3,12 @@ package service
 import (
 	"context"
 	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
 	"mini-banking-platform/internal/repository"
 	"os"
 	"testing"
+	"time"
 )
 
 func TestReconciliation_MatchesLedger(t *testing.T) {
@@ -14,7 +17,7 @@ func TestReconciliation_MatchesLedger(t *testing.T) {
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 	repos := repository.NewRepositories(db, logger)
-	accountService := NewAccountService(repos.Account, repos.Transaction, logger)
+	accountService := NewAccountService(repos.Account, repos.Transaction, repos.Currency, logger)
 
 	user := createTestUser(t, db, "reconcile@test.com")
 	createTestAccount(t, db, user.ID, "USD", 100000)
@@ -48,13 +51,13 @@ func TestGetUserAccounts(t *testing.T) {
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 	repos := repository.NewRepositories(db, logger)
-	service := NewAccountService(repos.Account, repos.Transaction, logger)
+	service := NewAccountService(repos.Account, repos.Transaction, repos.Currency, logger)
 
 	user := createTestUser(t, db, "accounts@test.com")
 	createTestAccount(t, db, user.ID, "USD", 100000)
 	createTestAccount(t, db, user.ID, "EUR", 50000)
 
-	accounts, err := service.GetUserAccounts(context.Background(), user.ID)
+	accounts, err := service.GetUserAccounts(context.Background(), user.ID, false)
 	if err != nil {
 		t.Fatalf("Failed to get user accounts: %v", err)
 	}
@@ -82,7 +85,7 @@ func TestGetAccountBalance_Success(t *testing.T) {
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 	repos := repository.NewRepositories(db, logger)
-	service := NewAccountService(repos.Account, repos.Transaction, logger)
+	service := NewAccountService(repos.Account, repos.Transaction, repos.Currency, logger)
 
 	user := createTestUser(t, db, "balance@test.com")
 	account := createTestAccount(t, db, user.ID, "USD", 100000)
@@ -103,7 +106,7 @@ func TestGetAccountBalance_UnauthorizedAccess(t *testing.T) {
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 	repos := repository.NewRepositories(db, logger)
-	service := NewAccountService(repos.Account, repos.Transaction, logger)
+	service := NewAccountService(repos.Account, repos.Transaction, repos.Currency, logger)
 
 	userA := createTestUser(t, db, "usera@test.com")
 	userB := createTestUser(t, db, "userb@test.com")
@@ -115,3 +118,253 @@ func TestGetAccountBalance_UnauthorizedAccess(t *testing.T) {
 	}
 }
 
+
+func TestOpenAccount_RejectsDuplicateCurrency(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	service := NewAccountService(repos.Account, repos.Transaction, repos.Currency, logger)
+
+	user := createTestUser(t, db, "open@test.com")
+	createTestAccount(t, db, user.ID, "USD", 0)
+
+	_, err := service.OpenAccount(context.Background(), user.ID, dto.OpenAccountRequest{Currency: "USD"}, "0.02")
+	if err != errorsx.ErrAccountExists {
+		t.Errorf("Expected ErrAccountExists, got %v", err)
+	}
+
+	_, err = service.OpenAccount(context.Background(), user.ID, dto.OpenAccountRequest{Currency: "GBP"}, "0.02")
+	if err != errorsx.ErrCurrencyDisabled {
+		t.Errorf("Expected ErrCurrencyDisabled, got %v", err)
+	}
+}
+
+func TestOpenAccount_NamedPockets(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	service := NewAccountService(repos.Account, repos.Transaction, repos.Currency, logger)
+	ctx := context.Background()
+
+	user := createTestUser(t, db, "pockets@test.com")
+	primary := createTestAccount(t, db, user.ID, "USD", 0)
+
+	rent, err := service.OpenAccount(ctx, user.ID, dto.OpenAccountRequest{Currency: "USD", Name: "Rent"}, "0.02")
+	if err != nil {
+		t.Fatalf("OpenAccount failed: %v", err)
+	}
+	if rent.Name == nil || *rent.Name != "Rent" {
+		t.Errorf("Expected pocket named Rent, got %v", rent.Name)
+	}
+	if _, err := service.OpenAccount(ctx, user.ID, dto.OpenAccountRequest{Currency: "EUR", Name: "Holiday"}, "0.02"); err != nil {
+		t.Fatalf("OpenAccount in EUR failed: %v", err)
+	}
+
+	_, err = service.OpenAccount(ctx, user.ID, dto.OpenAccountRequest{Currency: "EUR", Name: "rent"}, "0.02")
+	if err != errorsx.ErrAccountExists {
+		t.Errorf("Expected ErrAccountExists for duplicate name, got %v", err)
+	}
+
+	found, err := repos.Account.FindByUserAndCurrency(ctx, user.ID, "USD")
+	if err != nil {
+		t.Fatalf("FindByUserAndCurrency failed: %v", err)
+	}
+	if found.ID != primary.ID {
+		t.Errorf("Expected primary account %s, got %s", primary.ID, found.ID)
+	}
+
+	accounts, err := service.GetUserAccounts(ctx, user.ID, false)
+	if err != nil {
+		t.Fatalf("GetUserAccounts failed: %v", err)
+	}
+	if len(accounts) != 3 {
+		t.Errorf("Expected 3 accounts, got %d", len(accounts))
+	}
+}
+
+func TestAccountStatus_EnforcedOnPostings(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	accounts := NewAccountService(repos.Account, repos.Transaction, repos.Currency, logger)
+	transactions := newTestTransactionService(repos, logger)
+	ctx := context.Background()
+
+	admin := createTestUser(t, db, "compliance@test.com")
+	userA := createTestUser(t, db, "frozen@test.com")
+	userB := createTestUser(t, db, "sender@test.com")
+	accountA := createTestAccount(t, db, userA.ID, "USD", 10000)
+	createTestAccount(t, db, userB.ID, "USD", 10000)
+
+	_, err := accounts.SetStatus(ctx, admin.ID, accountA.ID, dto.SetAccountStatusRequest{Status: "debit_frozen", Reason: "KYC review"})
+	if err != nil {
+		t.Fatalf("SetStatus failed: %v", err)
+	}
+
+	_, err = transactions.Transfer(ctx, userA.ID, dto.TransferRequest{ToUserID: userB.ID, Currency: "USD", AmountCents: 100})
+	if err != errorsx.ErrAccountFrozen {
+		t.Errorf("Expected ErrAccountFrozen for debit, got %v", err)
+	}
+	if _, err := transactions.Transfer(ctx, userB.ID, dto.TransferRequest{ToUserID: userA.ID, Currency: "USD", AmountCents: 100}); err != nil {
+		t.Errorf("Expected credit to debit-frozen account to succeed, got %v", err)
+	}
+
+	_, err = accounts.SetStatus(ctx, admin.ID, accountA.ID, dto.SetAccountStatusRequest{Status: "frozen", Reason: "court order"})
+	if err != nil {
+		t.Fatalf("SetStatus failed: %v", err)
+	}
+	_, err = transactions.Transfer(ctx, userB.ID, dto.TransferRequest{ToUserID: userA.ID, Currency: "USD", AmountCents: 100})
+	if err != errorsx.ErrAccountFrozen {
+		t.Errorf("Expected ErrAccountFrozen for credit, got %v", err)
+	}
+
+	tx, err := repos.Account.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	if err := repos.Account.UpdateBalanceCents(ctx, tx, accountA.ID, 1); err != errorsx.ErrAccountFrozen {
+		t.Errorf("Expected posting to frozen account to be refused, got %v", err)
+	}
+	tx.Rollback()
+
+	_, err = accounts.SetStatus(ctx, admin.ID, accountA.ID, dto.SetAccountStatusRequest{Status: "closed", Reason: "customer request"})
+	if err == nil {
+		t.Error("Expected closing a funded account to fail")
+	}
+
+	history, err := accounts.GetStatusHistory(ctx, accountA.ID)
+	if err != nil {
+		t.Fatalf("GetStatusHistory failed: %v", err)
+	}
+	if len(history) != 2 || history[1].ToStatus != "frozen" || history[1].Reason != "court order" {
+		t.Errorf("Expected two recorded changes, got %+v", history)
+	}
+}
+
+func TestCreditLimit_AllowsOverdraftUpToLimit(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	accounts := NewAccountService(repos.Account, repos.Transaction, repos.Currency, logger)
+	transactions := newTestTransactionService(repos, logger)
+	ctx := context.Background()
+
+	userA := createTestUser(t, db, "overdraft@test.com")
+	userB := createTestUser(t, db, "payee@test.com")
+	accountA := createTestAccount(t, db, userA.ID, "USD", 1000)
+	createTestAccount(t, db, userB.ID, "USD", 0)
+
+	transfer := dto.TransferRequest{ToUserID: userB.ID, Currency: "USD", AmountCents: 4000}
+	if _, err := transactions.Transfer(ctx, userA.ID, transfer); err != errorsx.ErrInsufficientFunds {
+		t.Fatalf("Expected ErrInsufficientFunds without a limit, got %v", err)
+	}
+
+	if _, err := accounts.SetCreditLimit(ctx, accountA.ID, dto.SetCreditLimitRequest{CreditLimitCents: 5000}); err != nil {
+		t.Fatalf("SetCreditLimit failed: %v", err)
+	}
+	if _, err := transactions.Transfer(ctx, userA.ID, transfer); err != nil {
+		t.Fatalf("Transfer within limit failed: %v", err)
+	}
+
+	transfer.AmountCents = 2001
+	if _, err := transactions.Transfer(ctx, userA.ID, transfer); err != errorsx.ErrInsufficientFunds {
+		t.Errorf("Expected ErrInsufficientFunds beyond the limit, got %v", err)
+	}
+
+	list, err := accounts.GetUserAccounts(ctx, userA.ID, false)
+	if err != nil {
+		t.Fatalf("GetUserAccounts failed: %v", err)
+	}
+	if len(list) != 1 || list[0].BalanceCents != -3000 || list[0].OverdraftUsedCents != 3000 {
+		t.Errorf("Expected 3000 overdraft in use, got %+v", list)
+	}
+	tx, err := repos.Account.BeginTx(ctx)
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	spendable, err := repos.Account.GetAvailableCents(ctx, tx, accountA.ID)
+	tx.Rollback()
+	if err != nil {
+		t.Fatalf("GetAvailableCents failed: %v", err)
+	}
+	if len(list) == 1 && (list[0].AvailableBalanceCents != 2000 || list[0].AvailableBalanceCents != spendable) {
+		t.Errorf("Expected 2000 available including the limit, as postings see it (%d), got %d", spendable, list[0].AvailableBalanceCents)
+	}
+
+	if _, err := accounts.SetCreditLimit(ctx, accountA.ID, dto.SetCreditLimitRequest{CreditLimitCents: 1000}); err == nil {
+		t.Error("Expected lowering the limit below usage to fail")
+	}
+}
+
+func TestBalanceHistory_FromLedger(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	accounts := NewAccountService(repos.Account, repos.Transaction, repos.Currency, logger)
+	transactions := newTestTransactionService(repos, logger)
+	ctx := context.Background()
+
+	userA := createTestUser(t, db, "history@test.com")
+	userB := createTestUser(t, db, "history-payee@test.com")
+	accountA := createTestAccount(t, db, userA.ID, "USD", 10000)
+	createTestAccount(t, db, userB.ID, "USD", 0)
+
+	transfer := dto.TransferRequest{ToUserID: userB.ID, Currency: "USD", AmountCents: 2500}
+	if _, err := transactions.Transfer(ctx, userA.ID, transfer); err != nil {
+		t.Fatalf("Transfer failed: %v", err)
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	depositAt := today.AddDate(0, 0, -5).Add(10 * time.Hour)
+	transferAt := today.AddDate(0, 0, -3).Add(15 * time.Hour)
+	if _, err := db.Exec(`UPDATE ledger_entries SET created_at = $1 WHERE account_id = $2 AND amount_cents > 0`, depositAt, accountA.ID); err != nil {
+		t.Fatalf("Failed to backdate deposit: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE ledger_entries SET created_at = $1 WHERE account_id = $2 AND amount_cents < 0`, transferAt, accountA.ID); err != nil {
+		t.Fatalf("Failed to backdate transfer: %v", err)
+	}
+
+	checks := map[time.Time]int64{
+		depositAt.Add(-time.Second):  0,
+		depositAt:                    10000,
+		transferAt.Add(-time.Second): 10000,
+		transferAt:                   7500,
+	}
+	for at, want := range checks {
+		_, got, err := accounts.GetAccountBalanceAt(ctx, userA.ID, accountA.ID, at)
+		if err != nil {
+			t.Fatalf("GetAccountBalanceAt failed: %v", err)
+		}
+		if got != want {
+			t.Errorf("Balance at %s: expected %d, got %d", at.Format(time.RFC3339), want, got)
+		}
+	}
+
+	points, err := accounts.GetBalanceHistory(ctx, userA.ID, accountA.ID, today.AddDate(0, 0, -6), today.AddDate(0, 0, -2))
+	if err != nil {
+		t.Fatalf("GetBalanceHistory failed: %v", err)
+	}
+	want := []int64{0, 10000, 10000, 7500, 7500}
+	if len(points) != len(want) {
+		t.Fatalf("Expected %d points, got %d", len(want), len(points))
+	}
+	for i, p := range points {
+		if p.BalanceCents != want[i] {
+			t.Errorf("Day %s: expected %d, got %d", p.Date, want[i], p.BalanceCents)
+		}
+	}
+
+	if _, err := accounts.GetBalanceHistory(ctx, userB.ID, accountA.ID, today.AddDate(0, 0, -6), today); err != errorsx.ErrAccountNotFound {
+		t.Errorf("Expected ErrAccountNotFound for another user, got %v", err)
+	}
+}
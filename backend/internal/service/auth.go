@@ -17,21 +17,56 @@ type AuthService struct {
 	userRepo        *repository.UserRepository
 	accountRepo     *repository.AccountRepository
 	transactionRepo *repository.TransactionRepository
+	currencyRepo    *repository.CurrencyRepository
 	jwtService      *jwt.Service
 	logger          *slog.Logger
 }
 
-func NewAuthService(userRepo *repository.UserRepository, accountRepo *repository.AccountRepository, transactionRepo *repository.TransactionRepository, jwtService *jwt.Service, logger *slog.Logger) *AuthService {
+func NewAuthService(userRepo *repository.UserRepository, accountRepo *repository.AccountRepository, transactionRepo *repository.TransactionRepository, currencyRepo *repository.CurrencyRepository, jwtService *jwt.Service, logger *slog.Logger) *AuthService {
 	return &AuthService{
 		userRepo:        userRepo,
 		accountRepo:     accountRepo,
 		transactionRepo: transactionRepo,
+		currencyRepo:    currencyRepo,
 		jwtService:      jwtService,
 		logger:          logger,
 	}
 }
 
-func (s *AuthService) Register(ctx context.Context, req dto.RegisterRequest, initialBalanceUSDCents, initialBalanceEURCents int64) (*dto.AuthResponse, error) {
+func (s *AuthService) Register(ctx context.Context, req dto.RegisterRequest, initialBalancesCents map[string]int64) (*dto.AuthResponse, error) {
+	user, err := s.createUser(ctx, req, initialBalancesCents, false)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.generateToken(user.ID)
+	if err != nil {
+		s.logger.Error("failed to generate token", "error", err)
+		return nil, fmt.Errorf("error generating token: %w", err)
+	}
+
+	s.logger.Info("user registered successfully", "userID", user.ID, "email", user.Email)
+
+	return &dto.AuthResponse{
+		Token: token,
+		User:  *user,
+	}, nil
+}
+
+// RegisterAdmin creates a new admin user with empty accounts. It never
+// promotes an existing user: an email that is already registered fails with
+// ErrUserExists, so whoever signed up with it first does not become admin.
+func (s *AuthService) RegisterAdmin(ctx context.Context, req dto.RegisterRequest) (*models.User, error) {
+	user, err := s.createUser(ctx, req, nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("admin user created", "userID", user.ID, "email", user.Email)
+	return user, nil
+}
+
+func (s *AuthService) createUser(ctx context.Context, req dto.RegisterRequest, initialBalancesCents map[string]int64, isAdmin bool) (*models.User, error) {
 	if req.Email == "" {
 		return nil, errorsx.BadRequest("email is required")
 	}
@@ -57,10 +92,17 @@ func (s *AuthService) Register(ctx context.Context, req dto.RegisterRequest, ini
 		return nil, fmt.Errorf("error hashing password: %w", err)
 	}
 
+	currencies, err := s.currencyRepo.FindEnabled(ctx)
+	if err != nil {
+		s.logger.Error("failed to load currencies", "error", err)
+		return nil, fmt.Errorf("error loading currencies: %w", err)
+	}
+
 	user := &models.User{
 		Email:     req.Email,
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
+		IsAdmin:   isAdmin,
 	}
 
 	tx, err := s.accountRepo.BeginTx(ctx)
@@ -75,73 +117,44 @@ func (s *AuthService) Register(ctx context.Context, req dto.RegisterRequest, ini
 		return nil, fmt.Errorf("error creating user: %w", err)
 	}
 
-	usdAccount := &models.Account{
-		UserID:       user.ID,
-		Currency:     models.CurrencyUSD,
-		BalanceCents: initialBalanceUSDCents,
-	}
-	if err := s.accountRepo.CreateInTx(ctx, tx, usdAccount); err != nil {
-		s.logger.Error("failed to create USD account", "error", err)
-		return nil, fmt.Errorf("error creating USD account: %w", err)
-	}
-
-	eurAccount := &models.Account{
-		UserID:       user.ID,
-		Currency:     models.CurrencyEUR,
-		BalanceCents: initialBalanceEURCents,
-	}
-	if err := s.accountRepo.CreateInTx(ctx, tx, eurAccount); err != nil {
-		s.logger.Error("failed to create EUR account", "error", err)
-		return nil, fmt.Errorf("error creating EUR account: %w", err)
-	}
+	for _, currency := range currencies {
+		initialBalanceCents := initialBalancesCents[currency.Code]
 
-	if initialBalanceUSDCents > 0 {
-		usdTransaction := &models.Transaction{
-			Type:        models.TransactionTypeInitialDeposit,
-			FromUserID:  user.ID,
-			Currency:    models.CurrencyUSD,
-			AmountCents: initialBalanceUSDCents,
-			Description: "Initial deposit",
+		account := &models.Account{
+			UserID:       user.ID,
+			Currency:     currency.Code,
+			BalanceCents: initialBalanceCents,
 		}
-		if err := s.transactionRepo.CreateInTx(ctx, tx, usdTransaction); err != nil {
-			s.logger.Error("failed to create USD initial transaction", "error", err)
-			return nil, fmt.Errorf("error creating USD initial transaction: %w", err)
+		if err := s.accountRepo.CreateInTx(ctx, tx, account); err != nil {
+			s.logger.Error("failed to create account", "error", err, "currency", currency.Code)
+			return nil, fmt.Errorf("error creating %s account: %w", currency.Code, err)
 		}
 
-		usdLedgerEntry := &models.LedgerEntry{
-			TransactionID: usdTransaction.ID,
-			AccountID:     usdAccount.ID,
-			Currency:      models.CurrencyUSD,
-			AmountCents:   initialBalanceUSDCents,
-		}
-		if err := s.transactionRepo.CreateLedgerEntryInTx(ctx, tx, usdLedgerEntry); err != nil {
-			s.logger.Error("failed to create USD ledger entry", "error", err)
-			return nil, fmt.Errorf("error creating USD ledger entry: %w", err)
+		if initialBalanceCents <= 0 {
+			continue
 		}
-	}
 
-	if initialBalanceEURCents > 0 {
-		eurTransaction := &models.Transaction{
+		depositTransaction := &models.Transaction{
 			Type:        models.TransactionTypeInitialDeposit,
 			FromUserID:  user.ID,
-			Currency:    models.CurrencyEUR,
-			AmountCents: initialBalanceEURCents,
+			Currency:    currency.Code,
+			AmountCents: initialBalanceCents,
 			Description: "Initial deposit",
 		}
-		if err := s.transactionRepo.CreateInTx(ctx, tx, eurTransaction); err != nil {
-			s.logger.Error("failed to create EUR initial transaction", "error", err)
-			return nil, fmt.Errorf("error creating EUR initial transaction: %w", err)
+		if err := s.transactionRepo.CreateInTx(ctx, tx, depositTransaction); err != nil {
+			s.logger.Error("failed to create initial transaction", "error", err, "currency", currency.Code)
+			return nil, fmt.Errorf("error creating %s initial transaction: %w", currency.Code, err)
 		}
 
-		eurLedgerEntry := &models.LedgerEntry{
-			TransactionID: eurTransaction.ID,
-			AccountID:     eurAccount.ID,
-			Currency:      models.CurrencyEUR,
-			AmountCents:   initialBalanceEURCents,
+		ledgerEntry := &models.LedgerEntry{
+			TransactionID: depositTransaction.ID,
+			AccountID:     account.ID,
+			Currency:      currency.Code,
+			AmountCents:   initialBalanceCents,
 		}
-		if err := s.transactionRepo.CreateLedgerEntryInTx(ctx, tx, eurLedgerEntry); err != nil {
-			s.logger.Error("failed to create EUR ledger entry", "error", err)
-			return nil, fmt.Errorf("error creating EUR ledger entry: %w", err)
+		if err := s.transactionRepo.CreateLedgerEntryInTx(ctx, tx, ledgerEntry); err != nil {
+			s.logger.Error("failed to create initial ledger entry", "error", err, "currency", currency.Code)
+			return nil, fmt.Errorf("error creating %s ledger entry: %w", currency.Code, err)
 		}
 	}
 
@@ -150,18 +163,7 @@ func (s *AuthService) Register(ctx context.Context, req dto.RegisterRequest, ini
 		return nil, fmt.Errorf("error committing registration: %w", err)
 	}
 
-	token, err := s.generateToken(user.ID)
-	if err != nil {
-		s.logger.Error("failed to generate token", "error", err)
-		return nil, fmt.Errorf("error generating token: %w", err)
-	}
-
-	s.logger.Info("user registered successfully", "userID", user.ID, "email", user.Email)
-
-	return &dto.AuthResponse{
-		Token: token,
-		User:  *user,
-	}, nil
+	return user, nil
 }
 
 func (s *AuthService) Login(ctx context.Context, req dto.LoginRequest) (*dto.AuthResponse, error) {
@@ -194,6 +196,38 @@ func (s *AuthService) GetUser(ctx context.Context, userID string) (*models.User,
 	return s.userRepo.FindByID(ctx, userID)
 }
 
+// SetPreferredCurrency sets the currency incoming transfers are converted to.
+// An empty currency clears the preference.
+func (s *AuthService) SetPreferredCurrency(ctx context.Context, userID string, req dto.SetPreferredCurrencyRequest) (*models.User, error) {
+	var currency *string
+	if req.Currency != "" {
+		enabled, err := enabledCurrency(ctx, s.currencyRepo, req.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := s.accountRepo.FindByUserAndCurrency(ctx, userID, enabled.Code); err != nil {
+			return nil, err
+		}
+		currency = &enabled.Code
+	}
+
+	if err := s.userRepo.SetPreferredCurrency(ctx, userID, currency); err != nil {
+		s.logger.Error("failed to set preferred currency", "error", err, "userID", userID)
+		return nil, err
+	}
+
+	s.logger.Info("preferred currency updated", "userID", userID, "currency", req.Currency)
+	return s.userRepo.FindByID(ctx, userID)
+}
+
+func (s *AuthService) IsAdmin(ctx context.Context, userID string) (bool, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return user.IsAdmin, nil
+}
+
 func (s *AuthService) generateToken(userID string) (string, error) {
 	return s.jwtService.GenerateToken(userID)
 }
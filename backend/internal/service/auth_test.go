@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"log/slog"
+	"mini-banking-platform/internal/errorsx"
 	"mini-banking-platform/internal/http/dto"
 	"mini-banking-platform/internal/jwt"
 	"mini-banking-platform/internal/repository"
@@ -17,7 +18,7 @@ func TestRegistration_Atomic(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 	repos := repository.NewRepositories(db, logger)
 	jwtService := jwt.NewService("test-secret-key-that-is-long-enough-for-jwt", 168)
-	service := NewAuthService(repos.User, repos.Account, repos.Transaction, jwtService, logger)
+	service := NewAuthService(repos.User, repos.Account, repos.Transaction, repos.Currency, jwtService, logger)
 
 	req := dto.RegisterRequest{
 		Email:     "newuser@test.com",
@@ -26,7 +27,7 @@ func TestRegistration_Atomic(t *testing.T) {
 		LastName:  "User",
 	}
 
-	resp, err := service.Register(context.Background(), req, 100000, 50000)
+	resp, err := service.Register(context.Background(), req, map[string]int64{"USD": 100000, "EUR": 50000})
 	if err != nil {
 		t.Fatalf("Registration failed: %v", err)
 	}
@@ -40,7 +41,7 @@ func TestRegistration_Atomic(t *testing.T) {
 		t.Fatalf("User not found after registration: %v", err)
 	}
 
-	accounts, err := repos.Account.FindByUserID(context.Background(), user.ID)
+	accounts, err := repos.Account.FindByUserID(context.Background(), user.ID, false)
 	if err != nil {
 		t.Fatalf("Failed to get user accounts: %v", err)
 	}
@@ -91,7 +92,7 @@ func TestRegistration_DuplicateEmail(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 	repos := repository.NewRepositories(db, logger)
 	jwtService := jwt.NewService("test-secret-key-that-is-long-enough-for-jwt", 168)
-	service := NewAuthService(repos.User, repos.Account, repos.Transaction, jwtService, logger)
+	service := NewAuthService(repos.User, repos.Account, repos.Transaction, repos.Currency, jwtService, logger)
 
 	req := dto.RegisterRequest{
 		Email:     "duplicate@test.com",
@@ -100,17 +101,58 @@ func TestRegistration_DuplicateEmail(t *testing.T) {
 		LastName:  "User",
 	}
 
-	_, err := service.Register(context.Background(), req, 100000, 50000)
+	_, err := service.Register(context.Background(), req, map[string]int64{"USD": 100000, "EUR": 50000})
 	if err != nil {
 		t.Fatalf("First registration failed: %v", err)
 	}
 
-	_, err = service.Register(context.Background(), req, 100000, 50000)
+	_, err = service.Register(context.Background(), req, map[string]int64{"USD": 100000, "EUR": 50000})
 	if err == nil {
 		t.Error("Expected error for duplicate email registration")
 	}
 }
 
+func TestRegisterAdmin_NeverPromotesExistingUser(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	jwtService := jwt.NewService("test-secret-key-that-is-long-enough-for-jwt", 168)
+	service := NewAuthService(repos.User, repos.Account, repos.Transaction, repos.Currency, jwtService, logger)
+	ctx := context.Background()
+
+	squatter := dto.RegisterRequest{Email: "ops@test.com", Password: "password123", FirstName: "Early", LastName: "Bird"}
+	if _, err := service.Register(ctx, squatter, nil); err != nil {
+		t.Fatalf("Registration failed: %v", err)
+	}
+	if _, err := service.RegisterAdmin(ctx, squatter); err != errorsx.ErrUserExists {
+		t.Errorf("Expected ErrUserExists for a registered email, got %v", err)
+	}
+	existing, err := repos.User.FindByEmail(ctx, squatter.Email)
+	if err != nil {
+		t.Fatalf("FindByEmail failed: %v", err)
+	}
+	if existing.IsAdmin {
+		t.Error("Expected the existing user not to be promoted")
+	}
+
+	admin, err := service.RegisterAdmin(ctx, dto.RegisterRequest{Email: "root@test.com", Password: "s3cret-pass", FirstName: "Platform", LastName: "Admin"})
+	if err != nil {
+		t.Fatalf("RegisterAdmin failed: %v", err)
+	}
+	isAdmin, err := service.IsAdmin(ctx, admin.ID)
+	if err != nil || !isAdmin {
+		t.Errorf("Expected the new user to be admin, got %v, %v", isAdmin, err)
+	}
+
+	var balanceCents int64
+	db.Get(&balanceCents, "SELECT COALESCE(SUM(balance_cents), 0) FROM accounts WHERE user_id = $1", admin.ID)
+	if balanceCents != 0 {
+		t.Errorf("Expected the admin to start without money, got %d", balanceCents)
+	}
+}
+
 func TestLogin_Success(t *testing.T) {
 	db := setupTestDB(t)
 	defer cleanupTestData(t, db)
@@ -118,7 +160,7 @@ func TestLogin_Success(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 	repos := repository.NewRepositories(db, logger)
 	jwtService := jwt.NewService("test-secret-key-that-is-long-enough-for-jwt", 168)
-	service := NewAuthService(repos.User, repos.Account, repos.Transaction, jwtService, logger)
+	service := NewAuthService(repos.User, repos.Account, repos.Transaction, repos.Currency, jwtService, logger)
 
 	regReq := dto.RegisterRequest{
 		Email:     "login@test.com",
@@ -126,7 +168,7 @@ func TestLogin_Success(t *testing.T) {
 		FirstName: "Login",
 		LastName:  "User",
 	}
-	_, err := service.Register(context.Background(), regReq, 100000, 50000)
+	_, err := service.Register(context.Background(), regReq, map[string]int64{"USD": 100000, "EUR": 50000})
 	if err != nil {
 		t.Fatalf("Registration failed: %v", err)
 	}
@@ -156,7 +198,7 @@ func TestLogin_InvalidPassword(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 	repos := repository.NewRepositories(db, logger)
 	jwtService := jwt.NewService("test-secret-key-that-is-long-enough-for-jwt", 168)
-	service := NewAuthService(repos.User, repos.Account, repos.Transaction, jwtService, logger)
+	service := NewAuthService(repos.User, repos.Account, repos.Transaction, repos.Currency, jwtService, logger)
 
 	regReq := dto.RegisterRequest{
 		Email:     "invalid@test.com",
@@ -164,7 +206,7 @@ func TestLogin_InvalidPassword(t *testing.T) {
 		FirstName: "Invalid",
 		LastName:  "User",
 	}
-	_, err := service.Register(context.Background(), regReq, 100000, 50000)
+	_, err := service.Register(context.Background(), regReq, map[string]int64{"USD": 100000, "EUR": 50000})
 	if err != nil {
 		t.Fatalf("Registration failed: %v", err)
 	}
@@ -186,7 +228,7 @@ func TestLogin_NonExistentUser(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 	repos := repository.NewRepositories(db, logger)
 	jwtService := jwt.NewService("test-secret-key-that-is-long-enough-for-jwt", 168)
-	service := NewAuthService(repos.User, repos.Account, repos.Transaction, jwtService, logger)
+	service := NewAuthService(repos.User, repos.Account, repos.Transaction, repos.Currency, jwtService, logger)
 
 	loginReq := dto.LoginRequest{
 		Email:    "nonexistent@test.com",
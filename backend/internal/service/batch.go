@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/pkg/accountnumber"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	BatchModeAtomic  = "atomic"
+	BatchModePerLine = "per_line"
+
+	BatchStatusCompleted = "completed"
+	BatchStatusPartial   = "partial"
+	BatchStatusRejected  = "rejected"
+
+	BatchLineExecuted = "executed"
+	BatchLineFailed   = "failed"
+	BatchLineSkipped  = "skipped"
+)
+
+// BatchLineResult reports one line of a batch; Line is its 1-based position.
+type BatchLineResult struct {
+	Line          int     `json:"line"`
+	Recipient     string  `json:"recipient"`
+	Currency      string  `json:"currency"`
+	AmountCents   int64   `json:"amount_cents"`
+	Status        string  `json:"status"`
+	TransactionID *string `json:"transaction_id,omitempty"`
+	Error         string  `json:"error,omitempty"`
+}
+
+type BatchTransferResult struct {
+	Mode     string            `json:"mode"`
+	Status   string            `json:"status"`
+	Executed int               `json:"executed"`
+	Failed   int               `json:"failed"`
+	Lines    []BatchLineResult `json:"lines"`
+}
+
+// batchLine is a validated line with its accounts resolved.
+type batchLine struct {
+	result      *BatchLineResult
+	fromAccount *models.Account
+	toAccount   *models.Account
+	toUser      *models.User
+}
+
+// BatchTransfer pays a list of same-currency transfers. Every line is
+// validated before anything is posted. In atomic mode one invalid line
+// rejects the batch and all lines are posted in one DB transaction, so either
+// all of them land or none does. In per_line mode invalid lines are reported
+// and every other line is posted on its own.
+func (s *TransactionService) BatchTransfer(ctx context.Context, userID string, req dto.BatchTransferRequest) (*BatchTransferResult, error) {
+	mode := req.Mode
+	if mode == "" {
+		mode = BatchModeAtomic
+	}
+	result := &BatchTransferResult{Mode: mode, Lines: make([]BatchLineResult, len(req.Lines))}
+
+	valid := make([]*batchLine, 0, len(req.Lines))
+	sources := make(map[string]*models.Account)
+	for i, line := range req.Lines {
+		result.Lines[i] = BatchLineResult{
+			Line:        i + 1,
+			Recipient:   strings.TrimSpace(line.Recipient),
+			Currency:    strings.ToUpper(strings.TrimSpace(line.Currency)),
+			AmountCents: line.AmountCents,
+		}
+		resolved, err := s.resolveBatchLine(ctx, userID, req.FromAccountID, &result.Lines[i], line.ParseError, sources)
+		if err != nil {
+			result.Lines[i].Status = BatchLineFailed
+			result.Lines[i].Error = err.Error()
+			result.Failed++
+			continue
+		}
+		valid = append(valid, resolved)
+	}
+
+	if mode == BatchModeAtomic {
+		if result.Failed > 0 {
+			markSkipped(result)
+			result.Status = BatchStatusRejected
+			return result, nil
+		}
+		return s.postAtomicBatch(ctx, userID, result, valid)
+	}
+
+	for _, line := range valid {
+		transaction, err := s.postBatchLineInTx(ctx, userID, line)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			line.result.Status = BatchLineFailed
+			line.result.Error = err.Error()
+			result.Failed++
+			continue
+		}
+		line.result.Status = BatchLineExecuted
+		line.result.TransactionID = &transaction.ID
+		result.Executed++
+	}
+	result.Status = BatchStatusCompleted
+	if result.Failed > 0 {
+		result.Status = BatchStatusPartial
+	}
+
+	s.logger.Info("batch transfer posted", "userID", userID, "mode", mode, "executed", result.Executed, "failed", result.Failed)
+	return result, nil
+}
+
+// resolveBatchLine runs the checks a single transfer would make before
+// taking any locks, including the field checks request binding makes for a
+// transfer. Source accounts are cached per currency.
+func (s *TransactionService) resolveBatchLine(ctx context.Context, userID, fromAccountID string, line *BatchLineResult, parseError string, sources map[string]*models.Account) (*batchLine, error) {
+	if parseError != "" {
+		return nil, errorsx.BadRequest(parseError)
+	}
+	if line.Recipient == "" || len(line.Recipient) > 255 {
+		return nil, errorsx.BadRequest("recipient is required and must be at most 255 characters")
+	}
+	if len(line.Currency) != 3 {
+		return nil, errorsx.BadRequest("currency must be a 3-letter code")
+	}
+	if line.AmountCents <= 0 {
+		return nil, errorsx.ErrInvalidAmount
+	}
+
+	req := dto.TransferRequest{
+		FromAccountID: fromAccountID,
+		Currency:      line.Currency,
+		ToCurrency:    line.Currency,
+		AmountCents:   line.AmountCents,
+	}
+	// Anything shaped like an account number is looked up as one, so a
+	// mistyped number fails its check digits instead of the user lookup.
+	number := accountnumber.Normalize(line.Recipient)
+	if !strings.Contains(line.Recipient, "@") && accountnumber.LooksLike(number) {
+		req.ToAccountNumber = number
+	} else {
+		req.ToUserID = line.Recipient
+	}
+
+	fromAccount, ok := sources[line.Currency]
+	if !ok {
+		var err error
+		fromAccount, err = s.transferSourceAccount(ctx, userID, req)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := enabledCurrency(ctx, s.currencyRepo, fromAccount.Currency); err != nil {
+			return nil, err
+		}
+		if err := checkAccountStatus(fromAccount, true); err != nil {
+			return nil, err
+		}
+		sources[line.Currency] = fromAccount
+	}
+
+	toAccount, toUser, err := s.transferTargetAccount(ctx, userID, fromAccount, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAccountStatus(toAccount, false); err != nil {
+		return nil, err
+	}
+
+	return &batchLine{result: line, fromAccount: fromAccount, toAccount: toAccount, toUser: toUser}, nil
+}
+
+func (s *TransactionService) postAtomicBatch(ctx context.Context, userID string, result *BatchTransferResult, lines []*batchLine) (*BatchTransferResult, error) {
+	tx, err := s.transactionRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	accountIDs := make([]string, 0, 2*len(lines))
+	for _, line := range lines {
+		accountIDs = append(accountIDs, line.fromAccount.ID, line.toAccount.ID)
+	}
+	if err := s.accountRepo.LockAccountsForUpdate(ctx, tx, accountIDs); err != nil {
+		return nil, err
+	}
+
+	transactionIDs := make([]string, len(lines))
+	for i, line := range lines {
+		transaction, err := s.postBatchLine(ctx, tx, userID, line)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			// Nothing is committed, so the other lines did not happen either.
+			line.result.Status = BatchLineFailed
+			line.result.Error = err.Error()
+			result.Failed = 1
+			markSkipped(result)
+			result.Status = BatchStatusRejected
+			return result, nil
+		}
+		transactionIDs[i] = transaction.ID
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit batch transfer", "error", err)
+		return nil, fmt.Errorf("error committing batch transfer: %w", err)
+	}
+
+	for i, line := range lines {
+		line.result.Status = BatchLineExecuted
+		line.result.TransactionID = &transactionIDs[i]
+	}
+	result.Executed = len(lines)
+	result.Status = BatchStatusCompleted
+
+	s.logger.Info("batch transfer posted", "userID", userID, "mode", result.Mode, "executed", result.Executed)
+	return result, nil
+}
+
+func (s *TransactionService) postBatchLineInTx(ctx context.Context, userID string, line *batchLine) (*models.Transaction, error) {
+	tx, err := s.transactionRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := s.accountRepo.LockAccountsForUpdate(ctx, tx, []string{line.fromAccount.ID, line.toAccount.ID}); err != nil {
+		return nil, err
+	}
+	transaction, err := s.postBatchLine(ctx, tx, userID, line)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit batch line", "error", err)
+		return nil, fmt.Errorf("error committing batch line: %w", err)
+	}
+	return transaction, nil
+}
+
+// postBatchLine posts one line inside tx. The caller holds the account locks,
+// so the funds check also sees the lines posted before it.
+func (s *TransactionService) postBatchLine(ctx context.Context, tx *sqlx.Tx, userID string, line *batchLine) (*models.Transaction, error) {
+	amountCents := line.result.AmountCents
+	availableCents, err := s.accountRepo.GetAvailableCents(ctx, tx, line.fromAccount.ID)
+	if err != nil {
+		return nil, err
+	}
+	if availableCents < amountCents {
+		s.logger.Warn("insufficient funds for batch line", "userID", userID, "line", line.result.Line,
+			"available", availableCents, "required", amountCents)
+		return nil, errorsx.ErrInsufficientFunds
+	}
+
+	transaction := &models.Transaction{
+		Type:        models.TransactionTypeTransfer,
+		FromUserID:  userID,
+		ToUserID:    &line.toUser.ID,
+		Currency:    line.fromAccount.Currency,
+		AmountCents: amountCents,
+		Description: transferDescription(line.toUser, line.toAccount),
+	}
+	if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
+		return nil, err
+	}
+	entries := []*models.LedgerEntry{
+		{TransactionID: transaction.ID, AccountID: line.fromAccount.ID, Currency: line.fromAccount.Currency, AmountCents: -amountCents},
+		{TransactionID: transaction.ID, AccountID: line.toAccount.ID, Currency: line.toAccount.Currency, AmountCents: amountCents},
+	}
+	if err := s.postLedgerEntries(ctx, tx, entries); err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// markSkipped flags the lines of a rejected batch that were valid but never
+// posted.
+func markSkipped(result *BatchTransferResult) {
+	for i := range result.Lines {
+		if result.Lines[i].Status == "" {
+			result.Lines[i].Status = BatchLineSkipped
+		}
+	}
+}
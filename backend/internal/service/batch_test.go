@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/repository"
+	"mini-banking-platform/pkg/accountnumber"
+	"os"
+	"testing"
+)
+
+func TestBatchTransfer_AtomicAndPerLine(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	transactions := newTestTransactionService(repos, logger)
+	ctx := context.Background()
+
+	employer := createTestUser(t, db, "payroll@test.com")
+	alice := createTestUser(t, db, "alice-pay@test.com")
+	bob := createTestUser(t, db, "bob-pay@test.com")
+	payroll := createTestAccount(t, db, employer.ID, "USD", 10000)
+	aliceUSD := createTestAccount(t, db, alice.ID, "USD", 0)
+	bobUSD := createTestAccount(t, db, bob.ID, "USD", 0)
+	db.Get(&bobUSD.AccountNumber, "SELECT account_number FROM accounts WHERE id = $1", bobUSD.ID)
+
+	balances := func() (int64, int64, int64) {
+		var p, a, b int64
+		db.Get(&p, "SELECT balance_cents FROM accounts WHERE id = $1", payroll.ID)
+		db.Get(&a, "SELECT balance_cents FROM accounts WHERE id = $1", aliceUSD.ID)
+		db.Get(&b, "SELECT balance_cents FROM accounts WHERE id = $1", bobUSD.ID)
+		return p, a, b
+	}
+
+	rejected, err := transactions.BatchTransfer(ctx, employer.ID, dto.BatchTransferRequest{Lines: []dto.BatchTransferLine{
+		{Recipient: alice.Email, Currency: "USD", AmountCents: 1000},
+		{Recipient: "nobody@test.com", Currency: "USD", AmountCents: 1000},
+	}})
+	if err != nil {
+		t.Fatalf("BatchTransfer failed: %v", err)
+	}
+	if rejected.Status != BatchStatusRejected || rejected.Lines[0].Status != BatchLineSkipped || rejected.Lines[1].Status != BatchLineFailed {
+		t.Errorf("Expected the invalid line to reject the batch, got %+v", rejected)
+	}
+
+	// The second line passes validation but not the funds check once the
+	// first is posted, so the whole batch rolls back.
+	overdrawn, err := transactions.BatchTransfer(ctx, employer.ID, dto.BatchTransferRequest{Lines: []dto.BatchTransferLine{
+		{Recipient: alice.Email, Currency: "USD", AmountCents: 6000},
+		{Recipient: bobUSD.AccountNumber, Currency: "USD", AmountCents: 6000},
+	}})
+	if err != nil {
+		t.Fatalf("BatchTransfer failed: %v", err)
+	}
+	if overdrawn.Status != BatchStatusRejected || overdrawn.Lines[0].Status != BatchLineSkipped || overdrawn.Lines[1].Status != BatchLineFailed {
+		t.Errorf("Expected the atomic batch to be rolled back, got %+v", overdrawn)
+	}
+	if p, a, b := balances(); p != 10000 || a != 0 || b != 0 {
+		t.Errorf("Expected no money to move, got %d, %d, %d", p, a, b)
+	}
+
+	atomic, err := transactions.BatchTransfer(ctx, employer.ID, dto.BatchTransferRequest{Lines: []dto.BatchTransferLine{
+		{Recipient: alice.Email, Currency: "USD", AmountCents: 3000},
+		{Recipient: bobUSD.AccountNumber, Currency: "USD", AmountCents: 2000},
+	}})
+	if err != nil {
+		t.Fatalf("BatchTransfer failed: %v", err)
+	}
+	if atomic.Status != BatchStatusCompleted || atomic.Executed != 2 || atomic.Lines[1].TransactionID == nil {
+		t.Errorf("Expected both lines executed, got %+v", atomic)
+	}
+
+	perLine, err := transactions.BatchTransfer(ctx, employer.ID, dto.BatchTransferRequest{Mode: BatchModePerLine, Lines: []dto.BatchTransferLine{
+		{Recipient: alice.Email, Currency: "USD", AmountCents: 4000},
+		{Recipient: employer.Email, Currency: "USD", AmountCents: 100},
+		{Recipient: bob.ID, Currency: "USD", AmountCents: 4000},
+	}})
+	if err != nil {
+		t.Fatalf("BatchTransfer failed: %v", err)
+	}
+	if perLine.Status != BatchStatusPartial || perLine.Executed != 1 || perLine.Failed != 2 {
+		t.Errorf("Expected 1 executed and 2 failed lines, got %+v", perLine)
+	}
+	if perLine.Lines[2].Status != BatchLineFailed || perLine.Lines[2].Error != "insufficient funds" {
+		t.Errorf("Expected the last line to fail for funds, got %+v", perLine.Lines[2])
+	}
+	// Bad lines are reported one by one and do not stop the good ones.
+	typo := []byte(bobUSD.AccountNumber)
+	typo[len(typo)-1] = '0' + (typo[len(typo)-1]-'0'+1)%10
+	mixed, err := transactions.BatchTransfer(ctx, employer.ID, dto.BatchTransferRequest{Mode: BatchModePerLine, Lines: []dto.BatchTransferLine{
+		{Recipient: alice.Email, Currency: "USD", AmountCents: 0},
+		{Recipient: alice.Email, Currency: "US", AmountCents: 100},
+		{Recipient: alice.Email, Currency: "USD", ParseError: "line 4: amount_cents must be a whole number of cents"},
+		{Recipient: string(typo), Currency: "USD", AmountCents: 100},
+		{Recipient: alice.Email, Currency: "usd", AmountCents: 100},
+	}})
+	if err != nil {
+		t.Fatalf("BatchTransfer failed: %v", err)
+	}
+	if mixed.Executed != 1 || mixed.Failed != 4 || mixed.Lines[4].Status != BatchLineExecuted {
+		t.Errorf("Expected only the last line executed, got %+v", mixed)
+	}
+	if mixed.Lines[3].Error != accountnumber.ErrInvalidChecksum.Error() {
+		t.Errorf("Expected the mistyped account number to fail its check digits, got %q", mixed.Lines[3].Error)
+	}
+
+	if p, a, b := balances(); p != 900 || a != 7100 || b != 2000 {
+		t.Errorf("Expected balances 900, 7100 and 2000, got %d, %d, %d", p, a, b)
+	}
+}
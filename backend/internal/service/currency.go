@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/internal/repository"
+)
+
+type CurrencyService struct {
+	currencyRepo *repository.CurrencyRepository
+	logger       *slog.Logger
+}
+
+func NewCurrencyService(currencyRepo *repository.CurrencyRepository, logger *slog.Logger) *CurrencyService {
+	return &CurrencyService{
+		currencyRepo: currencyRepo,
+		logger:       logger,
+	}
+}
+
+func (s *CurrencyService) GetEnabledCurrencies(ctx context.Context) ([]models.Currency, error) {
+	currencies, err := s.currencyRepo.FindEnabled(ctx)
+	if err != nil {
+		s.logger.Error("failed to get currencies", "error", err)
+		return nil, fmt.Errorf("error getting currencies: %w", err)
+	}
+	return currencies, nil
+}
+
+// enabledCurrency resolves a currency code against the registry and rejects
+// unknown or disabled currencies.
+func enabledCurrency(ctx context.Context, currencyRepo *repository.CurrencyRepository, code string) (*models.Currency, error) {
+	currency, err := currencyRepo.FindByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if !currency.Enabled {
+		return nil, errorsx.ErrCurrencyDisabled
+	}
+	return currency, nil
+}
+
+// scaleRate adjusts a rate quoted per major unit so that it converts minor
+// units of the source currency into minor units of the target currency.
+func scaleRate(rateNum, rateDenom int64, from, to *models.Currency) (int64, int64) {
+	for i := from.MinorUnits; i < to.MinorUnits; i++ {
+		rateNum *= 10
+	}
+	for i := to.MinorUnits; i < from.MinorUnits; i++ {
+		rateDenom *= 10
+	}
+	return rateNum, rateDenom
+}
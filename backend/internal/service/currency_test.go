@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/internal/repository"
+	"os"
+	"testing"
+)
+
+func TestScaleRate_MinorUnits(t *testing.T) {
+	usd := &models.Currency{Code: "USD", MinorUnits: 2}
+	jpy := &models.Currency{Code: "JPY", MinorUnits: 0}
+
+	num, denom := scaleRate(150, 1, usd, jpy)
+	if got := int64(10000) * num / denom; got != 15000 {
+		t.Errorf("Expected 100.00 USD to convert to 15000 JPY, got %d", got)
+	}
+
+	num, denom = scaleRate(1, 150, jpy, usd)
+	if got := int64(15000) * num / denom; got != 10000 {
+		t.Errorf("Expected 15000 JPY to convert to 10000 USD cents, got %d", got)
+	}
+}
+
+func TestEnabledCurrency_RejectsDisabled(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+
+	if _, err := enabledCurrency(context.Background(), repos.Currency, "USD"); err != nil {
+		t.Errorf("Expected USD to be enabled, got %v", err)
+	}
+
+	_, err := enabledCurrency(context.Background(), repos.Currency, "GBP")
+	if err != errorsx.ErrCurrencyDisabled {
+		t.Errorf("Expected ErrCurrencyDisabled for GBP, got %v", err)
+	}
+
+	_, err = enabledCurrency(context.Background(), repos.Currency, "XXX")
+	if err != errorsx.ErrInvalidCurrency {
+		t.Errorf("Expected ErrInvalidCurrency for unknown code, got %v", err)
+	}
+}
@@ -0,0 +1,422 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/pkg/decimal"
+	"time"
+)
+
+// exchangePricing holds the amounts of one conversion. GrossAmountCents is
+// the target amount at the mid rate; the customer receives ToAmountCents,
+// which is gross minus the spread fee. Remainder is the exact fraction of a
+// target cent lost (or added, if negative) when rounding gross.
+type exchangePricing struct {
+	FromCurrency     string
+	ToCurrency       string
+	FromAmountCents  int64
+	GrossAmountCents int64
+	FeeCents         int64
+	ToAmountCents    int64
+	RoundingMode     decimal.RoundingMode
+	Remainder        *big.Rat
+	Rate             *models.FXRate
+}
+
+// conversionRate is the effective rate between two currencies, scaled to
+// minor units, together with the spread that applies to the direction.
+type conversionRate struct {
+	From      *models.Currency
+	To        *models.Currency
+	Rate      *models.FXRate
+	Num       int64
+	Denom     int64
+	SpreadBps int64
+}
+
+func (s *TransactionService) conversionRate(ctx context.Context, fromCode, toCode string, at time.Time) (*conversionRate, error) {
+	from, err := enabledCurrency(ctx, s.currencyRepo, fromCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if toCode == "" {
+		toCode = counterCurrency(from.Code)
+		if toCode == "" {
+			return nil, errorsx.BadRequest("to_currency is required")
+		}
+	}
+	to, err := enabledCurrency(ctx, s.currencyRepo, toCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if from.Code == to.Code {
+		return nil, errorsx.ErrCurrenciesMustDiffer
+	}
+
+	rate, err := s.rates.GetRate(ctx, from.Code, to.Code, at)
+	if err != nil {
+		return nil, err
+	}
+	rateNum, rateDenom := scaleRate(rate.RateNum, rate.RateDenom, from, to)
+
+	spreadBps, err := s.spreadBps(ctx, from.Code, to.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &conversionRate{
+		From:      from,
+		To:        to,
+		Rate:      rate,
+		Num:       rateNum,
+		Denom:     rateDenom,
+		SpreadBps: spreadBps,
+	}, nil
+}
+
+func (s *TransactionService) priceExchange(ctx context.Context, fromCode, toCode string, fromAmountCents int64, at time.Time) (*exchangePricing, error) {
+	if fromAmountCents <= 0 {
+		return nil, errorsx.ErrInvalidAmount
+	}
+
+	if fromAmountCents < models.MinExchangeAmountCents {
+		return nil, errorsx.BadRequest(fmt.Sprintf("minimum exchange amount is %d cents", models.MinExchangeAmountCents))
+	}
+
+	conv, err := s.conversionRate(ctx, fromCode, toCode, at)
+	if err != nil {
+		return nil, err
+	}
+
+	maxSafeAmount := int64(math.MaxInt64 / conv.Num)
+	if fromAmountCents > maxSafeAmount {
+		s.logger.Error("exchange amount too large, would cause overflow",
+			"fromAmountCents", fromAmountCents, "maxSafe", maxSafeAmount)
+		return nil, errorsx.BadRequest("amount too large")
+	}
+
+	grossAmountCents, remainder := decimal.DivRound(fromAmountCents*conv.Num, conv.Denom, s.roundingMode)
+	feeCents := spreadFeeCents(grossAmountCents, conv.SpreadBps)
+
+	return &exchangePricing{
+		FromCurrency:     conv.From.Code,
+		ToCurrency:       conv.To.Code,
+		FromAmountCents:  fromAmountCents,
+		GrossAmountCents: grossAmountCents,
+		FeeCents:         feeCents,
+		ToAmountCents:    grossAmountCents - feeCents,
+		RoundingMode:     s.roundingMode,
+		Remainder:        big.NewRat(remainder, conv.Denom),
+		Rate:             conv.Rate,
+	}, nil
+}
+
+// priceExchangeForTarget prices an exchange that delivers exactly
+// toAmountCents after fees. The source amount is rounded up, so the fraction
+// of a cent the customer overpays lands in the rounding suspense.
+func (s *TransactionService) priceExchangeForTarget(ctx context.Context, fromCode, toCode string, toAmountCents int64, at time.Time) (*exchangePricing, error) {
+	if toAmountCents <= 0 {
+		return nil, errorsx.ErrInvalidAmount
+	}
+
+	conv, err := s.conversionRate(ctx, fromCode, toCode, at)
+	if err != nil {
+		return nil, err
+	}
+
+	grossAmountCents, ok := grossForNet(toAmountCents, conv.SpreadBps)
+	if !ok {
+		return nil, errorsx.BadRequest("amount too large")
+	}
+
+	source := new(big.Int).Mul(big.NewInt(grossAmountCents), big.NewInt(conv.Denom))
+	source.Add(source, big.NewInt(conv.Num-1))
+	source.Quo(source, big.NewInt(conv.Num))
+	if !source.IsInt64() || source.Int64() > math.MaxInt64/conv.Num {
+		s.logger.Error("exchange target too large, would cause overflow", "toAmountCents", toAmountCents)
+		return nil, errorsx.BadRequest("amount too large")
+	}
+	fromAmountCents := source.Int64()
+
+	if fromAmountCents < models.MinExchangeAmountCents {
+		return nil, errorsx.BadRequest(fmt.Sprintf("minimum exchange amount is %d cents", models.MinExchangeAmountCents))
+	}
+
+	remainder := big.NewRat(fromAmountCents*conv.Num, conv.Denom)
+	remainder.Sub(remainder, new(big.Rat).SetInt64(grossAmountCents))
+	feeCents := spreadFeeCents(grossAmountCents, conv.SpreadBps)
+
+	return &exchangePricing{
+		FromCurrency:     conv.From.Code,
+		ToCurrency:       conv.To.Code,
+		FromAmountCents:  fromAmountCents,
+		GrossAmountCents: grossAmountCents,
+		FeeCents:         feeCents,
+		ToAmountCents:    grossAmountCents - feeCents,
+		RoundingMode:     decimal.RoundCeiling,
+		Remainder:        remainder,
+		Rate:             conv.Rate,
+	}, nil
+}
+
+// grossForNet returns the smallest gross amount that still leaves
+// netAmountCents once the spread fee is deducted.
+func grossForNet(netAmountCents, spreadBps int64) (int64, bool) {
+	if spreadBps <= 0 {
+		return netAmountCents, true
+	}
+	keepBps := models.BasisPointsDenominator - spreadBps
+	if keepBps <= 0 || netAmountCents > math.MaxInt64/models.BasisPointsDenominator {
+		return 0, false
+	}
+
+	gross := (netAmountCents*models.BasisPointsDenominator + keepBps - 1) / keepBps
+	for gross-spreadFeeCents(gross, spreadBps) < netAmountCents {
+		gross++
+	}
+	return gross, true
+}
+
+// spreadBps returns the bid spread when the customer sells the base currency
+// of a configured pair and the ask spread when they buy it.
+func (s *TransactionService) spreadBps(ctx context.Context, fromCurrency, toCurrency string) (int64, error) {
+	spread, err := s.spreadRepo.FindByPair(ctx, fromCurrency, toCurrency)
+	if err != nil {
+		return 0, err
+	}
+	if spread != nil {
+		return spread.BidBps, nil
+	}
+
+	spread, err = s.spreadRepo.FindByPair(ctx, toCurrency, fromCurrency)
+	if err != nil {
+		return 0, err
+	}
+	if spread != nil {
+		return spread.AskBps, nil
+	}
+	return 0, nil
+}
+
+// spreadFeeCents rounds the fee up so fractions of a cent go to the platform.
+func spreadFeeCents(grossAmountCents, spreadBps int64) int64 {
+	if spreadBps <= 0 || grossAmountCents <= 0 {
+		return 0
+	}
+	whole := (grossAmountCents / models.BasisPointsDenominator) * spreadBps
+	rest := (grossAmountCents % models.BasisPointsDenominator) * spreadBps
+	return whole + (rest+models.BasisPointsDenominator-1)/models.BasisPointsDenominator
+}
+
+type conversionAccounts struct {
+	FXFrom  *models.Account
+	FXTo    *models.Account
+	Revenue *models.Account
+}
+
+func (a *conversionAccounts) IDs() []string {
+	ids := []string{a.FXFrom.ID, a.FXTo.ID}
+	if a.Revenue != nil {
+		ids = append(ids, a.Revenue.ID)
+	}
+	return ids
+}
+
+// findConversionAccounts resolves the system accounts a conversion posts to.
+// The revenue account is only needed when a fee is charged.
+func (s *TransactionService) findConversionAccounts(ctx context.Context, pricing *exchangePricing) (*conversionAccounts, error) {
+	fxFrom, err := s.accountRepo.FindFXAccountByCurrency(ctx, pricing.FromCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	fxTo, err := s.accountRepo.FindFXAccountByCurrency(ctx, pricing.ToCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := &conversionAccounts{FXFrom: fxFrom, FXTo: fxTo}
+	if pricing.FeeCents > 0 {
+		accounts.Revenue, err = s.accountRepo.FindRevenueAccountByCurrency(ctx, pricing.ToCurrency)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return accounts, nil
+}
+
+// conversionEntries builds the balanced per-currency posting: the source leg
+// moves into the FX account, the FX account pays out the gross target amount,
+// and the fee leg moves from the customer to platform revenue.
+func conversionEntries(transactionID, fromAccountID, toAccountID string, accounts *conversionAccounts, pricing *exchangePricing) []*models.LedgerEntry {
+	entries := []*models.LedgerEntry{
+		{
+			TransactionID: transactionID,
+			AccountID:     fromAccountID,
+			Currency:      pricing.FromCurrency,
+			AmountCents:   -pricing.FromAmountCents,
+		},
+		{
+			TransactionID: transactionID,
+			AccountID:     accounts.FXFrom.ID,
+			Currency:      pricing.FromCurrency,
+			AmountCents:   pricing.FromAmountCents,
+		},
+		{
+			TransactionID: transactionID,
+			AccountID:     accounts.FXTo.ID,
+			Currency:      pricing.ToCurrency,
+			AmountCents:   -pricing.GrossAmountCents,
+		},
+		{
+			TransactionID: transactionID,
+			AccountID:     toAccountID,
+			Currency:      pricing.ToCurrency,
+			AmountCents:   pricing.GrossAmountCents,
+		},
+	}
+
+	if pricing.FeeCents > 0 {
+		entries = append(entries,
+			&models.LedgerEntry{
+				TransactionID: transactionID,
+				AccountID:     toAccountID,
+				Currency:      pricing.ToCurrency,
+				AmountCents:   -pricing.FeeCents,
+			},
+			&models.LedgerEntry{
+				TransactionID: transactionID,
+				AccountID:     accounts.Revenue.ID,
+				Currency:      pricing.ToCurrency,
+				AmountCents:   pricing.FeeCents,
+			},
+		)
+	}
+	return entries
+}
+
+// pricingFromQuote rebuilds the pricing of a locked quote. The rounding
+// remainder is recomputed from the quoted rate so it stays exact.
+func (s *TransactionService) pricingFromQuote(ctx context.Context, quote *models.FXQuote) (*exchangePricing, error) {
+	from, err := s.currencyRepo.FindByCode(ctx, quote.FromCurrency)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.currencyRepo.FindByCode(ctx, quote.ToCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	grossAmountCents := quote.ToAmountCents + quote.FeeCents
+	rateNum, rateDenom := scaleRate(quote.RateNum, quote.RateDenom, from, to)
+	exact := new(big.Int).Mul(big.NewInt(quote.FromAmountCents), big.NewInt(rateNum))
+	remainder := new(big.Rat).SetFrac(exact, big.NewInt(rateDenom))
+	remainder.Sub(remainder, new(big.Rat).SetInt64(grossAmountCents))
+
+	return &exchangePricing{
+		FromCurrency:     quote.FromCurrency,
+		ToCurrency:       quote.ToCurrency,
+		FromAmountCents:  quote.FromAmountCents,
+		GrossAmountCents: grossAmountCents,
+		FeeCents:         quote.FeeCents,
+		ToAmountCents:    quote.ToAmountCents,
+		RoundingMode:     decimal.RoundingMode(quote.RoundingMode),
+		Remainder:        remainder,
+		Rate: &models.FXRate{
+			ID:            quote.FXRateID,
+			BaseCurrency:  quote.FromCurrency,
+			QuoteCurrency: quote.ToCurrency,
+			RateNum:       quote.RateNum,
+			RateDenom:     quote.RateDenom,
+		},
+	}, nil
+}
+
+// validateQuote checks a locked quote against the caller and the optional
+// fields of the exchange request.
+func validateQuote(quote *models.FXQuote, userID string, req dto.ExchangeRequest, now time.Time) error {
+	if quote.UserID != userID {
+		return errorsx.ErrQuoteNotFound
+	}
+	if quote.UsedAt != nil {
+		return errorsx.ErrQuoteUsed
+	}
+	if now.After(quote.ExpiresAt) {
+		return errorsx.ErrQuoteExpired
+	}
+	if (req.FromCurrency != "" && req.FromCurrency != quote.FromCurrency) ||
+		(req.ToCurrency != "" && req.ToCurrency != quote.ToCurrency) ||
+		(req.AmountCents != 0 && req.AmountCents != quote.FromAmountCents) ||
+		(req.ToAmountCents != 0 && req.ToAmountCents != quote.ToAmountCents) {
+		return errorsx.BadRequest("request does not match quote")
+	}
+	return nil
+}
+
+func (s *TransactionService) Quote(ctx context.Context, userID string, req dto.ExchangeQuoteRequest, ttl time.Duration) (*models.FXQuote, error) {
+	now := time.Now().UTC()
+	pricing, err := s.priceExchangeRequest(ctx, req.FromCurrency, req.ToCurrency, req.AmountCents, req.ToAmountCents, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.accountRepo.FindByUserAndCurrency(ctx, userID, pricing.FromCurrency); err != nil {
+		return nil, err
+	}
+	if _, err := s.accountRepo.FindByUserAndCurrency(ctx, userID, pricing.ToCurrency); err != nil {
+		return nil, err
+	}
+
+	quote := &models.FXQuote{
+		UserID:          userID,
+		FromCurrency:    pricing.FromCurrency,
+		ToCurrency:      pricing.ToCurrency,
+		FromAmountCents: pricing.FromAmountCents,
+		ToAmountCents:   pricing.ToAmountCents,
+		FeeCents:        pricing.FeeCents,
+		RoundingMode:    string(pricing.RoundingMode),
+		FXRateID:        pricing.Rate.ID,
+		RateNum:         pricing.Rate.RateNum,
+		RateDenom:       pricing.Rate.RateDenom,
+		ExpiresAt:       now.Add(ttl),
+	}
+	if err := s.quoteRepo.Create(ctx, quote); err != nil {
+		s.logger.Error("failed to create quote", "error", err, "userID", userID)
+		return nil, fmt.Errorf("error creating quote: %w", err)
+	}
+
+	s.logger.Info("exchange quoted", "quoteID", quote.ID, "userID", userID, "from", quote.FromCurrency,
+		"to", quote.ToCurrency, "fromAmountCents", quote.FromAmountCents, "toAmountCents", quote.ToAmountCents, "feeCents", quote.FeeCents)
+	return quote, nil
+}
+
+// priceExchangeRequest prices by sell amount or, when toAmountCents is set,
+// by the exact amount to buy.
+func (s *TransactionService) priceExchangeRequest(ctx context.Context, fromCode, toCode string, amountCents, toAmountCents int64, at time.Time) (*exchangePricing, error) {
+	if amountCents != 0 && toAmountCents != 0 {
+		return nil, errorsx.BadRequest("specify either amount_cents or to_amount_cents, not both")
+	}
+	if toAmountCents != 0 {
+		return s.priceExchangeForTarget(ctx, fromCode, toCode, toAmountCents, at)
+	}
+	return s.priceExchange(ctx, fromCode, toCode, amountCents, at)
+}
+
+// counterCurrency keeps exchange requests without to_currency working for the
+// original USD/EUR pair.
+func counterCurrency(code string) string {
+	switch code {
+	case models.CurrencyUSD:
+		return models.CurrencyEUR
+	case models.CurrencyEUR:
+		return models.CurrencyUSD
+	}
+	return ""
+}
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/repository"
+	"mini-banking-platform/pkg/decimal"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestExchange_LockedQuoteSurvivesRateChange(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	rates := NewFXRateService(repos.FXRate, repos.FXSpread, repos.Rounding, repos.Currency, repos.Transaction, logger)
+	service := newTestTransactionService(repos, logger)
+
+	createFXSystemAccounts(t, db)
+
+	user := createTestUser(t, db, "quote@test.com")
+	createTestAccount(t, db, user.ID, "USD", 20000)
+	createTestAccount(t, db, user.ID, "EUR", 0)
+
+	quote, err := service.Quote(context.Background(), user.ID, dto.ExchangeQuoteRequest{
+		FromCurrency: "USD",
+		AmountCents:  10000,
+	}, time.Minute)
+	if err != nil {
+		t.Fatalf("Quote failed: %v", err)
+	}
+	if quote.ToAmountCents != 9200 {
+		t.Errorf("Expected quoted amount 9200, got %d", quote.ToAmountCents)
+	}
+
+	_, err = rates.PublishRate(context.Background(), dto.PublishFXRateRequest{
+		BaseCurrency:  "USD",
+		QuoteCurrency: "EUR",
+		Rate:          "0.5",
+		Source:        "test",
+	})
+	if err != nil {
+		t.Fatalf("PublishRate failed: %v", err)
+	}
+
+	_, err = service.Exchange(context.Background(), user.ID, dto.ExchangeRequest{QuoteID: quote.ID})
+	if err != nil {
+		t.Fatalf("Exchange with quote failed: %v", err)
+	}
+
+	var balanceEUR int64
+	db.Get(&balanceEUR, "SELECT balance_cents FROM accounts WHERE user_id = $1 AND currency = 'EUR'", user.ID)
+	if balanceEUR != 9200 {
+		t.Errorf("Expected quoted EUR balance 9200, got %d", balanceEUR)
+	}
+
+	_, err = service.Exchange(context.Background(), user.ID, dto.ExchangeRequest{QuoteID: quote.ID})
+	if err != errorsx.ErrQuoteUsed {
+		t.Errorf("Expected ErrQuoteUsed on reuse, got %v", err)
+	}
+}
+
+func TestExchange_ExpiredQuote(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	service := newTestTransactionService(repos, logger)
+
+	createFXSystemAccounts(t, db)
+
+	user := createTestUser(t, db, "expired-quote@test.com")
+	other := createTestUser(t, db, "other-quote@test.com")
+	createTestAccount(t, db, user.ID, "USD", 10000)
+	createTestAccount(t, db, user.ID, "EUR", 0)
+
+	quote, err := service.Quote(context.Background(), user.ID, dto.ExchangeQuoteRequest{
+		FromCurrency: "USD",
+		AmountCents:  1000,
+	}, -time.Second)
+	if err != nil {
+		t.Fatalf("Quote failed: %v", err)
+	}
+
+	_, err = service.Exchange(context.Background(), other.ID, dto.ExchangeRequest{QuoteID: quote.ID})
+	if err != errorsx.ErrQuoteNotFound {
+		t.Errorf("Expected ErrQuoteNotFound for another user, got %v", err)
+	}
+
+	_, err = service.Exchange(context.Background(), user.ID, dto.ExchangeRequest{QuoteID: quote.ID})
+	if err != errorsx.ErrQuoteExpired {
+		t.Errorf("Expected ErrQuoteExpired, got %v", err)
+	}
+}
+
+func TestSpreadFeeCents_RoundsUp(t *testing.T) {
+	cases := []struct {
+		gross, bps, want int64
+	}{
+		{9200, 0, 0},
+		{9200, 50, 46},
+		{9201, 50, 47},
+		{1, 1, 1},
+		{math.MaxInt64, 10000, math.MaxInt64},
+	}
+	for _, c := range cases {
+		if got := spreadFeeCents(c.gross, c.bps); got != c.want {
+			t.Errorf("spreadFeeCents(%d, %d) = %d, want %d", c.gross, c.bps, got, c.want)
+		}
+	}
+}
+
+func TestExchange_SpreadPostsFeeToRevenue(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	rates := NewFXRateService(repos.FXRate, repos.FXSpread, repos.Rounding, repos.Currency, repos.Transaction, logger)
+	service := newTestTransactionService(repos, logger)
+
+	createFXSystemAccounts(t, db)
+	createRevenueSystemAccounts(t, db)
+
+	_, err := rates.SetSpread(context.Background(), dto.SetFXSpreadRequest{
+		BaseCurrency:  "USD",
+		QuoteCurrency: "EUR",
+		BidBps:        50,
+		AskBps:        80,
+	})
+	if err != nil {
+		t.Fatalf("SetSpread failed: %v", err)
+	}
+
+	user := createTestUser(t, db, "spread@test.com")
+	createTestAccount(t, db, user.ID, "USD", 20000)
+	createTestAccount(t, db, user.ID, "EUR", 0)
+
+	transaction, err := service.Exchange(context.Background(), user.ID, dto.ExchangeRequest{
+		FromCurrency: "USD",
+		AmountCents:  10000,
+	})
+	if err != nil {
+		t.Fatalf("Exchange failed: %v", err)
+	}
+	if transaction.FeeCents != 46 {
+		t.Errorf("Expected fee 46, got %d", transaction.FeeCents)
+	}
+
+	var entryCount int
+	db.Get(&entryCount, "SELECT COUNT(*) FROM ledger_entries WHERE transaction_id = $1", transaction.ID)
+	if entryCount != 6 {
+		t.Errorf("Expected 6 ledger entries, got %d", entryCount)
+	}
+
+	var balanceEUR int64
+	db.Get(&balanceEUR, "SELECT balance_cents FROM accounts WHERE user_id = $1 AND currency = 'EUR'", user.ID)
+	if balanceEUR != 9154 {
+		t.Errorf("Expected net EUR balance 9154, got %d", balanceEUR)
+	}
+
+	revenue, err := rates.GetRevenue(context.Background(), time.Time{}, time.Now().UTC().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetRevenue failed: %v", err)
+	}
+	if len(revenue) != 1 || revenue[0].Currency != "EUR" || revenue[0].AmountCents != 46 {
+		t.Errorf("Expected 46 EUR revenue, got %+v", revenue)
+	}
+
+	var sums []int64
+	db.Select(&sums, "SELECT SUM(amount_cents) FROM ledger_entries WHERE transaction_id = $1 GROUP BY currency", transaction.ID)
+	for _, sum := range sums {
+		if sum != 0 {
+			t.Errorf("Expected ledger entries to balance per currency, got %d", sum)
+		}
+	}
+}
+
+func TestExchange_RoundingRemainderTracked(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	rates := NewFXRateService(repos.FXRate, repos.FXSpread, repos.Rounding, repos.Currency, repos.Transaction, logger)
+	floorService := newTestTransactionServiceWithRounding(repos, logger, decimal.RoundFloor)
+	halfUpService := newTestTransactionServiceWithRounding(repos, logger, decimal.RoundHalfUp)
+
+	createFXSystemAccounts(t, db)
+
+	user := createTestUser(t, db, "rounding@test.com")
+	createTestAccount(t, db, user.ID, "USD", 50000)
+	createTestAccount(t, db, user.ID, "EUR", 0)
+
+	// 10001 * 23/25 = 9200.92 EUR cents
+	req := dto.ExchangeRequest{FromCurrency: "USD", AmountCents: 10001}
+	if _, err := floorService.Exchange(context.Background(), user.ID, req); err != nil {
+		t.Fatalf("floor Exchange failed: %v", err)
+	}
+	if _, err := halfUpService.Exchange(context.Background(), user.ID, req); err != nil {
+		t.Fatalf("half_up Exchange failed: %v", err)
+	}
+
+	var balanceEUR int64
+	db.Get(&balanceEUR, "SELECT balance_cents FROM accounts WHERE user_id = $1 AND currency = 'EUR'", user.ID)
+	if balanceEUR != 9200+9201 {
+		t.Errorf("Expected EUR balance %d, got %d", 9200+9201, balanceEUR)
+	}
+
+	report, err := rates.GetRoundingReport(context.Background())
+	if err != nil {
+		t.Fatalf("GetRoundingReport failed: %v", err)
+	}
+	if len(report) != 1 {
+		t.Fatalf("Expected one report line, got %+v", report)
+	}
+	line := report[0]
+	if line.Currency != "EUR" || line.ExchangeCount != 2 || line.EntryCount != 2 {
+		t.Errorf("Unexpected report line %+v", line)
+	}
+	// floor keeps 23/25 of a cent, half_up pays out 2/25 too much
+	if line.SuspenseCents != "21/25" || !line.IsBalanced {
+		t.Errorf("Expected balanced suspense of 21/25 cents, got %+v", line)
+	}
+}
+
+func TestGrossForNet(t *testing.T) {
+	cases := []struct {
+		net, bps, want int64
+	}{
+		{9200, 0, 9200},
+		{9154, 50, 9200},
+		{9155, 50, 9202},
+		{1, 9999, 10000},
+	}
+	for _, c := range cases {
+		got, ok := grossForNet(c.net, c.bps)
+		if !ok || got != c.want {
+			t.Errorf("grossForNet(%d, %d) = %d, want %d", c.net, c.bps, got, c.want)
+		}
+		if got-spreadFeeCents(got, c.bps) != c.net {
+			t.Errorf("grossForNet(%d, %d) does not leave the exact net amount", c.net, c.bps)
+		}
+	}
+	if _, ok := grossForNet(100, 10000); ok {
+		t.Error("expected a 100% spread to be rejected")
+	}
+}
+
+func TestExchange_ByTargetAmount(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	service := newTestTransactionService(repos, logger)
+
+	createFXSystemAccounts(t, db)
+
+	user := createTestUser(t, db, "target@test.com")
+	createTestAccount(t, db, user.ID, "USD", 10001)
+	createTestAccount(t, db, user.ID, "EUR", 0)
+
+	// 9201 EUR cents need 10001.09 USD cents, rounded up to 10002
+	_, err := service.Exchange(context.Background(), user.ID, dto.ExchangeRequest{
+		FromCurrency:  "USD",
+		ToAmountCents: 9201,
+	})
+	if err != errorsx.ErrInsufficientFunds {
+		t.Fatalf("Expected ErrInsufficientFunds, got %v", err)
+	}
+
+	db.Exec("UPDATE accounts SET balance_cents = 10002 WHERE user_id = $1 AND currency = 'USD'", user.ID)
+	transaction, err := service.Exchange(context.Background(), user.ID, dto.ExchangeRequest{
+		FromCurrency:  "USD",
+		ToAmountCents: 9201,
+	})
+	if err != nil {
+		t.Fatalf("Exchange failed: %v", err)
+	}
+	if transaction.AmountCents != 10002 {
+		t.Errorf("Expected source amount 10002, got %d", transaction.AmountCents)
+	}
+
+	var balanceUSD, balanceEUR int64
+	db.Get(&balanceUSD, "SELECT balance_cents FROM accounts WHERE user_id = $1 AND currency = 'USD'", user.ID)
+	db.Get(&balanceEUR, "SELECT balance_cents FROM accounts WHERE user_id = $1 AND currency = 'EUR'", user.ID)
+	if balanceUSD != 0 || balanceEUR != 9201 {
+		t.Errorf("Expected USD 0 and EUR 9201, got USD %d EUR %d", balanceUSD, balanceEUR)
+	}
+
+	var remainderNum, remainderDenom int64
+	db.QueryRow("SELECT remainder_num, remainder_denom FROM fx_rounding_entries WHERE transaction_id = $1", transaction.ID).
+		Scan(&remainderNum, &remainderDenom)
+	if remainderNum != 21 || remainderDenom != 25 {
+		t.Errorf("Expected remainder 21/25 in the platform's favour, got %d/%d", remainderNum, remainderDenom)
+	}
+
+	_, err = service.Exchange(context.Background(), user.ID, dto.ExchangeRequest{
+		FromCurrency:  "EUR",
+		ToAmountCents: 5,
+	})
+	if err == nil {
+		t.Error("Expected minimum amount error for a tiny target")
+	}
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/internal/repository"
+	"mini-banking-platform/pkg/decimal"
+	"time"
+)
+
+const publishBackdateTolerance = time.Minute
+
+// FXRateProvider returns the rate that converts one major unit of
+// fromCurrency into toCurrency, effective at the given instant.
+type FXRateProvider interface {
+	GetRate(ctx context.Context, fromCurrency, toCurrency string, at time.Time) (*models.FXRate, error)
+}
+
+type FXRateService struct {
+	fxRateRepo      *repository.FXRateRepository
+	spreadRepo      *repository.FXSpreadRepository
+	roundingRepo    *repository.RoundingRepository
+	currencyRepo    *repository.CurrencyRepository
+	transactionRepo *repository.TransactionRepository
+	onRatePublished func()
+	logger          *slog.Logger
+}
+
+func NewFXRateService(
+	fxRateRepo *repository.FXRateRepository,
+	spreadRepo *repository.FXSpreadRepository,
+	roundingRepo *repository.RoundingRepository,
+	currencyRepo *repository.CurrencyRepository,
+	transactionRepo *repository.TransactionRepository,
+	logger *slog.Logger,
+) *FXRateService {
+	return &FXRateService{
+		fxRateRepo:      fxRateRepo,
+		spreadRepo:      spreadRepo,
+		roundingRepo:    roundingRepo,
+		currencyRepo:    currencyRepo,
+		transactionRepo: transactionRepo,
+		logger:          logger,
+	}
+}
+
+// OnRatePublished registers fn to be called after every successful
+// PublishRate. fn must not block.
+func (s *FXRateService) OnRatePublished(fn func()) {
+	s.onRatePublished = fn
+}
+
+// GetRate prefers whichever of the direct pair or the inverted reverse pair
+// was published most recently, so publishing one side of a pair is enough.
+func (s *FXRateService) GetRate(ctx context.Context, fromCurrency, toCurrency string, at time.Time) (*models.FXRate, error) {
+	return effectiveRate(ctx, s.fxRateRepo.FindEffective, fromCurrency, toCurrency, at)
+}
+
+// GetReferenceRate looks up the imported historical rates the same way
+// GetRate looks up live ones. It is meant for backtests and never prices a
+// transaction.
+func (s *FXRateService) GetReferenceRate(ctx context.Context, fromCurrency, toCurrency string, at time.Time) (*models.FXRate, error) {
+	return effectiveRate(ctx, s.fxRateRepo.FindReferenceEffective, fromCurrency, toCurrency, at)
+}
+
+func effectiveRate(ctx context.Context, find func(context.Context, string, string, time.Time) (*models.FXRate, error), fromCurrency, toCurrency string, at time.Time) (*models.FXRate, error) {
+	direct, err := find(ctx, fromCurrency, toCurrency, at)
+	if err != nil && !errors.Is(err, errorsx.ErrRateUnavailable) {
+		return nil, err
+	}
+
+	reverse, err := find(ctx, toCurrency, fromCurrency, at)
+	if err != nil && !errors.Is(err, errorsx.ErrRateUnavailable) {
+		return nil, err
+	}
+
+	if reverse == nil || (direct != nil && !reverse.ValidFrom.After(direct.ValidFrom)) {
+		if direct == nil {
+			return nil, errorsx.ErrRateUnavailable
+		}
+		return direct, nil
+	}
+
+	return &models.FXRate{
+		ID:            reverse.ID,
+		BaseCurrency:  fromCurrency,
+		QuoteCurrency: toCurrency,
+		RateNum:       reverse.RateDenom,
+		RateDenom:     reverse.RateNum,
+		ValidFrom:     reverse.ValidFrom,
+		Source:        reverse.Source,
+		CreatedAt:     reverse.CreatedAt,
+	}, nil
+}
+
+func (s *FXRateService) PublishRate(ctx context.Context, req dto.PublishFXRateRequest) (*models.FXRate, error) {
+	if req.BaseCurrency == req.QuoteCurrency {
+		return nil, errorsx.ErrCurrenciesMustDiffer
+	}
+	if _, err := s.currencyRepo.FindByCode(ctx, req.BaseCurrency); err != nil {
+		return nil, err
+	}
+	if _, err := s.currencyRepo.FindByCode(ctx, req.QuoteCurrency); err != nil {
+		return nil, err
+	}
+
+	rateNum, rateDenom, err := decimal.ParseRational(req.Rate)
+	if err != nil {
+		return nil, errorsx.BadRequest("rate must be a positive decimal number")
+	}
+
+	now := time.Now().UTC()
+	validFrom := now
+	if req.ValidFrom != nil {
+		validFrom = req.ValidFrom.UTC()
+		if validFrom.Before(now.Add(-publishBackdateTolerance)) {
+			return nil, errorsx.BadRequest("valid_from cannot be in the past")
+		}
+	}
+
+	rate := &models.FXRate{
+		BaseCurrency:  req.BaseCurrency,
+		QuoteCurrency: req.QuoteCurrency,
+		RateNum:       rateNum,
+		RateDenom:     rateDenom,
+		ValidFrom:     validFrom,
+		Source:        req.Source,
+	}
+	if err := s.fxRateRepo.Create(ctx, rate); err != nil {
+		s.logger.Error("failed to publish fx rate", "error", err)
+		return nil, fmt.Errorf("error publishing fx rate: %w", err)
+	}
+
+	s.logger.Info("fx rate published", "rateID", rate.ID, "base", rate.BaseCurrency, "quote", rate.QuoteCurrency,
+		"rateNum", rate.RateNum, "rateDenom", rate.RateDenom, "validFrom", rate.ValidFrom, "source", rate.Source)
+
+	if s.onRatePublished != nil {
+		s.onRatePublished()
+	}
+	return rate, nil
+}
+
+func (s *FXRateService) GetRateHistory(ctx context.Context, baseCurrency, quoteCurrency string, limit int) ([]models.FXRate, error) {
+	rates, err := s.fxRateRepo.FindByPair(ctx, baseCurrency, quoteCurrency, limit)
+	if err != nil {
+		s.logger.Error("failed to get fx rate history", "error", err, "base", baseCurrency, "quote", quoteCurrency)
+		return nil, fmt.Errorf("error getting fx rate history: %w", err)
+	}
+	return rates, nil
+}
+
+func (s *FXRateService) SetSpread(ctx context.Context, req dto.SetFXSpreadRequest) (*models.FXSpread, error) {
+	if req.BaseCurrency == req.QuoteCurrency {
+		return nil, errorsx.ErrCurrenciesMustDiffer
+	}
+	if _, err := s.currencyRepo.FindByCode(ctx, req.BaseCurrency); err != nil {
+		return nil, err
+	}
+	if _, err := s.currencyRepo.FindByCode(ctx, req.QuoteCurrency); err != nil {
+		return nil, err
+	}
+
+	spread := &models.FXSpread{
+		BaseCurrency:  req.BaseCurrency,
+		QuoteCurrency: req.QuoteCurrency,
+		BidBps:        req.BidBps,
+		AskBps:        req.AskBps,
+	}
+	if err := s.spreadRepo.Upsert(ctx, spread); err != nil {
+		s.logger.Error("failed to set fx spread", "error", err)
+		return nil, fmt.Errorf("error setting fx spread: %w", err)
+	}
+
+	s.logger.Info("fx spread updated", "base", spread.BaseCurrency, "quote", spread.QuoteCurrency, "bidBps", spread.BidBps, "askBps", spread.AskBps)
+	return spread, nil
+}
+
+func (s *FXRateService) GetSpreads(ctx context.Context) ([]models.FXSpread, error) {
+	spreads, err := s.spreadRepo.FindAll(ctx)
+	if err != nil {
+		s.logger.Error("failed to get fx spreads", "error", err)
+		return nil, fmt.Errorf("error getting fx spreads: %w", err)
+	}
+	return spreads, nil
+}
+
+func (s *FXRateService) GetRevenue(ctx context.Context, from, to time.Time) ([]models.CurrencyTotal, error) {
+	totals, err := s.transactionRepo.GetLedgerTotalsByUser(ctx, models.RevenueSystemUserID, from, to)
+	if err != nil {
+		s.logger.Error("failed to get fx revenue", "error", err)
+		return nil, fmt.Errorf("error getting fx revenue: %w", err)
+	}
+	return totals, nil
+}
+
+type FXImportResult struct {
+	Inserted        int            `json:"inserted"`
+	Existing        int            `json:"existing"`
+	UnknownCurrency map[string]int `json:"unknown_currency"`
+}
+
+// ImportRates stores historical rates in one DB transaction. They go to the
+// reference rates read by GetReferenceRate, not to the live rates, so an
+// import can neither change current prices nor backdate them. Rates for
+// currencies missing from the registry are counted and skipped, and rows that
+// were already imported from the same source are left untouched.
+func (s *FXRateService) ImportRates(ctx context.Context, rates []models.FXRate) (*FXImportResult, error) {
+	result := &FXImportResult{UnknownCurrency: make(map[string]int)}
+
+	known := make(map[string]bool)
+	currencies, err := s.currencyRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, currency := range currencies {
+		known[currency.Code] = true
+	}
+
+	tx, err := s.transactionRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for i := range rates {
+		rate := &rates[i]
+		if !known[rate.BaseCurrency] || !known[rate.QuoteCurrency] {
+			code := rate.QuoteCurrency
+			if !known[rate.BaseCurrency] {
+				code = rate.BaseCurrency
+			}
+			result.UnknownCurrency[code]++
+			continue
+		}
+
+		inserted, err := s.fxRateRepo.CreateReferenceIfMissing(ctx, tx, rate)
+		if err != nil {
+			return nil, err
+		}
+		if inserted {
+			result.Inserted++
+		} else {
+			result.Existing++
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit fx rate import", "error", err)
+		return nil, fmt.Errorf("error committing fx rate import: %w", err)
+	}
+
+	s.logger.Info("fx rates imported", "inserted", result.Inserted, "existing", result.Existing, "unknownCurrency", len(result.UnknownCurrency))
+	return result, nil
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/internal/repository"
+	"mini-banking-platform/pkg/decimal"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const defaultFXOrderTTL = 30 * 24 * time.Hour
+
+type FXOrderService struct {
+	orderRepo       *repository.FXOrderRepository
+	accountRepo     *repository.AccountRepository
+	transactionRepo *repository.TransactionRepository
+	currencyRepo    *repository.CurrencyRepository
+	transactions    *TransactionService
+	logger          *slog.Logger
+}
+
+func NewFXOrderService(
+	orderRepo *repository.FXOrderRepository,
+	accountRepo *repository.AccountRepository,
+	transactionRepo *repository.TransactionRepository,
+	currencyRepo *repository.CurrencyRepository,
+	transactions *TransactionService,
+	logger *slog.Logger,
+) *FXOrderService {
+	return &FXOrderService{
+		orderRepo:       orderRepo,
+		accountRepo:     accountRepo,
+		transactionRepo: transactionRepo,
+		currencyRepo:    currencyRepo,
+		transactions:    transactions,
+		logger:          logger,
+	}
+}
+
+// PlaceOrder reserves the source amount and records an open order. The
+// target rate is quoted like a published rate: units of to_currency per one
+// unit of from_currency.
+func (s *FXOrderService) PlaceOrder(ctx context.Context, userID string, req dto.PlaceFXOrderRequest) (*models.FXOrder, error) {
+	if req.AmountCents < models.MinExchangeAmountCents {
+		return nil, errorsx.BadRequest(fmt.Sprintf("minimum exchange amount is %d cents", models.MinExchangeAmountCents))
+	}
+
+	from, err := enabledCurrency(ctx, s.currencyRepo, req.FromCurrency)
+	if err != nil {
+		return nil, err
+	}
+	to, err := enabledCurrency(ctx, s.currencyRepo, req.ToCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if from.Code == to.Code {
+		return nil, errorsx.ErrCurrenciesMustDiffer
+	}
+
+	targetNum, targetDenom, err := decimal.ParseRational(req.TargetRate)
+	if err != nil {
+		return nil, errorsx.BadRequest("target_rate must be a positive decimal number")
+	}
+
+	now := time.Now().UTC()
+	expiresAt := now.Add(defaultFXOrderTTL)
+	if req.ExpiresAt != nil {
+		expiresAt = req.ExpiresAt.UTC()
+		if !expiresAt.After(now) {
+			return nil, errorsx.BadRequest("expires_at must be in the future")
+		}
+	}
+
+	fromAccount, err := s.accountRepo.FindByUserAndCurrency(ctx, userID, from.Code)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAccountStatus(fromAccount, true); err != nil {
+		return nil, err
+	}
+	if _, err := s.accountRepo.FindByUserAndCurrency(ctx, userID, to.Code); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.transactionRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := s.accountRepo.LockAccountsForUpdate(ctx, tx, []string{fromAccount.ID}); err != nil {
+		return nil, err
+	}
+
+	availableCents, err := s.accountRepo.GetAvailableCents(ctx, tx, fromAccount.ID)
+	if err != nil {
+		return nil, err
+	}
+	if availableCents < req.AmountCents {
+		s.logger.Warn("insufficient funds for fx order", "userID", userID, "available", availableCents, "required", req.AmountCents)
+		return nil, errorsx.ErrInsufficientFunds
+	}
+
+	if err := s.accountRepo.UpdateReservedCents(ctx, tx, fromAccount.ID, req.AmountCents); err != nil {
+		return nil, err
+	}
+
+	order := &models.FXOrder{
+		UserID:          userID,
+		FromCurrency:    from.Code,
+		ToCurrency:      to.Code,
+		AmountCents:     req.AmountCents,
+		TargetRate:      strings.TrimSpace(req.TargetRate),
+		TargetRateNum:   targetNum,
+		TargetRateDenom: targetDenom,
+		Status:          models.FXOrderStatusOpen,
+		ExpiresAt:       expiresAt,
+	}
+	if err := s.orderRepo.Create(ctx, tx, order); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit fx order", "error", err)
+		return nil, fmt.Errorf("error committing fx order: %w", err)
+	}
+
+	s.logger.Info("fx order placed", "orderID", order.ID, "userID", userID, "from", order.FromCurrency,
+		"to", order.ToCurrency, "amountCents", order.AmountCents, "targetRate", order.TargetRate)
+	return order, nil
+}
+
+func (s *FXOrderService) CancelOrder(ctx context.Context, userID, orderID string) (*models.FXOrder, error) {
+	tx, err := s.transactionRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	order, err := s.orderRepo.FindByIDForUpdate(ctx, tx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, errorsx.ErrOrderNotFound
+	}
+	if order.Status != models.FXOrderStatusOpen {
+		return nil, errorsx.ErrOrderNotOpen
+	}
+
+	if err := s.closeOrder(ctx, tx, order, models.FXOrderStatusCancelled, nil); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit fx order cancellation", "error", err)
+		return nil, fmt.Errorf("error committing fx order cancellation: %w", err)
+	}
+
+	s.logger.Info("fx order cancelled", "orderID", order.ID, "userID", userID)
+	return order, nil
+}
+
+func (s *FXOrderService) GetOrders(ctx context.Context, userID, status string) ([]models.FXOrder, error) {
+	orders, err := s.orderRepo.FindByUser(ctx, userID, status)
+	if err != nil {
+		s.logger.Error("failed to get fx orders", "error", err, "userID", userID)
+		return nil, fmt.Errorf("error getting fx orders: %w", err)
+	}
+	return orders, nil
+}
+
+// MatchOrders evaluates every open order against the rate effective at now.
+// Each order is handled in its own DB transaction so one failure does not
+// hold back the others. It returns the number of orders filled.
+func (s *FXOrderService) MatchOrders(ctx context.Context, now time.Time) (int, error) {
+	ids, err := s.orderRepo.FindOpenIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	filled := 0
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return filled, err
+		}
+
+		ok, err := s.matchOrder(ctx, id, now)
+		if err != nil {
+			if ctx.Err() != nil {
+				return filled, ctx.Err()
+			}
+			s.logger.Error("failed to fill fx order", "error", err, "orderID", id)
+			if failErr := s.failOrder(ctx, id, err); failErr != nil {
+				s.logger.Error("failed to mark fx order failed", "error", failErr, "orderID", id)
+			}
+			continue
+		}
+		if ok {
+			filled++
+		}
+	}
+
+	return filled, nil
+}
+
+func (s *FXOrderService) matchOrder(ctx context.Context, orderID string, now time.Time) (bool, error) {
+	tx, err := s.transactionRepo.BeginTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	order, err := s.orderRepo.FindByIDForUpdate(ctx, tx, orderID)
+	if err != nil {
+		return false, err
+	}
+	if order.Status != models.FXOrderStatusOpen {
+		return false, nil
+	}
+
+	if !now.Before(order.ExpiresAt) {
+		if err := s.closeOrder(ctx, tx, order, models.FXOrderStatusExpired, nil); err != nil {
+			return false, err
+		}
+		if err := tx.Commit(); err != nil {
+			return false, fmt.Errorf("error committing fx order expiry: %w", err)
+		}
+		s.logger.Info("fx order expired", "orderID", order.ID)
+		return false, nil
+	}
+
+	pricing, err := s.transactions.priceExchange(ctx, order.FromCurrency, order.ToCurrency, order.AmountCents, now)
+	if err != nil {
+		if errors.Is(err, errorsx.ErrRateUnavailable) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	reached, err := s.targetReached(ctx, order, pricing)
+	if err != nil || !reached {
+		return false, err
+	}
+
+	transaction, err := s.transactions.postExchange(ctx, tx, order.UserID, pricing, order.AmountCents)
+	if err != nil {
+		return false, err
+	}
+
+	order.Status = models.FXOrderStatusFilled
+	order.TransactionID = &transaction.ID
+	if err := s.orderRepo.UpdateStatus(ctx, tx, order); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("error committing fx order fill: %w", err)
+	}
+
+	s.logger.Info("fx order filled", "orderID", order.ID, "transactionID", transaction.ID,
+		"amountCents", pricing.FromAmountCents, "toAmountCents", pricing.ToAmountCents)
+	return true, nil
+}
+
+// targetReached compares the amount the customer would receive, after the
+// spread fee, with the amount the target rate promises.
+func (s *FXOrderService) targetReached(ctx context.Context, order *models.FXOrder, pricing *exchangePricing) (bool, error) {
+	from, err := s.currencyRepo.FindByCode(ctx, order.FromCurrency)
+	if err != nil {
+		return false, err
+	}
+	to, err := s.currencyRepo.FindByCode(ctx, order.ToCurrency)
+	if err != nil {
+		return false, err
+	}
+
+	targetNum, targetDenom := scaleRate(order.TargetRateNum, order.TargetRateDenom, from, to)
+	return rateReached(pricing.FromAmountCents, pricing.ToAmountCents, targetNum, targetDenom), nil
+}
+
+// rateReached reports whether toAmountCents/fromAmountCents >= num/denom.
+func rateReached(fromAmountCents, toAmountCents, num, denom int64) bool {
+	received := new(big.Int).Mul(big.NewInt(toAmountCents), big.NewInt(denom))
+	promised := new(big.Int).Mul(big.NewInt(fromAmountCents), big.NewInt(num))
+	return received.Cmp(promised) >= 0
+}
+
+func (s *FXOrderService) failOrder(ctx context.Context, orderID string, cause error) error {
+	tx, err := s.transactionRepo.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	order, err := s.orderRepo.FindByIDForUpdate(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+	if order.Status != models.FXOrderStatusOpen {
+		return nil
+	}
+
+	reason := cause.Error()
+	if err := s.closeOrder(ctx, tx, order, models.FXOrderStatusFailed, &reason); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// closeOrder releases the order's reservation and moves it to a final status.
+func (s *FXOrderService) closeOrder(ctx context.Context, tx *sqlx.Tx, order *models.FXOrder, status string, reason *string) error {
+	account, err := s.accountRepo.FindByUserAndCurrency(ctx, order.UserID, order.FromCurrency)
+	if err != nil {
+		return err
+	}
+	if err := s.accountRepo.LockAccountsForUpdate(ctx, tx, []string{account.ID}); err != nil {
+		return err
+	}
+	if err := s.accountRepo.UpdateReservedCents(ctx, tx, account.ID, -order.AmountCents); err != nil {
+		return err
+	}
+
+	order.Status = status
+	order.FailureReason = reason
+	return s.orderRepo.UpdateStatus(ctx, tx, order)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// FXOrderMatcher runs MatchOrders in the background: on every tick and
+// whenever a new rate is published. Notifications that arrive while a run is
+// in progress collapse into a single follow-up run.
+type FXOrderMatcher struct {
+	orders   *FXOrderService
+	interval time.Duration
+	notify   chan struct{}
+	cancel   context.CancelFunc
+	done     chan struct{}
+	logger   *slog.Logger
+}
+
+func NewFXOrderMatcher(orders *FXOrderService, interval time.Duration, logger *slog.Logger) *FXOrderMatcher {
+	return &FXOrderMatcher{
+		orders:   orders,
+		interval: interval,
+		notify:   make(chan struct{}, 1),
+		logger:   logger,
+	}
+}
+
+func (m *FXOrderMatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.done = make(chan struct{})
+
+	go m.run(ctx)
+	m.logger.Info("fx order matcher started", "interval", m.interval)
+}
+
+// Stop cancels the current run and waits for the loop to exit or for ctx to
+// be done, whichever comes first.
+func (m *FXOrderMatcher) Stop(ctx context.Context) error {
+	if m.cancel == nil {
+		return nil
+	}
+	m.cancel()
+
+	select {
+	case <-m.done:
+		m.logger.Info("fx order matcher stopped")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// NotifyRatePublished schedules a matching run without blocking the caller.
+func (m *FXOrderMatcher) NotifyRatePublished() {
+	select {
+	case m.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (m *FXOrderMatcher) run(ctx context.Context) {
+	defer close(m.done)
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-m.notify:
+		}
+
+		filled, err := m.orders.MatchOrders(ctx, time.Now().UTC())
+		if err != nil && ctx.Err() == nil {
+			m.logger.Error("fx order matching failed", "error", err)
+			continue
+		}
+		if filled > 0 {
+			m.logger.Info("fx orders filled", "count", filled)
+		}
+	}
+}
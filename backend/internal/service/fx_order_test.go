@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/internal/repository"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestRateReached(t *testing.T) {
+	tests := []struct {
+		from, to, num, denom int64
+		want                 bool
+	}{
+		{10000, 9500, 95, 100, true},
+		{10000, 9499, 95, 100, false},
+		{10000, 9600, 95, 100, true},
+		{3, 1, 1, 3, true},
+	}
+	for _, tt := range tests {
+		if got := rateReached(tt.from, tt.to, tt.num, tt.denom); got != tt.want {
+			t.Errorf("rateReached(%d, %d, %d/%d) = %v, want %v", tt.from, tt.to, tt.num, tt.denom, got, tt.want)
+		}
+	}
+}
+
+func TestFXOrder_FillsWhenRateReached(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	rates := NewFXRateService(repos.FXRate, repos.FXSpread, repos.Rounding, repos.Currency, repos.Transaction, logger)
+	transactions := newTestTransactionService(repos, logger)
+	orders := NewFXOrderService(repos.FXOrder, repos.Account, repos.Transaction, repos.Currency, transactions, logger)
+	ctx := context.Background()
+
+	createFXSystemAccounts(t, db)
+
+	user := createTestUser(t, db, "order@test.com")
+	createTestAccount(t, db, user.ID, "USD", 15000)
+	createTestAccount(t, db, user.ID, "EUR", 0)
+
+	order, err := orders.PlaceOrder(ctx, user.ID, dto.PlaceFXOrderRequest{
+		FromCurrency: "USD",
+		ToCurrency:   "EUR",
+		AmountCents:  10000,
+		TargetRate:   "0.95",
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+
+	_, err = transactions.Exchange(ctx, user.ID, dto.ExchangeRequest{FromCurrency: "USD", AmountCents: 6000})
+	if err != errorsx.ErrInsufficientFunds {
+		t.Errorf("Expected reserved funds to be unavailable, got %v", err)
+	}
+
+	filled, err := orders.MatchOrders(ctx, time.Now().UTC())
+	if err != nil {
+		t.Fatalf("MatchOrders failed: %v", err)
+	}
+	if filled != 0 {
+		t.Fatalf("Expected no fill below target, got %d", filled)
+	}
+
+	notified := false
+	rates.OnRatePublished(func() { notified = true })
+	_, err = rates.PublishRate(ctx, dto.PublishFXRateRequest{
+		BaseCurrency:  "USD",
+		QuoteCurrency: "EUR",
+		Rate:          "0.96",
+		Source:        "test",
+	})
+	if err != nil {
+		t.Fatalf("PublishRate failed: %v", err)
+	}
+	if !notified {
+		t.Error("Expected rate listener to be notified")
+	}
+
+	filled, err = orders.MatchOrders(ctx, time.Now().UTC())
+	if err != nil {
+		t.Fatalf("MatchOrders failed: %v", err)
+	}
+	if filled != 1 {
+		t.Fatalf("Expected 1 fill, got %d", filled)
+	}
+
+	got, err := orders.GetOrders(ctx, user.ID, "")
+	if err != nil {
+		t.Fatalf("GetOrders failed: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != order.ID || got[0].Status != models.FXOrderStatusFilled || got[0].TransactionID == nil {
+		t.Fatalf("Expected filled order with transaction, got %+v", got)
+	}
+
+	var account models.Account
+	db.Get(&account, "SELECT balance_cents, reserved_cents FROM accounts WHERE user_id = $1 AND currency = 'USD'", user.ID)
+	if account.BalanceCents != 5000 || account.ReservedCents != 0 {
+		t.Errorf("Expected USD balance 5000 with nothing reserved, got %d reserved %d", account.BalanceCents, account.ReservedCents)
+	}
+
+	var balanceEUR int64
+	db.Get(&balanceEUR, "SELECT balance_cents FROM accounts WHERE user_id = $1 AND currency = 'EUR'", user.ID)
+	if balanceEUR != 9600 {
+		t.Errorf("Expected EUR balance 9600, got %d", balanceEUR)
+	}
+}
+
+func TestFXOrder_CancelAndExpiryReleaseReservation(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	transactions := newTestTransactionService(repos, logger)
+	orders := NewFXOrderService(repos.FXOrder, repos.Account, repos.Transaction, repos.Currency, transactions, logger)
+	ctx := context.Background()
+
+	user := createTestUser(t, db, "cancel@test.com")
+	other := createTestUser(t, db, "other@test.com")
+	createTestAccount(t, db, user.ID, "USD", 10000)
+	createTestAccount(t, db, user.ID, "EUR", 0)
+
+	req := dto.PlaceFXOrderRequest{FromCurrency: "USD", ToCurrency: "EUR", AmountCents: 6000, TargetRate: "5"}
+	order, err := orders.PlaceOrder(ctx, user.ID, req)
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if _, err := orders.PlaceOrder(ctx, user.ID, req); err != errorsx.ErrInsufficientFunds {
+		t.Errorf("Expected second order to exceed available funds, got %v", err)
+	}
+
+	if _, err := orders.CancelOrder(ctx, other.ID, order.ID); err != errorsx.ErrOrderNotFound {
+		t.Errorf("Expected ErrOrderNotFound for another user, got %v", err)
+	}
+	cancelled, err := orders.CancelOrder(ctx, user.ID, order.ID)
+	if err != nil {
+		t.Fatalf("CancelOrder failed: %v", err)
+	}
+	if cancelled.Status != models.FXOrderStatusCancelled {
+		t.Errorf("Expected cancelled status, got %s", cancelled.Status)
+	}
+	if _, err := orders.CancelOrder(ctx, user.ID, order.ID); err != errorsx.ErrOrderNotOpen {
+		t.Errorf("Expected ErrOrderNotOpen, got %v", err)
+	}
+
+	expiresAt := time.Now().UTC().Add(time.Hour)
+	req.ExpiresAt = &expiresAt
+	order, err = orders.PlaceOrder(ctx, user.ID, req)
+	if err != nil {
+		t.Fatalf("PlaceOrder after cancel failed: %v", err)
+	}
+
+	if _, err := orders.MatchOrders(ctx, expiresAt.Add(time.Minute)); err != nil {
+		t.Fatalf("MatchOrders failed: %v", err)
+	}
+
+	expired, err := orders.GetOrders(ctx, user.ID, models.FXOrderStatusExpired)
+	if err != nil {
+		t.Fatalf("GetOrders failed: %v", err)
+	}
+	if len(expired) != 1 || expired[0].ID != order.ID {
+		t.Errorf("Expected order to expire, got %+v", expired)
+	}
+
+	var reserved int64
+	db.Get(&reserved, "SELECT reserved_cents FROM accounts WHERE user_id = $1 AND currency = 'USD'", user.ID)
+	if reserved != 0 {
+		t.Errorf("Expected reservation to be released, got %d", reserved)
+	}
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/models"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const maxPositionReportDays = 366
+
+type FXPositionPoint struct {
+	Date                 string `json:"date"`
+	Currency             string `json:"currency"`
+	PositionCents        int64  `json:"position_cents"`
+	HistoricalValueCents *int64 `json:"historical_value_cents"`
+	CurrentValueCents    *int64 `json:"current_value_cents"`
+}
+
+type FXPosition struct {
+	Currency          string `json:"currency"`
+	PositionCents     int64  `json:"position_cents"`
+	CurrentValueCents *int64 `json:"current_value_cents"`
+	RealizedPnLCents  *int64 `json:"realized_pnl_cents"`
+}
+
+// FXPositionReport shows the net position of the FX system accounts. Values
+// and P&L are in minor units of BaseCurrency; a nil value means no rate to
+// the base currency was available.
+type FXPositionReport struct {
+	BaseCurrency          string            `json:"base_currency"`
+	From                  time.Time         `json:"from"`
+	To                    time.Time         `json:"to"`
+	Positions             []FXPosition      `json:"positions"`
+	Series                []FXPositionPoint `json:"series"`
+	TotalRealizedPnLCents int64             `json:"total_realized_pnl_cents"`
+}
+
+// rateHistory answers GetRate for one currency against the base currency from
+// rates loaded up front, scaled to minor units.
+type rateHistory struct {
+	direct  []models.FXRate
+	reverse []models.FXRate
+	from    *models.Currency
+	to      *models.Currency
+}
+
+func (h *rateHistory) at(t time.Time) *big.Rat {
+	if h.from.Code == h.to.Code {
+		return big.NewRat(1, 1)
+	}
+
+	var direct, reverse *models.FXRate
+	for i := range h.direct {
+		if !h.direct[i].ValidFrom.After(t) {
+			direct = &h.direct[i]
+			break
+		}
+	}
+	for i := range h.reverse {
+		if !h.reverse[i].ValidFrom.After(t) {
+			reverse = &h.reverse[i]
+			break
+		}
+	}
+
+	var num, denom int64
+	switch {
+	case reverse == nil || (direct != nil && !reverse.ValidFrom.After(direct.ValidFrom)):
+		if direct == nil {
+			return nil
+		}
+		num, denom = direct.RateNum, direct.RateDenom
+	default:
+		num, denom = reverse.RateDenom, reverse.RateNum
+	}
+	num, denom = scaleRate(num, denom, h.from, h.to)
+	return big.NewRat(num, denom)
+}
+
+// positionBook tracks one currency with average-cost accounting: reducing a
+// position realizes the difference between the current value and the share
+// of the cost basis being released.
+type positionBook struct {
+	position   int64
+	cost       *big.Rat
+	realized   *big.Rat
+	incomplete bool
+}
+
+func (b *positionBook) apply(amountCents int64, rate *big.Rat, realize bool) {
+	if rate == nil {
+		b.incomplete = true
+		b.position += amountCents
+		return
+	}
+
+	value := new(big.Rat).Mul(big.NewRat(amountCents, 1), rate)
+	if b.position == 0 || (b.position > 0) == (amountCents > 0) {
+		b.cost.Add(b.cost, value)
+		b.position += amountCents
+		return
+	}
+
+	closing := amountCents
+	if abs64(closing) > abs64(b.position) {
+		closing = -b.position
+	}
+	released := new(big.Rat).Mul(b.cost, big.NewRat(-closing, b.position))
+	proceeds := new(big.Rat).Mul(big.NewRat(-closing, 1), rate)
+	if realize {
+		b.realized.Add(b.realized, proceeds.Sub(proceeds, released))
+	}
+	b.cost.Sub(b.cost, released)
+	b.position += closing
+
+	if opening := amountCents - closing; opening != 0 {
+		b.cost.Add(b.cost, new(big.Rat).Mul(big.NewRat(opening, 1), rate))
+		b.position += opening
+	}
+}
+
+func (s *FXRateService) GetPositionReport(ctx context.Context, baseCode string, from, to time.Time) (*FXPositionReport, error) {
+	if !from.Before(to) {
+		return nil, errorsx.BadRequest("from must be before to")
+	}
+	if to.Sub(from) > maxPositionReportDays*24*time.Hour {
+		return nil, errorsx.BadRequest(fmt.Sprintf("report range is limited to %d days", maxPositionReportDays))
+	}
+
+	base, err := s.currencyRepo.FindByCode(ctx, baseCode)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.transactionRepo.FindLedgerEntriesByUser(ctx, models.FXSystemUserID, to)
+	if err != nil {
+		s.logger.Error("failed to get fx ledger entries", "error", err)
+		return nil, fmt.Errorf("error getting fx ledger entries: %w", err)
+	}
+
+	now := time.Now().UTC()
+	histories := make(map[string]*rateHistory)
+	history := func(code string) (*rateHistory, error) {
+		if h, ok := histories[code]; ok {
+			return h, nil
+		}
+		currency, err := s.currencyRepo.FindByCode(ctx, code)
+		if err != nil {
+			return nil, err
+		}
+		h := &rateHistory{from: currency, to: base}
+		if code != base.Code {
+			if h.direct, err = s.fxRateRepo.FindHistory(ctx, code, base.Code, now); err != nil {
+				return nil, err
+			}
+			if h.reverse, err = s.fxRateRepo.FindHistory(ctx, base.Code, code, now); err != nil {
+				return nil, err
+			}
+		}
+		histories[code] = h
+		return h, nil
+	}
+
+	books := make(map[string]*positionBook)
+	var codes []string
+	for _, entry := range entries {
+		if _, ok := books[entry.Currency]; ok {
+			continue
+		}
+		if _, err := history(entry.Currency); err != nil {
+			return nil, err
+		}
+		books[entry.Currency] = &positionBook{cost: new(big.Rat), realized: new(big.Rat)}
+		codes = append(codes, entry.Currency)
+	}
+	sort.Strings(codes)
+
+	report := &FXPositionReport{BaseCurrency: base.Code, From: from, To: to}
+
+	snapshot := func(dayEnd time.Time) {
+		for _, code := range codes {
+			h := histories[code]
+			position := books[code].position
+			report.Series = append(report.Series, FXPositionPoint{
+				Date:                 dayEnd.AddDate(0, 0, -1).Format("2006-01-02"),
+				Currency:             code,
+				PositionCents:        position,
+				HistoricalValueCents: valueCents(position, h.at(dayEnd)),
+				CurrentValueCents:    valueCents(position, h.at(now)),
+			})
+		}
+	}
+
+	dayEnd := from.AddDate(0, 0, 1)
+	for _, entry := range entries {
+		for !entry.CreatedAt.Before(dayEnd) && !dayEnd.After(to) {
+			snapshot(dayEnd)
+			dayEnd = dayEnd.AddDate(0, 0, 1)
+		}
+
+		book := books[entry.Currency]
+		if entry.Currency == base.Code {
+			book.position += entry.AmountCents
+			continue
+		}
+		book.apply(entry.AmountCents, histories[entry.Currency].at(entry.CreatedAt), !entry.CreatedAt.Before(from))
+	}
+	for ; !dayEnd.After(to); dayEnd = dayEnd.AddDate(0, 0, 1) {
+		snapshot(dayEnd)
+	}
+
+	for _, code := range codes {
+		book := books[code]
+		position := FXPosition{
+			Currency:          code,
+			PositionCents:     book.position,
+			CurrentValueCents: valueCents(book.position, histories[code].at(now)),
+		}
+		if !book.incomplete {
+			pnl := ratCents(book.realized)
+			position.RealizedPnLCents = &pnl
+			report.TotalRealizedPnLCents += pnl
+		}
+		report.Positions = append(report.Positions, position)
+	}
+
+	return report, nil
+}
+
+func valueCents(amountCents int64, rate *big.Rat) *int64 {
+	if rate == nil {
+		return nil
+	}
+	value := ratCents(new(big.Rat).Mul(big.NewRat(amountCents, 1), rate))
+	return &value
+}
+
+// ratCents rounds to the nearest minor unit, halves away from zero.
+func ratCents(r *big.Rat) int64 {
+	cents, _ := strconv.ParseInt(r.FloatString(0), 10, 64)
+	return cents
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
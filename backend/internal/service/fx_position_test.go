@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"math/big"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/repository"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestPositionBook_AverageCostRealizedPnL(t *testing.T) {
+	book := &positionBook{cost: new(big.Rat), realized: new(big.Rat)}
+
+	book.apply(100, big.NewRat(1, 1), true)
+	book.apply(-50, big.NewRat(2, 1), true)
+	if book.realized.Cmp(big.NewRat(50, 1)) != 0 {
+		t.Errorf("Expected realized 50 after partial close, got %s", book.realized.RatString())
+	}
+
+	// closes the remaining 50 long and opens a 50 short at rate 3
+	book.apply(-100, big.NewRat(3, 1), true)
+	if book.realized.Cmp(big.NewRat(150, 1)) != 0 {
+		t.Errorf("Expected realized 150 after flip, got %s", book.realized.RatString())
+	}
+	if book.position != -50 || book.cost.Cmp(big.NewRat(-150, 1)) != 0 {
+		t.Errorf("Expected short 50 with cost -150, got %d with cost %s", book.position, book.cost.RatString())
+	}
+}
+
+func TestFXPositionReport(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	rates := NewFXRateService(repos.FXRate, repos.FXSpread, repos.Rounding, repos.Currency, repos.Transaction, logger)
+	service := newTestTransactionService(repos, logger)
+
+	createFXSystemAccounts(t, db)
+
+	user := createTestUser(t, db, "position@test.com")
+	createTestAccount(t, db, user.ID, "USD", 0)
+	createTestAccount(t, db, user.ID, "EUR", 18400)
+
+	// FX desk buys 9200 EUR for 10000 USD at the seeded rate
+	if _, err := service.Exchange(context.Background(), user.ID, dto.ExchangeRequest{FromCurrency: "EUR", AmountCents: 9200}); err != nil {
+		t.Fatalf("Exchange failed: %v", err)
+	}
+
+	if _, err := rates.PublishRate(context.Background(), dto.PublishFXRateRequest{
+		BaseCurrency:  "EUR",
+		QuoteCurrency: "USD",
+		Rate:          "1.25",
+		Source:        "test",
+	}); err != nil {
+		t.Fatalf("PublishRate failed: %v", err)
+	}
+
+	// and sells half of it back at 1.25 USD per EUR
+	if _, err := service.Exchange(context.Background(), user.ID, dto.ExchangeRequest{FromCurrency: "USD", ToAmountCents: 4600}); err != nil {
+		t.Fatalf("Exchange failed: %v", err)
+	}
+
+	to := time.Now().UTC().AddDate(0, 0, 1).Truncate(24 * time.Hour)
+	report, err := rates.GetPositionReport(context.Background(), "USD", to.AddDate(0, 0, -2), to)
+	if err != nil {
+		t.Fatalf("GetPositionReport failed: %v", err)
+	}
+
+	var eurFound bool
+	for _, position := range report.Positions {
+		if position.Currency != "EUR" {
+			continue
+		}
+		eurFound = true
+		if position.PositionCents != 4600 {
+			t.Errorf("Expected EUR position 4600, got %d", position.PositionCents)
+		}
+		if position.CurrentValueCents == nil || *position.CurrentValueCents != 5750 {
+			t.Errorf("Expected current value 5750 USD cents, got %v", position.CurrentValueCents)
+		}
+		// 4600 EUR bought at 25/23 (5000 USD) and sold at 1.25 (5750 USD)
+		if position.RealizedPnLCents == nil || *position.RealizedPnLCents != 750 {
+			t.Errorf("Expected realized P&L 750 USD cents, got %v", position.RealizedPnLCents)
+		}
+	}
+	if !eurFound {
+		t.Fatalf("Expected EUR position in report, got %+v", report.Positions)
+	}
+	if len(report.Series) != 4 {
+		t.Errorf("Expected 2 days x 2 currencies in series, got %d", len(report.Series))
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/internal/repository"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestFXRate_EffectiveAtExecutionTime(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	rates := NewFXRateService(repos.FXRate, repos.FXSpread, repos.Rounding, repos.Currency, repos.Transaction, logger)
+	ctx := context.Background()
+
+	t1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	for _, r := range []*models.FXRate{
+		{BaseCurrency: "GBP", QuoteCurrency: "CHF", RateNum: 11, RateDenom: 10, ValidFrom: t1, Source: "test"},
+		{BaseCurrency: "GBP", QuoteCurrency: "CHF", RateNum: 12, RateDenom: 10, ValidFrom: t2, Source: "test"},
+	} {
+		if err := repos.FXRate.Create(ctx, r); err != nil {
+			t.Fatalf("Failed to create rate: %v", err)
+		}
+	}
+
+	rate, err := rates.GetRate(ctx, "GBP", "CHF", t1.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("GetRate failed: %v", err)
+	}
+	if rate.RateNum != 11 || rate.RateDenom != 10 {
+		t.Errorf("Expected historical rate 11/10, got %d/%d", rate.RateNum, rate.RateDenom)
+	}
+
+	rate, err = rates.GetRate(ctx, "CHF", "GBP", t2.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetRate for inverse pair failed: %v", err)
+	}
+	if rate.RateNum != 10 || rate.RateDenom != 12 {
+		t.Errorf("Expected inverted rate 10/12, got %d/%d", rate.RateNum, rate.RateDenom)
+	}
+
+	_, err = rates.GetRate(ctx, "GBP", "CHF", t1.Add(-time.Hour))
+	if err != errorsx.ErrRateUnavailable {
+		t.Errorf("Expected ErrRateUnavailable before first rate, got %v", err)
+	}
+}
+
+func TestFXRate_PublishRejectsBackdated(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	rates := NewFXRateService(repos.FXRate, repos.FXSpread, repos.Rounding, repos.Currency, repos.Transaction, logger)
+
+	past := time.Now().Add(-24 * time.Hour)
+	_, err := rates.PublishRate(context.Background(), dto.PublishFXRateRequest{
+		BaseCurrency:  "USD",
+		QuoteCurrency: "EUR",
+		Rate:          "0.95",
+		ValidFrom:     &past,
+		Source:        "test",
+	})
+	if err == nil {
+		t.Error("Expected error for backdated rate")
+	}
+}
+
+func TestExchange_RecordsAppliedRate(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	rates := NewFXRateService(repos.FXRate, repos.FXSpread, repos.Rounding, repos.Currency, repos.Transaction, logger)
+	service := newTestTransactionService(repos, logger)
+
+	createFXSystemAccounts(t, db)
+
+	user := createTestUser(t, db, "rate@test.com")
+	createTestAccount(t, db, user.ID, "USD", 10000)
+	createTestAccount(t, db, user.ID, "EUR", 0)
+
+	published, err := rates.PublishRate(context.Background(), dto.PublishFXRateRequest{
+		BaseCurrency:  "USD",
+		QuoteCurrency: "EUR",
+		Rate:          "0.9",
+		Source:        "test",
+	})
+	if err != nil {
+		t.Fatalf("PublishRate failed: %v", err)
+	}
+
+	tx, err := service.Exchange(context.Background(), user.ID, dto.ExchangeRequest{
+		FromCurrency: "USD",
+		AmountCents:  10000,
+	})
+	if err != nil {
+		t.Fatalf("Exchange failed: %v", err)
+	}
+
+	if tx.FXRateID == nil || *tx.FXRateID != published.ID {
+		t.Errorf("Expected transaction to reference rate %s, got %v", published.ID, tx.FXRateID)
+	}
+
+	var balanceEUR int64
+	db.Get(&balanceEUR, "SELECT balance_cents FROM accounts WHERE user_id = $1 AND currency = 'EUR'", user.ID)
+	if balanceEUR != 9000 {
+		t.Errorf("Expected EUR balance 9000 at published rate, got %d", balanceEUR)
+	}
+}
+
+func TestFXRate_ImportIsIdempotent(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	rates := NewFXRateService(repos.FXRate, repos.FXSpread, repos.Rounding, repos.Currency, repos.Transaction, logger)
+
+	day := time.Date(2020, 3, 2, 0, 0, 0, 0, time.UTC)
+	batch := func() []models.FXRate {
+		return []models.FXRate{
+			{BaseCurrency: "EUR", QuoteCurrency: "USD", RateNum: 11, RateDenom: 10, ValidFrom: day, Source: "test-import"},
+			{BaseCurrency: "EUR", QuoteCurrency: "USD", RateNum: 12, RateDenom: 10, ValidFrom: day.AddDate(0, 0, 1), Source: "test-import"},
+			{BaseCurrency: "EUR", QuoteCurrency: "XAU", RateNum: 1, RateDenom: 1500, ValidFrom: day, Source: "test-import"},
+		}
+	}
+
+	result, err := rates.ImportRates(context.Background(), batch())
+	if err != nil {
+		t.Fatalf("ImportRates failed: %v", err)
+	}
+	if result.Inserted != 2 || result.UnknownCurrency["XAU"] != 1 {
+		t.Errorf("Expected 2 inserted and XAU skipped, got %+v", result)
+	}
+
+	result, err = rates.ImportRates(context.Background(), batch())
+	if err != nil {
+		t.Fatalf("second ImportRates failed: %v", err)
+	}
+	if result.Inserted != 0 || result.Existing != 2 {
+		t.Errorf("Expected re-import to skip the existing rate, got %+v", result)
+	}
+
+	// Imported rates are for backtests only; live pricing keeps the seed rate.
+	live, err := rates.GetRate(context.Background(), "EUR", "USD", day.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetRate failed: %v", err)
+	}
+	if live.Source == "test-import" {
+		t.Errorf("Expected the imported rate to stay out of live pricing, got %+v", live)
+	}
+
+	if _, err := rates.GetReferenceRate(context.Background(), "EUR", "USD", day.Add(-time.Minute)); err != errorsx.ErrRateUnavailable {
+		t.Errorf("Expected no reference rate before valid_from, got %v", err)
+	}
+	rate, err := rates.GetReferenceRate(context.Background(), "USD", "EUR", day.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("GetReferenceRate failed: %v", err)
+	}
+	if rate.RateNum != 10 || rate.RateDenom != 11 {
+		t.Errorf("Expected inverted reference rate 10/11, got %d/%d", rate.RateNum, rate.RateDenom)
+	}
+
+	// A backtest between two publications sees the earlier one, and the next
+	// day's rate only from its own publication on.
+	rate, err = rates.GetReferenceRate(context.Background(), "EUR", "USD", day.AddDate(0, 0, 1).Add(-time.Second))
+	if err != nil || rate.RateNum != 11 {
+		t.Errorf("Expected the first day's rate just before the second publication, got %+v, %v", rate, err)
+	}
+	rate, err = rates.GetReferenceRate(context.Background(), "EUR", "USD", day.AddDate(0, 0, 30))
+	if err != nil || rate.RateNum != 12 || rate.RateDenom != 10 {
+		t.Errorf("Expected the latest imported rate 12/10, got %+v, %v", rate, err)
+	}
+}
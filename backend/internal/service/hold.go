@@ -0,0 +1,370 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/internal/repository"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const defaultHoldTTL = 7 * 24 * time.Hour
+
+type HoldService struct {
+	holdRepo        *repository.HoldRepository
+	accountRepo     *repository.AccountRepository
+	transactionRepo *repository.TransactionRepository
+	logger          *slog.Logger
+}
+
+func NewHoldService(
+	holdRepo *repository.HoldRepository,
+	accountRepo *repository.AccountRepository,
+	transactionRepo *repository.TransactionRepository,
+	logger *slog.Logger,
+) *HoldService {
+	return &HoldService{
+		holdRepo:        holdRepo,
+		accountRepo:     accountRepo,
+		transactionRepo: transactionRepo,
+		logger:          logger,
+	}
+}
+
+// PlaceHold reserves amount_cents on one of the user's accounts in favour of
+// the payee account. No money moves until the payee captures it.
+func (s *HoldService) PlaceHold(ctx context.Context, userID string, req dto.PlaceHoldRequest) (*models.Hold, error) {
+	now := time.Now().UTC()
+	expiresAt := now.Add(defaultHoldTTL)
+	if req.ExpiresAt != nil {
+		expiresAt = req.ExpiresAt.UTC()
+		if !expiresAt.After(now) {
+			return nil, errorsx.BadRequest("expires_at must be in the future")
+		}
+	}
+
+	account, err := s.accountRepo.FindByID(ctx, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireAccountRole(ctx, s.accountRepo, account, userID, models.AccountRoleCanTransact); err != nil {
+		return nil, err
+	}
+	if err := checkAccountStatus(account, true); err != nil {
+		return nil, err
+	}
+
+	payee, err := s.accountRepo.FindByID(ctx, req.PayeeAccountID)
+	if err != nil {
+		return nil, err
+	}
+	if payee.ID == account.ID {
+		return nil, errorsx.ErrCannotTransferToSelf
+	}
+	if payee.Currency != account.Currency {
+		return nil, errorsx.BadRequest("payee account must be in the same currency")
+	}
+
+	tx, err := s.transactionRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := s.accountRepo.LockAccountsForUpdate(ctx, tx, []string{account.ID}); err != nil {
+		return nil, err
+	}
+
+	availableCents, err := s.accountRepo.GetAvailableCents(ctx, tx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+	if availableCents < req.AmountCents {
+		s.logger.Warn("insufficient funds for hold", "userID", userID, "available", availableCents, "required", req.AmountCents)
+		return nil, errorsx.ErrInsufficientFunds
+	}
+
+	if err := s.accountRepo.UpdateReservedCents(ctx, tx, account.ID, req.AmountCents); err != nil {
+		return nil, err
+	}
+
+	hold := &models.Hold{
+		AccountID:      account.ID,
+		PayeeAccountID: payee.ID,
+		Currency:       account.Currency,
+		AmountCents:    req.AmountCents,
+		Description:    strings.TrimSpace(req.Description),
+		Status:         models.HoldStatusActive,
+		ExpiresAt:      expiresAt,
+	}
+	if err := s.holdRepo.Create(ctx, tx, hold); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit hold", "error", err)
+		return nil, fmt.Errorf("error committing hold: %w", err)
+	}
+
+	s.logger.Info("hold placed", "holdID", hold.ID, "accountID", account.ID, "payeeAccountID", payee.ID, "amountCents", hold.AmountCents)
+	return hold, nil
+}
+
+// CaptureHold lets the payee collect up to the held amount. A partial
+// capture releases the rest; a hold can be captured only once.
+func (s *HoldService) CaptureHold(ctx context.Context, userID, holdID string, req dto.CaptureHoldRequest) (*models.Hold, error) {
+	tx, err := s.transactionRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	hold, err := s.holdRepo.FindByIDForUpdate(ctx, tx, holdID)
+	if err != nil {
+		return nil, err
+	}
+	account, payee, err := s.holdAccounts(ctx, hold)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireAccountRole(ctx, s.accountRepo, payee, userID, models.AccountRoleCanTransact); err != nil {
+		if errors.Is(err, errorsx.ErrAccountNotFound) && requireAccountRole(ctx, s.accountRepo, account, userID, models.AccountRoleViewOnly) == nil {
+			return nil, errorsx.BadRequest("only the payee can capture a hold")
+		}
+		return nil, holdAccessError(err)
+	}
+	if hold.Status != models.HoldStatusActive {
+		return nil, errorsx.ErrHoldNotActive
+	}
+	if !time.Now().UTC().Before(hold.ExpiresAt) {
+		if err := s.closeHold(ctx, tx, hold, models.HoldStatusExpired); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(); err != nil {
+			return nil, fmt.Errorf("error committing hold expiry: %w", err)
+		}
+		return nil, errorsx.ErrHoldNotActive
+	}
+
+	amountCents := req.AmountCents
+	if amountCents == 0 {
+		amountCents = hold.AmountCents
+	}
+	if amountCents > hold.AmountCents {
+		return nil, errorsx.BadRequest("capture amount exceeds the held amount")
+	}
+	if err := checkAccountStatus(account, true); err != nil {
+		return nil, err
+	}
+	if err := checkAccountStatus(payee, false); err != nil {
+		return nil, err
+	}
+
+	if err := s.accountRepo.LockAccountsForUpdate(ctx, tx, []string{account.ID, payee.ID}); err != nil {
+		return nil, err
+	}
+	if err := s.accountRepo.UpdateReservedCents(ctx, tx, account.ID, -hold.AmountCents); err != nil {
+		return nil, err
+	}
+
+	availableCents, err := s.accountRepo.GetAvailableCents(ctx, tx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+	if availableCents < amountCents {
+		s.logger.Warn("insufficient funds for hold capture", "holdID", hold.ID, "available", availableCents, "required", amountCents)
+		return nil, errorsx.ErrInsufficientFunds
+	}
+
+	description := "Capture of hold"
+	if hold.Description != "" {
+		description = hold.Description
+	}
+	transaction := &models.Transaction{
+		Type:        models.TransactionTypeTransfer,
+		FromUserID:  account.UserID,
+		ToUserID:    &payee.UserID,
+		Currency:    hold.Currency,
+		AmountCents: amountCents,
+		Description: description,
+	}
+	if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
+		return nil, err
+	}
+
+	entries := []models.LedgerEntry{
+		{TransactionID: transaction.ID, AccountID: account.ID, Currency: hold.Currency, AmountCents: -amountCents},
+		{TransactionID: transaction.ID, AccountID: payee.ID, Currency: hold.Currency, AmountCents: amountCents},
+	}
+	for i := range entries {
+		if err := s.transactionRepo.CreateLedgerEntry(ctx, tx, &entries[i]); err != nil {
+			return nil, err
+		}
+		if err := s.accountRepo.UpdateBalanceCents(ctx, tx, entries[i].AccountID, entries[i].AmountCents); err != nil {
+			return nil, err
+		}
+	}
+
+	hold.Status = models.HoldStatusCaptured
+	hold.CapturedCents = amountCents
+	hold.TransactionID = &transaction.ID
+	if err := s.holdRepo.UpdateStatus(ctx, tx, hold); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit hold capture", "error", err)
+		return nil, fmt.Errorf("error committing hold capture: %w", err)
+	}
+
+	s.logger.Info("hold captured", "holdID", hold.ID, "transactionID", transaction.ID, "capturedCents", amountCents, "heldCents", hold.AmountCents)
+	return hold, nil
+}
+
+// ReleaseHold cancels an active hold. Only the payee may release it early;
+// otherwise the payer could drop a hold before it is captured, so it would
+// guarantee nothing. Holds left alone are released by ExpireHolds.
+func (s *HoldService) ReleaseHold(ctx context.Context, userID, holdID string) (*models.Hold, error) {
+	tx, err := s.transactionRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	hold, err := s.holdRepo.FindByIDForUpdate(ctx, tx, holdID)
+	if err != nil {
+		return nil, err
+	}
+	account, payee, err := s.holdAccounts(ctx, hold)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireAccountRole(ctx, s.accountRepo, payee, userID, models.AccountRoleCanTransact); err != nil {
+		if errors.Is(err, errorsx.ErrAccountNotFound) && requireAccountRole(ctx, s.accountRepo, account, userID, models.AccountRoleViewOnly) == nil {
+			return nil, errorsx.BadRequest("only the payee can release a hold")
+		}
+		return nil, holdAccessError(err)
+	}
+	if hold.Status != models.HoldStatusActive {
+		return nil, errorsx.ErrHoldNotActive
+	}
+
+	if err := s.closeHold(ctx, tx, hold, models.HoldStatusReleased); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit hold release", "error", err)
+		return nil, fmt.Errorf("error committing hold release: %w", err)
+	}
+
+	s.logger.Info("hold released", "holdID", hold.ID, "userID", userID)
+	return hold, nil
+}
+
+func (s *HoldService) GetHolds(ctx context.Context, userID, status string) ([]models.Hold, error) {
+	holds, err := s.holdRepo.FindByUser(ctx, userID, status)
+	if err != nil {
+		s.logger.Error("failed to get holds", "error", err, "userID", userID)
+		return nil, fmt.Errorf("error getting holds: %w", err)
+	}
+	return holds, nil
+}
+
+// ExpireHolds releases every active hold whose expiry has passed. It returns
+// the number of holds expired.
+func (s *HoldService) ExpireHolds(ctx context.Context, now time.Time) (int, error) {
+	ids, err := s.holdRepo.FindExpiredIDs(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return expired, err
+		}
+		ok, err := s.expireHold(ctx, id, now)
+		if err != nil {
+			if ctx.Err() != nil {
+				return expired, ctx.Err()
+			}
+			s.logger.Error("failed to expire hold", "error", err, "holdID", id)
+			continue
+		}
+		if ok {
+			expired++
+		}
+	}
+
+	return expired, nil
+}
+
+func (s *HoldService) expireHold(ctx context.Context, holdID string, now time.Time) (bool, error) {
+	tx, err := s.transactionRepo.BeginTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	hold, err := s.holdRepo.FindByIDForUpdate(ctx, tx, holdID)
+	if err != nil {
+		return false, err
+	}
+	if hold.Status != models.HoldStatusActive || now.Before(hold.ExpiresAt) {
+		return false, nil
+	}
+
+	if err := s.closeHold(ctx, tx, hold, models.HoldStatusExpired); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("error committing hold expiry: %w", err)
+	}
+
+	s.logger.Info("hold expired", "holdID", hold.ID)
+	return true, nil
+}
+
+func (s *HoldService) holdAccounts(ctx context.Context, hold *models.Hold) (*models.Account, *models.Account, error) {
+	account, err := s.accountRepo.FindByID(ctx, hold.AccountID)
+	if err != nil {
+		return nil, nil, err
+	}
+	payee, err := s.accountRepo.FindByID(ctx, hold.PayeeAccountID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return account, payee, nil
+}
+
+// closeHold releases the hold's reservation and moves it to a final status.
+func (s *HoldService) closeHold(ctx context.Context, tx *sqlx.Tx, hold *models.Hold, status string) error {
+	if err := s.accountRepo.LockAccountsForUpdate(ctx, tx, []string{hold.AccountID}); err != nil {
+		return err
+	}
+	if err := s.accountRepo.UpdateReservedCents(ctx, tx, hold.AccountID, -hold.AmountCents); err != nil {
+		return err
+	}
+
+	hold.Status = status
+	return s.holdRepo.UpdateStatus(ctx, tx, hold)
+}
+
+// holdAccessError reports a hold on accounts the user cannot see at all as
+// not found, and any other access failure as is.
+func holdAccessError(errs ...error) error {
+	for _, err := range errs {
+		if !errors.Is(err, errorsx.ErrAccountNotFound) {
+			return err
+		}
+	}
+	return errorsx.ErrHoldNotFound
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// HoldExpirer releases expired holds in the background so their reservation
+// does not outlive them.
+type HoldExpirer struct {
+	holds    *HoldService
+	interval time.Duration
+	cancel   context.CancelFunc
+	done     chan struct{}
+	logger   *slog.Logger
+}
+
+func NewHoldExpirer(holds *HoldService, interval time.Duration, logger *slog.Logger) *HoldExpirer {
+	return &HoldExpirer{
+		holds:    holds,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+func (e *HoldExpirer) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+	e.done = make(chan struct{})
+
+	go e.run(ctx)
+	e.logger.Info("hold expirer started", "interval", e.interval)
+}
+
+// Stop cancels the current run and waits for the loop to exit or for ctx to
+// be done, whichever comes first.
+func (e *HoldExpirer) Stop(ctx context.Context) error {
+	if e.cancel == nil {
+		return nil
+	}
+	e.cancel()
+
+	select {
+	case <-e.done:
+		e.logger.Info("hold expirer stopped")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *HoldExpirer) run(ctx context.Context) {
+	defer close(e.done)
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		expired, err := e.holds.ExpireHolds(ctx, time.Now().UTC())
+		if err != nil && ctx.Err() == nil {
+			e.logger.Error("hold expiry failed", "error", err)
+			continue
+		}
+		if expired > 0 {
+			e.logger.Info("holds expired", "count", expired)
+		}
+	}
+}
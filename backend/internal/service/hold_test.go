@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/internal/repository"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestHold_CaptureReleaseAndExpiry(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	holds := NewHoldService(repos.Hold, repos.Account, repos.Transaction, logger)
+	accounts := NewAccountService(repos.Account, repos.Transaction, repos.Currency, logger)
+	transactions := newTestTransactionService(repos, logger)
+	ctx := context.Background()
+
+	payer := createTestUser(t, db, "payer@test.com")
+	merchant := createTestUser(t, db, "merchant@test.com")
+	payerAccount := createTestAccount(t, db, payer.ID, "USD", 10000)
+	merchantAccount := createTestAccount(t, db, merchant.ID, "USD", 0)
+
+	hold, err := holds.PlaceHold(ctx, payer.ID, dto.PlaceHoldRequest{
+		AccountID:      payerAccount.ID,
+		PayeeAccountID: merchantAccount.ID,
+		AmountCents:    6000,
+		Description:    "Hotel deposit",
+	})
+	if err != nil {
+		t.Fatalf("PlaceHold failed: %v", err)
+	}
+
+	list, err := accounts.GetUserAccounts(ctx, payer.ID, false)
+	if err != nil {
+		t.Fatalf("GetUserAccounts failed: %v", err)
+	}
+	if list[0].BalanceCents != 10000 || list[0].AvailableBalanceCents != 4000 {
+		t.Errorf("Expected ledger 10000 and available 4000, got %d and %d", list[0].BalanceCents, list[0].AvailableBalanceCents)
+	}
+
+	transfer := dto.TransferRequest{ToUserID: merchant.ID, Currency: "USD", AmountCents: 5000}
+	if _, err := transactions.Transfer(ctx, payer.ID, transfer); err != errorsx.ErrInsufficientFunds {
+		t.Errorf("Expected held funds to be unavailable, got %v", err)
+	}
+
+	if _, err := holds.CaptureHold(ctx, payer.ID, hold.ID, dto.CaptureHoldRequest{}); err == nil {
+		t.Error("Expected the payer to be unable to capture")
+	}
+	captured, err := holds.CaptureHold(ctx, merchant.ID, hold.ID, dto.CaptureHoldRequest{AmountCents: 2500})
+	if err != nil {
+		t.Fatalf("CaptureHold failed: %v", err)
+	}
+	if captured.Status != models.HoldStatusCaptured || captured.CapturedCents != 2500 || captured.TransactionID == nil {
+		t.Errorf("Unexpected captured hold: %+v", captured)
+	}
+	if _, err := holds.CaptureHold(ctx, merchant.ID, hold.ID, dto.CaptureHoldRequest{}); err != errorsx.ErrHoldNotActive {
+		t.Errorf("Expected ErrHoldNotActive on second capture, got %v", err)
+	}
+
+	payerAfter, _ := repos.Account.FindByID(ctx, payerAccount.ID)
+	merchantAfter, _ := repos.Account.FindByID(ctx, merchantAccount.ID)
+	if payerAfter.BalanceCents != 7500 || payerAfter.ReservedCents != 0 || merchantAfter.BalanceCents != 2500 {
+		t.Errorf("Unexpected balances after capture: payer %d (reserved %d), merchant %d",
+			payerAfter.BalanceCents, payerAfter.ReservedCents, merchantAfter.BalanceCents)
+	}
+
+	released, err := holds.PlaceHold(ctx, payer.ID, dto.PlaceHoldRequest{AccountID: payerAccount.ID, PayeeAccountID: merchantAccount.ID, AmountCents: 1000})
+	if err != nil {
+		t.Fatalf("PlaceHold failed: %v", err)
+	}
+	if _, err := holds.ReleaseHold(ctx, payer.ID, released.ID); err == nil || err == errorsx.ErrHoldNotFound {
+		t.Errorf("Expected the payer to be unable to release, got %v", err)
+	}
+	if _, err := holds.ReleaseHold(ctx, merchant.ID, released.ID); err != nil {
+		t.Fatalf("ReleaseHold failed: %v", err)
+	}
+
+	expiresAt := time.Now().UTC().Add(time.Minute)
+	expiring, err := holds.PlaceHold(ctx, payer.ID, dto.PlaceHoldRequest{
+		AccountID:      payerAccount.ID,
+		PayeeAccountID: merchantAccount.ID,
+		AmountCents:    3000,
+		ExpiresAt:      &expiresAt,
+	})
+	if err != nil {
+		t.Fatalf("PlaceHold failed: %v", err)
+	}
+	expired, err := holds.ExpireHolds(ctx, expiresAt.Add(time.Second))
+	if err != nil {
+		t.Fatalf("ExpireHolds failed: %v", err)
+	}
+	if expired != 1 {
+		t.Errorf("Expected 1 expired hold, got %d", expired)
+	}
+	if _, err := holds.ReleaseHold(ctx, merchant.ID, expiring.ID); err != errorsx.ErrHoldNotActive {
+		t.Errorf("Expected ErrHoldNotActive after expiry, got %v", err)
+	}
+
+	payerAfter, _ = repos.Account.FindByID(ctx, payerAccount.ID)
+	if payerAfter.ReservedCents != 0 {
+		t.Errorf("Expected every reservation released, got %d", payerAfter.ReservedCents)
+	}
+}
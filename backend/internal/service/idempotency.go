@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	idempotencyEndpointTransfer = "transfer"
+	idempotencyEndpointExchange = "exchange"
+)
+
+// IdempotencyService makes transfers and exchanges safe to retry: the first
+// request with a key runs, later ones with the same key and body get the
+// original transaction back without posting anything. The key is stored in
+// the same DB transaction as the posting, so a key exists exactly when its
+// money has moved.
+type IdempotencyService struct {
+	idempotencyRepo    *repository.IdempotencyRepository
+	transactionRepo    *repository.TransactionRepository
+	transactionService *TransactionService
+	logger             *slog.Logger
+}
+
+// errIdempotencyKeyTaken rolls a posting back when another request stored
+// the same key first.
+var errIdempotencyKeyTaken = errors.New("idempotency key taken by another request")
+
+func NewIdempotencyService(idempotencyRepo *repository.IdempotencyRepository, transactionRepo *repository.TransactionRepository, transactionService *TransactionService, logger *slog.Logger) *IdempotencyService {
+	return &IdempotencyService{
+		idempotencyRepo:    idempotencyRepo,
+		transactionRepo:    transactionRepo,
+		transactionService: transactionService,
+		logger:             logger,
+	}
+}
+
+// Transfer runs TransactionService.Transfer at most once per key. The bool
+// reports whether the result is a replay.
+func (s *IdempotencyService) Transfer(ctx context.Context, userID, key string, req dto.TransferRequest) (*models.Transaction, bool, error) {
+	return s.run(ctx, userID, key, idempotencyEndpointTransfer, req, func(hook beforeCommit) (*models.Transaction, error) {
+		return s.transactionService.transfer(ctx, userID, req, hook)
+	})
+}
+
+// Exchange runs TransactionService.Exchange at most once per key.
+func (s *IdempotencyService) Exchange(ctx context.Context, userID, key string, req dto.ExchangeRequest) (*models.Transaction, bool, error) {
+	return s.run(ctx, userID, key, idempotencyEndpointExchange, req, func(hook beforeCommit) (*models.Transaction, error) {
+		return s.transactionService.exchange(ctx, userID, req, hook)
+	})
+}
+
+func (s *IdempotencyService) run(ctx context.Context, userID, key, endpoint string, req any, op func(beforeCommit) (*models.Transaction, error)) (*models.Transaction, bool, error) {
+	hash, err := requestHash(endpoint, req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if transaction, err := s.replay(ctx, userID, key, endpoint, hash); transaction != nil || err != nil {
+		return transaction, transaction != nil, err
+	}
+
+	transaction, err := op(func(tx *sqlx.Tx, transaction *models.Transaction) error {
+		stored, err := s.idempotencyRepo.CreateInTx(ctx, tx, &models.IdempotencyKey{
+			UserID:        userID,
+			Key:           key,
+			Endpoint:      endpoint,
+			RequestHash:   hash,
+			TransactionID: transaction.ID,
+		})
+		if err != nil {
+			return err
+		}
+		if !stored {
+			return errIdempotencyKeyTaken
+		}
+		return nil
+	})
+	if errors.Is(err, errIdempotencyKeyTaken) {
+		// A concurrent request with the key committed first; ours was rolled
+		// back, so answer with theirs.
+		transaction, err := s.replay(ctx, userID, key, endpoint, hash)
+		if err == nil && transaction == nil {
+			err = fmt.Errorf("idempotency key %q disappeared after a conflict", key)
+		}
+		return transaction, transaction != nil, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return transaction, false, nil
+}
+
+// replay returns the transaction stored for the key, or nil when the key is
+// unused. A key used for a different request is a mismatch.
+func (s *IdempotencyService) replay(ctx context.Context, userID, key, endpoint, hash string) (*models.Transaction, error) {
+	stored, err := s.idempotencyRepo.Find(ctx, userID, key)
+	if err != nil || stored == nil {
+		return nil, err
+	}
+	if stored.Endpoint != endpoint || stored.RequestHash != hash {
+		return nil, errorsx.ErrIdempotencyMismatch
+	}
+	transaction, err := s.transactionRepo.FindByID(ctx, stored.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("idempotent request replayed", "userID", userID, "endpoint", endpoint, "transactionID", transaction.ID)
+	return transaction, nil
+}
+
+// requestHash fingerprints the bound request, so formatting differences in
+// the raw body do not count as a different request.
+func requestHash(endpoint string, req any) (string, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("error hashing request: %w", err)
+	}
+	sum := sha256.Sum256(append([]byte(endpoint+":"), body...))
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/internal/repository"
+	"os"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func TestIdempotency_ReplaysTransferOnce(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	transactions := newTestTransactionService(repos, logger)
+	idempotency := NewIdempotencyService(repos.Idempotency, repos.Transaction, transactions, logger)
+	ctx := context.Background()
+
+	userA := createTestUser(t, db, "retry-a@test.com")
+	userB := createTestUser(t, db, "retry-b@test.com")
+	accountA := createTestAccount(t, db, userA.ID, "USD", 10000)
+	createTestAccount(t, db, userB.ID, "USD", 0)
+
+	req := dto.TransferRequest{ToUserID: userB.Email, Currency: "USD", AmountCents: 4000}
+	first, replayed, err := idempotency.Transfer(ctx, userA.ID, "key-1", req)
+	if err != nil || replayed {
+		t.Fatalf("First transfer failed: %v (replayed %v)", err, replayed)
+	}
+	second, replayed, err := idempotency.Transfer(ctx, userA.ID, "key-1", req)
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if !replayed || second.ID != first.ID {
+		t.Errorf("Expected replay of %s, got %s (replayed %v)", first.ID, second.ID, replayed)
+	}
+
+	changed := req
+	changed.AmountCents = 5000
+	if _, _, err := idempotency.Transfer(ctx, userA.ID, "key-1", changed); err != errorsx.ErrIdempotencyMismatch {
+		t.Errorf("Expected ErrIdempotencyMismatch for a different body, got %v", err)
+	}
+	exchange := dto.ExchangeRequest{FromCurrency: "USD", ToCurrency: "EUR", AmountCents: 1000}
+	if _, _, err := idempotency.Exchange(ctx, userA.ID, "key-1", exchange); err != errorsx.ErrIdempotencyMismatch {
+		t.Errorf("Expected ErrIdempotencyMismatch for another endpoint, got %v", err)
+	}
+
+	// Keys are per user.
+	if _, replayed, err := idempotency.Transfer(ctx, userB.ID, "key-1", dto.TransferRequest{ToUserID: userA.Email, Currency: "USD", AmountCents: 1000}); err != nil || replayed {
+		t.Errorf("Expected another user's key-1 to run, got %v (replayed %v)", err, replayed)
+	}
+
+	// A failed request frees its key for a retry.
+	big := dto.TransferRequest{ToUserID: userB.Email, Currency: "USD", AmountCents: 20000}
+	if _, _, err := idempotency.Transfer(ctx, userA.ID, "key-2", big); err != errorsx.ErrInsufficientFunds {
+		t.Fatalf("Expected ErrInsufficientFunds, got %v", err)
+	}
+	if _, err := db.Exec("UPDATE accounts SET credit_limit_cents = 20000 WHERE id = $1", accountA.ID); err != nil {
+		t.Fatalf("Failed to raise credit limit: %v", err)
+	}
+	if _, replayed, err := idempotency.Transfer(ctx, userA.ID, "key-2", big); err != nil || replayed {
+		t.Errorf("Expected retry after failure to run, got %v (replayed %v)", err, replayed)
+	}
+
+	var balance int64
+	db.Get(&balance, "SELECT balance_cents FROM accounts WHERE id = $1", accountA.ID)
+	if balance != 10000-4000+1000-20000 {
+		t.Errorf("Expected balance %d, got %d", 10000-4000+1000-20000, balance)
+	}
+}
+
+func TestIdempotency_ConcurrentKeyRollsBackLoser(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	transactions := newTestTransactionService(repos, logger)
+	idempotency := NewIdempotencyService(repos.Idempotency, repos.Transaction, transactions, logger)
+	ctx := context.Background()
+
+	userA := createTestUser(t, db, "race-a@test.com")
+	userB := createTestUser(t, db, "race-b@test.com")
+	accountA := createTestAccount(t, db, userA.ID, "USD", 10000)
+	createTestAccount(t, db, userB.ID, "USD", 0)
+
+	req := dto.TransferRequest{ToUserID: userB.Email, Currency: "USD", AmountCents: 3000}
+	hash, err := requestHash(idempotencyEndpointTransfer, req)
+	if err != nil {
+		t.Fatalf("requestHash failed: %v", err)
+	}
+
+	// The winner stores the key after the loser has checked for it but
+	// before the loser commits.
+	var winner *models.Transaction
+	got, replayed, err := idempotency.run(ctx, userA.ID, "race", idempotencyEndpointTransfer, req, func(hook beforeCommit) (*models.Transaction, error) {
+		winner, err = transactions.transfer(ctx, userA.ID, req, func(tx *sqlx.Tx, transaction *models.Transaction) error {
+			_, err := repos.Idempotency.CreateInTx(ctx, tx, &models.IdempotencyKey{
+				UserID: userA.ID, Key: "race", Endpoint: idempotencyEndpointTransfer, RequestHash: hash, TransactionID: transaction.ID,
+			})
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return transactions.transfer(ctx, userA.ID, req, hook)
+	})
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if !replayed || got.ID != winner.ID {
+		t.Errorf("Expected the winner %s to be replayed, got %s (replayed %v)", winner.ID, got.ID, replayed)
+	}
+
+	var balance int64
+	db.Get(&balance, "SELECT balance_cents FROM accounts WHERE id = $1", accountA.ID)
+	if balance != 7000 {
+		t.Errorf("Expected only the winner to post, got balance %d", balance)
+	}
+}
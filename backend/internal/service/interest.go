@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/internal/repository"
+	"mini-banking-platform/pkg/decimal"
+	"mini-banking-platform/pkg/interest"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// interestAccrualLookbackDays is how many past days each scheduled run
+// re-checks, so a missed run catches up without a manual backfill.
+const interestAccrualLookbackDays = 7
+
+type InterestService struct {
+	interestRepo    *repository.InterestRepository
+	accountRepo     *repository.AccountRepository
+	transactionRepo *repository.TransactionRepository
+	dayCount        interest.DayCount
+	logger          *slog.Logger
+}
+
+func NewInterestService(
+	interestRepo *repository.InterestRepository,
+	accountRepo *repository.AccountRepository,
+	transactionRepo *repository.TransactionRepository,
+	dayCount interest.DayCount,
+	logger *slog.Logger,
+) *InterestService {
+	return &InterestService{
+		interestRepo:    interestRepo,
+		accountRepo:     accountRepo,
+		transactionRepo: transactionRepo,
+		dayCount:        dayCount,
+		logger:          logger,
+	}
+}
+
+func (s *InterestService) SetInterestRate(ctx context.Context, accountID string, req dto.SetInterestRateRequest) (*models.Account, error) {
+	num, denom, err := decimal.ParseRational(req.InterestRate)
+	if err != nil {
+		return nil, errorsx.BadRequest("interest_rate must be a positive decimal number")
+	}
+
+	account, err := s.accountRepo.FindByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account.Type != models.AccountTypeSavings {
+		return nil, errorsx.BadRequest("interest rates apply to savings accounts only")
+	}
+	if account.Status == models.AccountStatusClosed {
+		return nil, errorsx.ErrAccountClosed
+	}
+
+	rate := strings.TrimSpace(req.InterestRate)
+	account.InterestRate = &rate
+	account.InterestRateNum = &num
+	account.InterestRateDenom = &denom
+	if err := s.accountRepo.UpdateInterestRate(ctx, account); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("interest rate changed", "accountID", account.ID, "interestRate", rate)
+	return account, nil
+}
+
+// AccrueDay records one day of interest for every eligible savings account,
+// using the ledger balance at the end of that day. Days already accrued are
+// skipped, so reruns are safe. It returns the number of accruals created.
+func (s *InterestService) AccrueDay(ctx context.Context, day time.Time) (int, error) {
+	day = truncateToDay(day)
+	if !day.Before(truncateToDay(time.Now().UTC())) {
+		return 0, errorsx.BadRequest("only past days can be accrued")
+	}
+
+	candidates, err := s.interestRepo.FindAccrualCandidates(ctx, day)
+	if err != nil {
+		return 0, err
+	}
+
+	daysInYear := s.dayCount.DaysInYear(day)
+	created := 0
+	for _, c := range candidates {
+		accrual := &models.InterestAccrual{
+			AccountID:    c.AccountID,
+			AccrualDate:  day,
+			BalanceCents: c.BalanceCents,
+			RateNum:      c.RateNum,
+			RateDenom:    c.RateDenom,
+			DayCount:     string(s.dayCount),
+			AmountMicros: interest.DailyMicros(c.BalanceCents, c.RateNum, c.RateDenom, daysInYear),
+		}
+		ok, err := s.interestRepo.CreateAccrual(ctx, accrual)
+		if err != nil {
+			return created, err
+		}
+		if ok {
+			created++
+		}
+	}
+
+	s.logger.Info("interest accrued", "day", day.Format(time.DateOnly), "accounts", created)
+	return created, nil
+}
+
+// CapitalizeMonth posts every pending accrual dated up to the end of month to
+// the savings accounts. Whole cents are paid from the interest-expense
+// account; the sub-cent remainder carries over to the next month. It returns
+// the number of accounts credited.
+func (s *InterestService) CapitalizeMonth(ctx context.Context, month time.Time) (int, error) {
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	if end.After(truncateToDay(time.Now().UTC())) {
+		return 0, errorsx.BadRequest("only past months can be capitalized")
+	}
+
+	ids, err := s.interestRepo.FindAccountsWithPendingAccruals(ctx, end)
+	if err != nil {
+		return 0, err
+	}
+
+	credited := 0
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return credited, err
+		}
+		ok, err := s.capitalizeAccount(ctx, id, start, end)
+		if err != nil {
+			if ctx.Err() != nil {
+				return credited, ctx.Err()
+			}
+			s.logger.Error("failed to capitalize interest", "error", err, "accountID", id)
+			continue
+		}
+		if ok {
+			credited++
+		}
+	}
+
+	s.logger.Info("interest capitalized", "month", start.Format("2006-01"), "accounts", credited)
+	return credited, nil
+}
+
+func (s *InterestService) capitalizeAccount(ctx context.Context, accountID string, month, end time.Time) (bool, error) {
+	account, err := s.accountRepo.FindByID(ctx, accountID)
+	if err != nil {
+		return false, err
+	}
+	if checkAccountStatus(account, false) != nil {
+		s.logger.Warn("interest capitalization deferred", "accountID", account.ID, "status", account.Status)
+		return false, nil
+	}
+
+	expenseAccount, err := s.expenseAccount(ctx, account.Currency)
+	if err != nil {
+		return false, err
+	}
+
+	tx, err := s.transactionRepo.BeginTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if err := s.accountRepo.LockAccountsForUpdate(ctx, tx, []string{account.ID, expenseAccount.ID}); err != nil {
+		return false, err
+	}
+	account, err = s.accountRepo.FindByIDForUpdate(ctx, tx, account.ID)
+	if err != nil {
+		return false, err
+	}
+
+	pendingMicros, err := s.interestRepo.SumPendingAccruals(ctx, tx, account.ID, end)
+	if err != nil {
+		return false, err
+	}
+	totalMicros := account.InterestCarryMicros + pendingMicros
+	amountCents := totalMicros / interest.MicrosPerCent
+	carryMicros := totalMicros % interest.MicrosPerCent
+
+	var transactionID *string
+	if amountCents > 0 {
+		transactionID, err = s.postInterest(ctx, tx, account, expenseAccount, amountCents, fmt.Sprintf("Interest for %s", month.Format("2006-01")))
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if err := s.interestRepo.MarkCapitalized(ctx, tx, account.ID, end, transactionID); err != nil {
+		return false, err
+	}
+	if err := s.accountRepo.UpdateInterestCarry(ctx, tx, account.ID, carryMicros); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("error committing interest capitalization: %w", err)
+	}
+
+	s.logger.Info("interest credited", "accountID", account.ID, "amountCents", amountCents, "carryMicros", carryMicros)
+	return amountCents > 0, nil
+}
+
+func (s *InterestService) postInterest(ctx context.Context, tx *sqlx.Tx, account, expenseAccount *models.Account, amountCents int64, description string) (*string, error) {
+	transaction := &models.Transaction{
+		Type:        models.TransactionTypeInterest,
+		FromUserID:  models.InterestSystemUserID,
+		ToUserID:    &account.UserID,
+		Currency:    account.Currency,
+		AmountCents: amountCents,
+		Description: description,
+	}
+	if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
+		return nil, err
+	}
+
+	entries := []models.LedgerEntry{
+		{TransactionID: transaction.ID, AccountID: expenseAccount.ID, Currency: account.Currency, AmountCents: -amountCents},
+		{TransactionID: transaction.ID, AccountID: account.ID, Currency: account.Currency, AmountCents: amountCents},
+	}
+	for i := range entries {
+		if err := s.transactionRepo.CreateLedgerEntry(ctx, tx, &entries[i]); err != nil {
+			return nil, err
+		}
+		if err := s.accountRepo.UpdateBalanceCents(ctx, tx, entries[i].AccountID, entries[i].AmountCents); err != nil {
+			return nil, err
+		}
+	}
+	return &transaction.ID, nil
+}
+
+// closingInterest accrues the finished days a closing savings account has not
+// accrued yet, within the usual lookback, and returns the whole cents it is
+// owed: pending accruals plus the carry. The closing day itself earns nothing
+// because the account ends it empty.
+func (s *InterestService) closingInterest(ctx context.Context, tx *sqlx.Tx, account *models.Account, now time.Time) (int64, error) {
+	today := truncateToDay(now)
+	for i := interestAccrualLookbackDays; i >= 1; i-- {
+		day := today.AddDate(0, 0, -i)
+		c, err := s.interestRepo.FindAccountAccrualCandidate(ctx, tx, account.ID, day)
+		if err != nil {
+			return 0, err
+		}
+		if c == nil {
+			continue
+		}
+		accrual := &models.InterestAccrual{
+			AccountID:    c.AccountID,
+			AccrualDate:  day,
+			BalanceCents: c.BalanceCents,
+			RateNum:      c.RateNum,
+			RateDenom:    c.RateDenom,
+			DayCount:     string(s.dayCount),
+			AmountMicros: interest.DailyMicros(c.BalanceCents, c.RateNum, c.RateDenom, s.dayCount.DaysInYear(day)),
+		}
+		if err := s.interestRepo.CreateAccrualInTx(ctx, tx, accrual); err != nil {
+			return 0, err
+		}
+	}
+
+	pendingMicros, err := s.interestRepo.SumPendingAccruals(ctx, tx, account.ID, today)
+	if err != nil {
+		return 0, err
+	}
+	return (account.InterestCarryMicros + pendingMicros) / interest.MicrosPerCent, nil
+}
+
+// capitalizeForClosure credits everything closingInterest found to the
+// locked account and marks the accruals capitalized. The sub-cent carry is
+// forfeited, as a closed account can never be paid it. It returns the cents
+// credited.
+func (s *InterestService) capitalizeForClosure(ctx context.Context, tx *sqlx.Tx, account, expenseAccount *models.Account, now time.Time) (int64, error) {
+	today := truncateToDay(now)
+	pendingMicros, err := s.interestRepo.SumPendingAccruals(ctx, tx, account.ID, today)
+	if err != nil {
+		return 0, err
+	}
+	amountCents := (account.InterestCarryMicros + pendingMicros) / interest.MicrosPerCent
+
+	var transactionID *string
+	if amountCents > 0 {
+		transactionID, err = s.postInterest(ctx, tx, account, expenseAccount, amountCents, "Interest on closing the account")
+		if err != nil {
+			return 0, err
+		}
+	}
+	if err := s.interestRepo.MarkCapitalized(ctx, tx, account.ID, today, transactionID); err != nil {
+		return 0, err
+	}
+	if err := s.accountRepo.UpdateInterestCarry(ctx, tx, account.ID, 0); err != nil {
+		return 0, err
+	}
+
+	s.logger.Info("interest credited on closing", "accountID", account.ID, "amountCents", amountCents)
+	return amountCents, nil
+}
+
+func (s *InterestService) expenseAccount(ctx context.Context, currency string) (*models.Account, error) {
+	return s.accountRepo.FindOrCreateSystemAccount(ctx, models.InterestSystemUserID, currency, true)
+}
+
+// RunDue accrues the recent past days and capitalizes the previous month.
+// Both steps are idempotent, so it can run as often as the scheduler likes.
+func (s *InterestService) RunDue(ctx context.Context, now time.Time) error {
+	today := truncateToDay(now)
+	for i := interestAccrualLookbackDays; i >= 1; i-- {
+		if _, err := s.AccrueDay(ctx, today.AddDate(0, 0, -i)); err != nil {
+			return err
+		}
+	}
+
+	monthStart := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	_, err := s.CapitalizeMonth(ctx, monthStart.AddDate(0, -1, 0))
+	return err
+}
+
+func truncateToDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// InterestScheduler runs the daily accrual and monthly capitalization in the
+// background. Every run is idempotent, so the interval only bounds how late
+// interest can be recorded.
+type InterestScheduler struct {
+	interest *InterestService
+	interval time.Duration
+	cancel   context.CancelFunc
+	done     chan struct{}
+	logger   *slog.Logger
+}
+
+func NewInterestScheduler(interest *InterestService, interval time.Duration, logger *slog.Logger) *InterestScheduler {
+	return &InterestScheduler{
+		interest: interest,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+func (s *InterestScheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go s.run(ctx)
+	s.logger.Info("interest scheduler started", "interval", s.interval)
+}
+
+// Stop cancels the current run and waits for the loop to exit or for ctx to
+// be done, whichever comes first.
+func (s *InterestScheduler) Stop(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+
+	select {
+	case <-s.done:
+		s.logger.Info("interest scheduler stopped")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *InterestScheduler) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.interest.RunDue(ctx, time.Now().UTC()); err != nil && ctx.Err() == nil {
+			s.logger.Error("interest run failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/internal/repository"
+	"mini-banking-platform/pkg/interest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func createInterestSystemUser(t *testing.T, db *sqlx.DB) {
+	userQuery := `INSERT INTO users (id, email, password, first_name, last_name) VALUES ($1, $2, $3, $4, $5)`
+	_, err := db.Exec(userQuery, models.InterestSystemUserID, models.InterestSystemUserEmail, "N/A", "Interest", "Expense")
+	if err != nil {
+		t.Fatalf("Failed to create interest system user: %v", err)
+	}
+}
+
+func TestInterest_AccrueAndCapitalizeMonth(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	accounts := NewAccountService(repos.Account, repos.Transaction, repos.Currency, logger)
+	transactions := newTestTransactionService(repos, logger)
+	interestService := NewInterestService(repos.Interest, repos.Account, repos.Transaction, interest.Actual365, logger)
+	ctx := context.Background()
+
+	createInterestSystemUser(t, db)
+	user := createTestUser(t, db, "saver@test.com")
+	createTestAccount(t, db, user.ID, "USD", 2000000)
+
+	savings, err := accounts.OpenAccount(ctx, user.ID, dto.OpenAccountRequest{Currency: "USD", Name: "Rainy day", Type: "savings"}, "0.0365")
+	if err != nil {
+		t.Fatalf("OpenAccount failed: %v", err)
+	}
+	if _, err := accounts.OpenAccount(ctx, user.ID, dto.OpenAccountRequest{Currency: "USD", Type: "savings"}, "0.0365"); err == nil {
+		t.Error("Expected an unnamed savings account to be rejected")
+	}
+
+	transfer := dto.TransferRequest{ToAccountID: savings.ID, Currency: "USD", AmountCents: 1000005}
+	if _, err := transactions.Transfer(ctx, user.ID, transfer); err != nil {
+		t.Fatalf("Transfer to savings failed: %v", err)
+	}
+
+	today := time.Now().UTC()
+	month := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	backdated := month.AddDate(0, 0, -1)
+	if _, err := db.Exec(`UPDATE accounts SET created_at = $1 WHERE id = $2`, backdated, savings.ID); err != nil {
+		t.Fatalf("Failed to backdate account: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE ledger_entries SET created_at = $1 WHERE account_id = $2`, backdated, savings.ID); err != nil {
+		t.Fatalf("Failed to backdate ledger entries: %v", err)
+	}
+
+	// 1,000,005 cents at 3.65% act/365 earns 100 cents and 500 micros a day.
+	days := 0
+	for day := month; day.Month() == month.Month(); day = day.AddDate(0, 0, 1) {
+		created, err := interestService.AccrueDay(ctx, day)
+		if err != nil {
+			t.Fatalf("AccrueDay %s failed: %v", day.Format(time.DateOnly), err)
+		}
+		if created != 1 {
+			t.Fatalf("Expected 1 accrual on %s, got %d", day.Format(time.DateOnly), created)
+		}
+		days++
+	}
+	if created, err := interestService.AccrueDay(ctx, month); err != nil || created != 0 {
+		t.Errorf("Expected re-accrual to be a no-op, got %d, %v", created, err)
+	}
+
+	credited, err := interestService.CapitalizeMonth(ctx, month)
+	if err != nil {
+		t.Fatalf("CapitalizeMonth failed: %v", err)
+	}
+	if credited != 1 {
+		t.Errorf("Expected 1 account credited, got %d", credited)
+	}
+	if credited, err := interestService.CapitalizeMonth(ctx, month); err != nil || credited != 0 {
+		t.Errorf("Expected second capitalization to be a no-op, got %d, %v", credited, err)
+	}
+
+	updated, err := repos.Account.FindByID(ctx, savings.ID)
+	if err != nil {
+		t.Fatalf("FindByID failed: %v", err)
+	}
+	wantInterest := int64(days) * 100
+	if updated.BalanceCents != 1000005+wantInterest {
+		t.Errorf("Expected balance %d, got %d", 1000005+wantInterest, updated.BalanceCents)
+	}
+	if updated.InterestCarryMicros != int64(days)*500 {
+		t.Errorf("Expected carry %d micros, got %d", days*500, updated.InterestCarryMicros)
+	}
+
+	expense, err := repos.Account.FindByUserAndCurrency(ctx, models.InterestSystemUserID, "USD")
+	if err != nil {
+		t.Fatalf("Interest expense account not found: %v", err)
+	}
+	if expense.BalanceCents != -wantInterest {
+		t.Errorf("Expected expense balance %d, got %d", -wantInterest, expense.BalanceCents)
+	}
+
+	ledgerSum, err := repos.Transaction.GetLedgerSumCents(ctx, savings.ID)
+	if err != nil {
+		t.Fatalf("GetLedgerSumCents failed: %v", err)
+	}
+	if ledgerSum != updated.BalanceCents {
+		t.Errorf("Ledger sum %d does not match balance %d", ledgerSum, updated.BalanceCents)
+	}
+}
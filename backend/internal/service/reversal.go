@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/models"
+	"strings"
+)
+
+// ReverseTransaction undoes a transfer or exchange with a new reversal
+// transaction whose ledger entries mirror the original ones. A transaction
+// can be refunded in several parts until its whole amount is back; each part
+// takes the same share of every leg, so conversions are refunded
+// proportionally together with their fee and rounding remainder. Returns the
+// reversal and the updated original.
+func (s *TransactionService) ReverseTransaction(ctx context.Context, adminID, transactionID string, req dto.ReverseTransactionRequest) (*models.Transaction, *models.Transaction, error) {
+	tx, err := s.transactionRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	// The row lock serialises concurrent refunds of the same transaction.
+	original, err := s.transactionRepo.FindByIDForUpdate(ctx, tx, transactionID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if original.Type != models.TransactionTypeTransfer && original.Type != models.TransactionTypeExchange {
+		return nil, nil, errorsx.BadRequest("only transfers and exchanges can be reversed")
+	}
+	remainingCents := original.AmountCents - original.RefundedCents
+	if remainingCents <= 0 {
+		return nil, nil, errorsx.ErrAlreadyReversed
+	}
+
+	amountCents := remainingCents
+	if req.AmountCents != 0 {
+		if req.AmountCents > remainingCents {
+			return nil, nil, errorsx.BadRequest(fmt.Sprintf("amount_cents exceeds the %d cents left to refund", remainingCents))
+		}
+		amountCents = req.AmountCents
+	}
+	partial := amountCents < original.AmountCents
+	share := refundShare(original.RefundedCents, original.RefundedCents+amountCents, original.AmountCents)
+
+	entries, err := s.transactionRepo.FindLedgerEntriesByTransaction(ctx, tx, original.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	accountIDs := make([]string, 0, len(entries))
+	debits := make(map[string]int64)
+	for _, entry := range entries {
+		accountIDs = append(accountIDs, entry.AccountID)
+		if entry.AmountCents > 0 {
+			debits[entry.AccountID] += share(entry.AmountCents)
+		}
+	}
+	if err := s.accountRepo.LockAccountsForUpdate(ctx, tx, accountIDs); err != nil {
+		return nil, nil, err
+	}
+	for accountID, debitCents := range debits {
+		availableCents, err := s.accountRepo.GetAvailableCents(ctx, tx, accountID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if availableCents < debitCents {
+			s.logger.Warn("insufficient funds for reversal", "transactionID", original.ID, "accountID", accountID,
+				"available", availableCents, "required", debitCents)
+			return nil, nil, errorsx.ErrInsufficientFunds
+		}
+	}
+
+	// The money flows back, so the original recipient becomes the payer. An
+	// exchange has no recipient and stays with its user.
+	fromUserID := original.FromUserID
+	if original.ToUserID != nil {
+		fromUserID = *original.ToUserID
+	}
+	description := fmt.Sprintf("Reversal of %s", original.ID)
+	if partial {
+		description = fmt.Sprintf("Partial reversal of %s: %d of %d cents", original.ID, amountCents, original.AmountCents)
+	}
+	if reason := strings.TrimSpace(req.Reason); reason != "" {
+		description = fmt.Sprintf("%s (%s)", description, reason)
+	}
+
+	var toAmountCents *int64
+	if original.ToAmountCents != nil {
+		// The target leg is credited gross and the fee taken back out, so
+		// the refunded net amount is the difference of those two shares.
+		netCents := share(*original.ToAmountCents+original.FeeCents) - share(original.FeeCents)
+		toAmountCents = &netCents
+	}
+
+	reversal := &models.Transaction{
+		Type:                  models.TransactionTypeReversal,
+		FromUserID:            fromUserID,
+		ToUserID:              &original.FromUserID,
+		Currency:              original.Currency,
+		AmountCents:           amountCents,
+		ToCurrency:            original.ToCurrency,
+		ToAmountCents:         toAmountCents,
+		Description:           description,
+		FXRateID:              original.FXRateID,
+		FXRateNum:             original.FXRateNum,
+		FXRateDenom:           original.FXRateDenom,
+		FeeCents:              share(original.FeeCents),
+		ReversesTransactionID: &original.ID,
+	}
+	if err := s.transactionRepo.Create(ctx, tx, reversal); err != nil {
+		return nil, nil, err
+	}
+
+	mirrored := make([]*models.LedgerEntry, 0, len(entries))
+	for _, entry := range entries {
+		cents := share(entry.AmountCents)
+		if cents == 0 {
+			continue
+		}
+		mirrored = append(mirrored, &models.LedgerEntry{
+			TransactionID: reversal.ID,
+			AccountID:     entry.AccountID,
+			Currency:      entry.Currency,
+			AmountCents:   -cents,
+		})
+	}
+	if err := s.postLedgerEntries(ctx, tx, mirrored); err != nil {
+		return nil, nil, err
+	}
+
+	if original.ToCurrency != nil {
+		rounding, err := s.roundingRepo.FindEntry(ctx, tx, original.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if rounding != nil {
+			remainder := big.NewRat(-rounding.RemainderNum, rounding.RemainderDenom)
+			remainder.Mul(remainder, big.NewRat(amountCents, original.AmountCents))
+			if !remainder.Num().IsInt64() || !remainder.Denom().IsInt64() {
+				return nil, nil, fmt.Errorf("rounding remainder of %s is out of range for a refund of %d cents", original.ID, amountCents)
+			}
+			if err := s.postRoundingEntry(ctx, tx, &models.RoundingEntry{
+				TransactionID:  reversal.ID,
+				Currency:       rounding.Currency,
+				RoundingMode:   rounding.RoundingMode,
+				RemainderNum:   remainder.Num().Int64(),
+				RemainderDenom: remainder.Denom().Int64(),
+			}); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	if err := s.transactionRepo.AddRefund(ctx, tx, original.ID, reversal.ID, amountCents); err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit reversal", "error", err)
+		return nil, nil, fmt.Errorf("error committing reversal: %w", err)
+	}
+
+	s.logger.Info("transaction reversed", "transactionID", original.ID, "reversalID", reversal.ID,
+		"amountCents", amountCents, "adminID", adminID)
+	original.ReversedByTransactionID = &reversal.ID
+	original.RefundedCents += amountCents
+	return reversal, original, nil
+}
+
+// refundShare returns the part of a ledger amount that a refund covering the
+// original amount from refunded cents up to refundedAfter undoes. Both ends
+// are truncated toward zero from the cumulative total, so the parts of
+// several refunds always add up to the full amount and the two entries of a
+// leg, which have the same size, stay balanced.
+func refundShare(refunded, refundedAfter, totalCents int64) func(cents int64) int64 {
+	scaled := func(cents, upTo int64) int64 {
+		v := new(big.Int).Mul(big.NewInt(cents), big.NewInt(upTo))
+		return v.Quo(v, big.NewInt(totalCents)).Int64()
+	}
+	return func(cents int64) int64 {
+		return scaled(cents, refundedAfter) - scaled(cents, refunded)
+	}
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/internal/repository"
+	"os"
+	"testing"
+)
+
+func TestReverseTransaction_RefundsInParts(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	transactions := newTestTransactionService(repos, logger)
+	fxRates := NewFXRateService(repos.FXRate, repos.FXSpread, repos.Rounding, repos.Currency, repos.Transaction, logger)
+	ctx := context.Background()
+
+	createFXSystemAccounts(t, db)
+
+	admin := createTestUser(t, db, "support@test.com")
+	userA := createTestUser(t, db, "refund-a@test.com")
+	userB := createTestUser(t, db, "refund-b@test.com")
+	accountA := createTestAccount(t, db, userA.ID, "USD", 10000)
+	eurA := createTestAccount(t, db, userA.ID, "EUR", 0)
+	accountB := createTestAccount(t, db, userB.ID, "USD", 0)
+
+	transfer, err := transactions.Transfer(ctx, userA.ID, dto.TransferRequest{ToUserID: userB.Email, Currency: "USD", AmountCents: 3000})
+	if err != nil {
+		t.Fatalf("Transfer failed: %v", err)
+	}
+
+	if _, _, err := transactions.ReverseTransaction(ctx, admin.ID, transfer.ID, dto.ReverseTransactionRequest{AmountCents: 5000, Reason: "too much"}); err == nil {
+		t.Error("Expected a refund above the original amount to fail")
+	}
+
+	reversal, original, err := transactions.ReverseTransaction(ctx, admin.ID, transfer.ID, dto.ReverseTransactionRequest{AmountCents: 1000, Reason: "partial refund"})
+	if err != nil {
+		t.Fatalf("ReverseTransaction failed: %v", err)
+	}
+	if reversal.Type != models.TransactionTypeReversal || reversal.ReversesTransactionID == nil || *reversal.ReversesTransactionID != transfer.ID {
+		t.Errorf("Expected a reversal linked to %s, got %+v", transfer.ID, reversal)
+	}
+	if original.ReversedByTransactionID == nil || *original.ReversedByTransactionID != reversal.ID {
+		t.Errorf("Expected the original to link to %s, got %v", reversal.ID, original.ReversedByTransactionID)
+	}
+	if reversal.FromUserID != userB.ID {
+		t.Errorf("Expected the recipient to pay the refund, got %s", reversal.FromUserID)
+	}
+
+	var balanceA, balanceB int64
+	db.Get(&balanceA, "SELECT balance_cents FROM accounts WHERE id = $1", accountA.ID)
+	db.Get(&balanceB, "SELECT balance_cents FROM accounts WHERE id = $1", accountB.ID)
+	if balanceA != 8000 || balanceB != 2000 {
+		t.Errorf("Expected balances 8000 and 2000, got %d and %d", balanceA, balanceB)
+	}
+
+	if _, _, err := transactions.ReverseTransaction(ctx, admin.ID, transfer.ID, dto.ReverseTransactionRequest{AmountCents: 2500, Reason: "too much"}); err == nil {
+		t.Error("Expected a refund above the amount left to fail")
+	}
+	second, original, err := transactions.ReverseTransaction(ctx, admin.ID, transfer.ID, dto.ReverseTransactionRequest{Reason: "rest"})
+	if err != nil {
+		t.Fatalf("Second refund failed: %v", err)
+	}
+	if second.AmountCents != 2000 || original.RefundedCents != 3000 {
+		t.Errorf("Expected the rest of 2000 cents to be refunded, got %d (total %d)", second.AmountCents, original.RefundedCents)
+	}
+	db.Get(&balanceA, "SELECT balance_cents FROM accounts WHERE id = $1", accountA.ID)
+	db.Get(&balanceB, "SELECT balance_cents FROM accounts WHERE id = $1", accountB.ID)
+	if balanceA != 10000 || balanceB != 0 {
+		t.Errorf("Expected balances 10000 and 0, got %d and %d", balanceA, balanceB)
+	}
+	if _, _, err := transactions.ReverseTransaction(ctx, admin.ID, transfer.ID, dto.ReverseTransactionRequest{Reason: "again"}); err != errorsx.ErrAlreadyReversed {
+		t.Errorf("Expected ErrAlreadyReversed, got %v", err)
+	}
+	if _, _, err := transactions.ReverseTransaction(ctx, admin.ID, reversal.ID, dto.ReverseTransactionRequest{Reason: "undo"}); err == nil {
+		t.Error("Expected reversing a reversal to fail")
+	}
+
+	listed, _, err := transactions.GetTransactions(ctx, userB.ID, "", 1, 10)
+	if err != nil {
+		t.Fatalf("GetTransactions failed: %v", err)
+	}
+	for _, item := range listed {
+		if item.ID == transfer.ID && (item.ReversedByTransactionID == nil || *item.ReversedByTransactionID != second.ID || item.RefundedCents != 3000) {
+			t.Error("Expected the listed transfer to show its latest reversal and refunded total")
+		}
+	}
+
+	exchange, err := transactions.Exchange(ctx, userA.ID, dto.ExchangeRequest{FromCurrency: "USD", ToCurrency: "EUR", AmountCents: 5000})
+	if err != nil {
+		t.Fatalf("Exchange failed: %v", err)
+	}
+	partialExchange, _, err := transactions.ReverseTransaction(ctx, admin.ID, exchange.ID, dto.ReverseTransactionRequest{AmountCents: 1000, Reason: "partial"})
+	if err != nil {
+		t.Fatalf("Partial exchange refund failed: %v", err)
+	}
+	if partialExchange.ToAmountCents == nil || *partialExchange.ToAmountCents <= 0 || *partialExchange.ToAmountCents >= *exchange.ToAmountCents {
+		t.Errorf("Expected a proportional share of %d EUR cents, got %v", *exchange.ToAmountCents, partialExchange.ToAmountCents)
+	}
+
+	var eurBalance int64
+	db.Get(&balanceA, "SELECT balance_cents FROM accounts WHERE id = $1", accountA.ID)
+	db.Get(&eurBalance, "SELECT balance_cents FROM accounts WHERE id = $1", eurA.ID)
+	if balanceA != 6000 || eurBalance != *exchange.ToAmountCents-*partialExchange.ToAmountCents {
+		t.Errorf("Expected 6000 USD and %d EUR after the partial refund, got %d and %d",
+			*exchange.ToAmountCents-*partialExchange.ToAmountCents, balanceA, eurBalance)
+	}
+
+	if _, _, err := transactions.ReverseTransaction(ctx, admin.ID, exchange.ID, dto.ReverseTransactionRequest{Reason: "wrong currency"}); err != nil {
+		t.Fatalf("Exchange reversal failed: %v", err)
+	}
+
+	db.Get(&balanceA, "SELECT balance_cents FROM accounts WHERE id = $1", accountA.ID)
+	db.Get(&eurBalance, "SELECT balance_cents FROM accounts WHERE id = $1", eurA.ID)
+	if balanceA != 10000 || eurBalance != 0 {
+		t.Errorf("Expected the exchange to be fully undone, got %d USD and %d EUR", balanceA, eurBalance)
+	}
+
+	var unsettledAccounts int
+	db.Get(&unsettledAccounts, `
+		SELECT COUNT(*) FROM (
+			SELECT account_id FROM ledger_entries
+			WHERE transaction_id = $1 OR transaction_id IN (SELECT id FROM transactions WHERE reverses_transaction_id = $1)
+			GROUP BY account_id
+			HAVING SUM(amount_cents) <> 0
+		) leftover`, exchange.ID)
+	if unsettledAccounts != 0 {
+		t.Errorf("Expected the exchange and its refunds to net to zero on every account, got %d accounts off", unsettledAccounts)
+	}
+
+	report, err := fxRates.GetRoundingReport(ctx)
+	if err != nil {
+		t.Fatalf("GetRoundingReport failed: %v", err)
+	}
+	for _, line := range report {
+		if !line.IsBalanced {
+			t.Errorf("Expected rounding suspense to stay balanced, got %+v", line)
+		}
+	}
+
+	results, err := NewAccountService(repos.Account, repos.Transaction, repos.Currency, logger).ReconcileBalances(ctx, userA.ID)
+	if err != nil {
+		t.Fatalf("ReconcileBalances failed: %v", err)
+	}
+	for _, result := range results {
+		if !result.IsBalanced {
+			t.Errorf("Expected account %s to reconcile, got %+v", result.AccountID, result)
+		}
+	}
+}
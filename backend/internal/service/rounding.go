@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"mini-banking-platform/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// recordRounding stores the exact remainder of an exchange and adds it to the
+// rounding suspense balance of the target currency.
+func (s *TransactionService) recordRounding(ctx context.Context, tx *sqlx.Tx, transactionID string, pricing *exchangePricing) error {
+	return s.postRoundingEntry(ctx, tx, &models.RoundingEntry{
+		TransactionID:  transactionID,
+		Currency:       pricing.ToCurrency,
+		RoundingMode:   string(pricing.RoundingMode),
+		RemainderNum:   pricing.Remainder.Num().Int64(),
+		RemainderDenom: pricing.Remainder.Denom().Int64(),
+	})
+}
+
+func (s *TransactionService) postRoundingEntry(ctx context.Context, tx *sqlx.Tx, entry *models.RoundingEntry) error {
+	if err := s.roundingRepo.CreateEntry(ctx, tx, entry); err != nil {
+		return err
+	}
+
+	suspense, err := s.roundingRepo.FindSuspenseForUpdate(ctx, tx, entry.Currency)
+	if err != nil {
+		return err
+	}
+	balance, err := suspenseBalance(suspense)
+	if err != nil {
+		return err
+	}
+	balance.Add(balance, big.NewRat(entry.RemainderNum, entry.RemainderDenom))
+
+	suspense.RemainderNum = balance.Num().String()
+	suspense.RemainderDenom = balance.Denom().String()
+	suspense.ExchangeCount++
+	return s.roundingRepo.UpdateSuspense(ctx, tx, suspense)
+}
+
+func suspenseBalance(suspense *models.RoundingSuspense) (*big.Rat, error) {
+	balance, ok := new(big.Rat).SetString(suspense.RemainderNum + "/" + suspense.RemainderDenom)
+	if !ok {
+		return nil, fmt.Errorf("invalid rounding suspense balance for %s: %s/%s", suspense.Currency, suspense.RemainderNum, suspense.RemainderDenom)
+	}
+	return balance, nil
+}
+
+// RoundingReportLine compares the suspense balance of a currency with the sum
+// of the per-exchange remainders. Amounts are fractions of a minor unit.
+type RoundingReportLine struct {
+	Currency        string `json:"currency"`
+	ExchangeCount   int64  `json:"exchange_count"`
+	EntryCount      int64  `json:"entry_count"`
+	SuspenseCents   string `json:"suspense_cents"`
+	SuspenseDecimal string `json:"suspense_decimal"`
+	EntriesCents    string `json:"entries_cents"`
+	IsBalanced      bool   `json:"is_balanced"`
+}
+
+func (s *FXRateService) GetRoundingReport(ctx context.Context) ([]RoundingReportLine, error) {
+	suspense, err := s.roundingRepo.FindAllSuspense(ctx)
+	if err != nil {
+		s.logger.Error("failed to get rounding suspense", "error", err)
+		return nil, fmt.Errorf("error getting rounding suspense: %w", err)
+	}
+
+	sums, err := s.roundingRepo.SumEntries(ctx)
+	if err != nil {
+		s.logger.Error("failed to sum rounding entries", "error", err)
+		return nil, fmt.Errorf("error summing rounding entries: %w", err)
+	}
+
+	entryTotals := make(map[string]*big.Rat)
+	entryCounts := make(map[string]int64)
+	for _, sum := range sums {
+		part, ok := new(big.Rat).SetString(fmt.Sprintf("%s/%d", sum.RemainderNum, sum.RemainderDenom))
+		if !ok {
+			return nil, fmt.Errorf("invalid rounding entry sum for %s: %s/%d", sum.Currency, sum.RemainderNum, sum.RemainderDenom)
+		}
+		if entryTotals[sum.Currency] == nil {
+			entryTotals[sum.Currency] = new(big.Rat)
+		}
+		entryTotals[sum.Currency].Add(entryTotals[sum.Currency], part)
+		entryCounts[sum.Currency] += sum.EntryCount
+	}
+
+	lines := make([]RoundingReportLine, 0, len(suspense))
+	for i := range suspense {
+		balance, err := suspenseBalance(&suspense[i])
+		if err != nil {
+			return nil, err
+		}
+		entries := entryTotals[suspense[i].Currency]
+		if entries == nil {
+			entries = new(big.Rat)
+		}
+		delete(entryTotals, suspense[i].Currency)
+
+		line := RoundingReportLine{
+			Currency:        suspense[i].Currency,
+			ExchangeCount:   suspense[i].ExchangeCount,
+			EntryCount:      entryCounts[suspense[i].Currency],
+			SuspenseCents:   balance.RatString(),
+			SuspenseDecimal: balance.FloatString(6),
+			EntriesCents:    entries.RatString(),
+			IsBalanced:      balance.Cmp(entries) == 0 && suspense[i].ExchangeCount == entryCounts[suspense[i].Currency],
+		}
+		if !line.IsBalanced {
+			s.logger.Warn("rounding suspense mismatch detected", "currency", line.Currency,
+				"suspenseCents", line.SuspenseCents, "entriesCents", line.EntriesCents)
+		}
+		lines = append(lines, line)
+	}
+
+	for currency, entries := range entryTotals {
+		s.logger.Warn("rounding entries without suspense balance", "currency", currency, "entriesCents", entries.RatString())
+		lines = append(lines, RoundingReportLine{
+			Currency:        currency,
+			EntryCount:      entryCounts[currency],
+			SuspenseCents:   "0",
+			SuspenseDecimal: "0.000000",
+			EntriesCents:    entries.RatString(),
+		})
+	}
+
+	return lines, nil
+}
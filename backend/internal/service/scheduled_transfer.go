@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/internal/repository"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const maxScheduleAhead = 366 * 24 * time.Hour
+
+// stuckClaimAfter is how long a transfer may sit in processing before it is
+// taken to belong to a worker that died. A run takes milliseconds.
+const stuckClaimAfter = 5 * time.Minute
+
+// errScheduleClaimLost rolls a posting back when recovery settled the
+// schedule while its transfer was still running.
+var errScheduleClaimLost = errors.New("scheduled transfer is no longer processing")
+
+// ScheduledTransferService keeps future-dated transfers until they are due
+// and then runs them through TransactionService.Transfer, so they get the
+// same checks as a transfer made at that moment.
+type ScheduledTransferService struct {
+	scheduledRepo      *repository.ScheduledTransferRepository
+	transactionService *TransactionService
+	logger             *slog.Logger
+}
+
+func NewScheduledTransferService(scheduledRepo *repository.ScheduledTransferRepository, transactionService *TransactionService, logger *slog.Logger) *ScheduledTransferService {
+	return &ScheduledTransferService{
+		scheduledRepo:      scheduledRepo,
+		transactionService: transactionService,
+		logger:             logger,
+	}
+}
+
+// Schedule stores a transfer to run at execute_at. Source and recipient are
+// checked now so obvious mistakes fail up front; funds are only checked when
+// the transfer runs.
+func (s *ScheduledTransferService) Schedule(ctx context.Context, userID string, req dto.ScheduleTransferRequest) (*models.ScheduledTransfer, error) {
+	now := time.Now().UTC()
+	executeAt := req.ExecuteAt.UTC()
+	if !executeAt.After(now) {
+		return nil, errorsx.BadRequest("execute_at must be in the future")
+	}
+	if executeAt.After(now.Add(maxScheduleAhead)) {
+		return nil, errorsx.BadRequest("execute_at must be within a year")
+	}
+
+	fromAccount, err := s.transactionService.transferSourceAccount(ctx, userID, req.TransferRequest)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := s.transactionService.transferTargetAccount(ctx, userID, fromAccount, req.TransferRequest); err != nil {
+		return nil, err
+	}
+
+	transfer := &models.ScheduledTransfer{
+		UserID:          userID,
+		ToUserID:        req.ToUserID,
+		ToAccountNumber: req.ToAccountNumber,
+		Currency:        req.Currency,
+		ToCurrency:      req.ToCurrency,
+		AmountCents:     req.AmountCents,
+		ExecuteAt:       executeAt,
+		Status:          models.ScheduledTransferStatusScheduled,
+	}
+	if req.ToAccountID != "" {
+		transfer.ToAccountID = &req.ToAccountID
+	}
+	if req.FromAccountID != "" {
+		transfer.FromAccountID = &req.FromAccountID
+	}
+	if err := s.scheduledRepo.Create(ctx, transfer); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("transfer scheduled", "scheduleID", transfer.ID, "userID", userID, "executeAt", executeAt)
+	return transfer, nil
+}
+
+func (s *ScheduledTransferService) GetScheduledTransfers(ctx context.Context, userID, status string) ([]models.ScheduledTransfer, error) {
+	transfers, err := s.scheduledRepo.FindByUser(ctx, userID, status)
+	if err != nil {
+		s.logger.Error("failed to get scheduled transfers", "error", err, "userID", userID)
+		return nil, fmt.Errorf("error getting scheduled transfers: %w", err)
+	}
+	return transfers, nil
+}
+
+// Cancel stops a transfer that has not started running yet.
+func (s *ScheduledTransferService) Cancel(ctx context.Context, userID, scheduleID string) (*models.ScheduledTransfer, error) {
+	transfer, err := s.scheduledRepo.FindByID(ctx, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer.UserID != userID {
+		return nil, errorsx.ErrScheduleNotFound
+	}
+
+	cancelled, err := s.scheduledRepo.Cancel(ctx, scheduleID, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("scheduled transfer cancelled", "scheduleID", scheduleID, "userID", userID)
+	return cancelled, nil
+}
+
+// ExecuteDue runs every scheduled transfer due at now. It returns the number
+// of transfers attempted; a failed transfer counts and keeps its reason.
+func (s *ScheduledTransferService) ExecuteDue(ctx context.Context, now time.Time) (int, error) {
+	ids, err := s.scheduledRepo.FindDueIDs(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	attempted := 0
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return attempted, err
+		}
+		ok, err := s.execute(ctx, id, now)
+		if err != nil {
+			if ctx.Err() != nil {
+				return attempted, ctx.Err()
+			}
+			s.logger.Error("failed to run scheduled transfer", "error", err, "scheduleID", id)
+			continue
+		}
+		if ok {
+			attempted++
+		}
+	}
+
+	return attempted, nil
+}
+
+func (s *ScheduledTransferService) execute(ctx context.Context, scheduleID string, now time.Time) (bool, error) {
+	transfer, ok, err := s.scheduledRepo.Claim(ctx, scheduleID, now)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	// Once claimed the outcome has to be written even during shutdown, or
+	// the transfer would stay in processing.
+	settleCtx := context.WithoutCancel(ctx)
+	transaction, err := s.transactionService.transfer(ctx, transfer.UserID, transferRequest(transfer), s.linkClaim(ctx, transfer.ID))
+	if errors.Is(err, errScheduleClaimLost) {
+		return false, err
+	}
+	if err != nil && ctx.Err() != nil {
+		if requeueErr := s.scheduledRepo.Requeue(settleCtx, transfer.ID); requeueErr != nil {
+			s.logger.Error("failed to requeue scheduled transfer", "error", requeueErr, "scheduleID", transfer.ID)
+		}
+		return false, ctx.Err()
+	}
+
+	executedAt := time.Now().UTC()
+	transfer.ExecutedAt = &executedAt
+	if err != nil {
+		transfer.Status = models.ScheduledTransferStatusFailed
+		transfer.FailureReason = err.Error()
+		s.logger.Warn("scheduled transfer failed", "scheduleID", transfer.ID, "userID", transfer.UserID, "error", err)
+	} else {
+		transfer.Status = models.ScheduledTransferStatusExecuted
+		transfer.TransactionID = &transaction.ID
+		s.logger.Info("scheduled transfer executed", "scheduleID", transfer.ID, "transactionID", transaction.ID)
+	}
+	if err := s.scheduledRepo.Finish(settleCtx, transfer); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// linkClaim records the schedule on the transaction its run posts, in the
+// same commit, so RecoverStuck can tell whether a run that never finished
+// moved the money.
+func (s *ScheduledTransferService) linkClaim(ctx context.Context, scheduleID string) beforeCommit {
+	return func(tx *sqlx.Tx, transaction *models.Transaction) error {
+		ok, err := s.scheduledRepo.LinkTransactionInTx(ctx, tx, scheduleID, transaction.ID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errScheduleClaimLost
+		}
+		return nil
+	}
+}
+
+// RecoverStuck settles transfers left in processing by a worker that died
+// between claiming and finishing them. A transfer whose run was posted is
+// marked executed with that transaction; one that never posted is marked
+// failed rather than run late. Returns the number settled.
+func (s *ScheduledTransferService) RecoverStuck(ctx context.Context, claimedBefore time.Time) (int, error) {
+	ids, err := s.scheduledRepo.FindStuckIDs(ctx, claimedBefore)
+	if err != nil {
+		return 0, err
+	}
+
+	recovered := 0
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return recovered, err
+		}
+		ok, err := s.recover(ctx, id, claimedBefore)
+		if err != nil {
+			s.logger.Error("failed to recover scheduled transfer", "error", err, "scheduleID", id)
+			continue
+		}
+		if ok {
+			recovered++
+		}
+	}
+
+	return recovered, nil
+}
+
+func (s *ScheduledTransferService) recover(ctx context.Context, scheduleID string, claimedBefore time.Time) (bool, error) {
+	tx, err := s.transactionService.transactionRepo.BeginTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// A run still posting holds this lock until it commits, so the lookup
+	// below sees its transaction.
+	transfer, ok, err := s.scheduledRepo.FindStuckForUpdate(ctx, tx, scheduleID, claimedBefore)
+	if err != nil || !ok {
+		return false, err
+	}
+	transaction, err := s.scheduledRepo.FindPostedTransaction(ctx, tx, scheduleID)
+	if err != nil {
+		return false, err
+	}
+
+	if transaction != nil {
+		transfer.Status = models.ScheduledTransferStatusExecuted
+		transfer.TransactionID = &transaction.ID
+		transfer.ExecutedAt = &transaction.CreatedAt
+	} else {
+		executedAt := time.Now().UTC()
+		transfer.Status = models.ScheduledTransferStatusFailed
+		transfer.FailureReason = "interrupted before the transfer was posted"
+		transfer.ExecutedAt = &executedAt
+	}
+	if err := s.scheduledRepo.FinishInTx(ctx, tx, transfer); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("error committing scheduled transfer recovery: %w", err)
+	}
+
+	s.logger.Warn("recovered stuck scheduled transfer", "scheduleID", transfer.ID, "status", transfer.Status,
+		"transactionID", transfer.TransactionID)
+	return true, nil
+}
+
+func transferRequest(transfer *models.ScheduledTransfer) dto.TransferRequest {
+	req := dto.TransferRequest{
+		ToUserID:        transfer.ToUserID,
+		ToAccountNumber: transfer.ToAccountNumber,
+		Currency:        transfer.Currency,
+		ToCurrency:      transfer.ToCurrency,
+		AmountCents:     transfer.AmountCents,
+	}
+	if transfer.ToAccountID != nil {
+		req.ToAccountID = *transfer.ToAccountID
+	}
+	if transfer.FromAccountID != nil {
+		req.FromAccountID = *transfer.FromAccountID
+	}
+	return req
+}
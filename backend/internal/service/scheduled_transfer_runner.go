@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// ScheduledTransferRunner executes due scheduled transfers in the background.
+type ScheduledTransferRunner struct {
+	transfers *ScheduledTransferService
+	interval  time.Duration
+	cancel    context.CancelFunc
+	done      chan struct{}
+	logger    *slog.Logger
+}
+
+func NewScheduledTransferRunner(transfers *ScheduledTransferService, interval time.Duration, logger *slog.Logger) *ScheduledTransferRunner {
+	return &ScheduledTransferRunner{
+		transfers: transfers,
+		interval:  interval,
+		logger:    logger,
+	}
+}
+
+func (r *ScheduledTransferRunner) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go r.run(ctx)
+	r.logger.Info("scheduled transfer runner started", "interval", r.interval)
+}
+
+// Stop cancels the current run and waits for the loop to exit or for ctx to
+// be done, whichever comes first.
+func (r *ScheduledTransferRunner) Stop(ctx context.Context) error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+
+	select {
+	case <-r.done:
+		r.logger.Info("scheduled transfer runner stopped")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *ScheduledTransferRunner) run(ctx context.Context) {
+	defer close(r.done)
+
+	// Claims left behind by a crashed process are settled at startup and
+	// then on every run, in case another instance dies meanwhile.
+	r.recoverStuck(ctx)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		r.recoverStuck(ctx)
+		attempted, err := r.transfers.ExecuteDue(ctx, time.Now().UTC())
+		if err != nil && ctx.Err() == nil {
+			r.logger.Error("scheduled transfer run failed", "error", err)
+			continue
+		}
+		if attempted > 0 {
+			r.logger.Info("scheduled transfers run", "count", attempted)
+		}
+	}
+}
+
+func (r *ScheduledTransferRunner) recoverStuck(ctx context.Context) {
+	recovered, err := r.transfers.RecoverStuck(ctx, time.Now().UTC().Add(-stuckClaimAfter))
+	if err != nil && ctx.Err() == nil {
+		r.logger.Error("scheduled transfer recovery failed", "error", err)
+		return
+	}
+	if recovered > 0 {
+		r.logger.Warn("stuck scheduled transfers recovered", "count", recovered)
+	}
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/internal/repository"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestScheduledTransfers_ExecuteCancelAndFail(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	scheduled := NewScheduledTransferService(repos.Scheduled, newTestTransactionService(repos, logger), logger)
+	ctx := context.Background()
+
+	userA := createTestUser(t, db, "scheduler-a@test.com")
+	userB := createTestUser(t, db, "scheduler-b@test.com")
+	accountA := createTestAccount(t, db, userA.ID, "USD", 10000)
+	accountB := createTestAccount(t, db, userB.ID, "USD", 0)
+
+	tomorrow := time.Now().UTC().Add(24 * time.Hour)
+	schedule := func(amountCents int64, at time.Time) (*models.ScheduledTransfer, error) {
+		return scheduled.Schedule(ctx, userA.ID, dto.ScheduleTransferRequest{
+			TransferRequest: dto.TransferRequest{ToUserID: userB.Email, Currency: "USD", AmountCents: amountCents},
+			ExecuteAt:       at,
+		})
+	}
+
+	if _, err := schedule(1000, time.Now().UTC().Add(-time.Minute)); err == nil {
+		t.Error("Expected a past execution date to be rejected")
+	}
+	if _, err := scheduled.Schedule(ctx, userA.ID, dto.ScheduleTransferRequest{
+		TransferRequest: dto.TransferRequest{ToUserID: "nobody@test.com", Currency: "USD", AmountCents: 1000},
+		ExecuteAt:       tomorrow,
+	}); err != errorsx.ErrUserNotFound {
+		t.Errorf("Expected ErrUserNotFound for an unknown recipient, got %v", err)
+	}
+
+	paid, err := schedule(4000, tomorrow)
+	if err != nil {
+		t.Fatalf("Schedule failed: %v", err)
+	}
+	cancelled, err := schedule(1000, tomorrow)
+	if err != nil {
+		t.Fatalf("Schedule failed: %v", err)
+	}
+	tooBig, err := schedule(9000, tomorrow.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Schedule failed: %v", err)
+	}
+
+	if _, err := scheduled.Cancel(ctx, userB.ID, cancelled.ID); err != errorsx.ErrScheduleNotFound {
+		t.Errorf("Expected ErrScheduleNotFound for another user, got %v", err)
+	}
+	if _, err := scheduled.Cancel(ctx, userA.ID, cancelled.ID); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+
+	if attempted, err := scheduled.ExecuteDue(ctx, time.Now().UTC()); err != nil || attempted != 0 {
+		t.Errorf("Expected nothing due yet, got %d, %v", attempted, err)
+	}
+	attempted, err := scheduled.ExecuteDue(ctx, tomorrow.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("ExecuteDue failed: %v", err)
+	}
+	if attempted != 2 {
+		t.Errorf("Expected 2 transfers attempted, got %d", attempted)
+	}
+
+	transfers, err := scheduled.GetScheduledTransfers(ctx, userA.ID, "")
+	if err != nil {
+		t.Fatalf("GetScheduledTransfers failed: %v", err)
+	}
+	statuses := make(map[string]models.ScheduledTransfer)
+	for _, transfer := range transfers {
+		statuses[transfer.ID] = transfer
+	}
+	if got := statuses[paid.ID]; got.Status != models.ScheduledTransferStatusExecuted || got.TransactionID == nil {
+		t.Errorf("Expected the first transfer to be executed, got %+v", got)
+	}
+	if got := statuses[cancelled.ID]; got.Status != models.ScheduledTransferStatusCancelled {
+		t.Errorf("Expected the cancelled transfer to stay cancelled, got %s", got.Status)
+	}
+	if got := statuses[tooBig.ID]; got.Status != models.ScheduledTransferStatusFailed || got.FailureReason != errorsx.ErrInsufficientFunds.Error() {
+		t.Errorf("Expected the large transfer to fail for insufficient funds, got %+v", got)
+	}
+	if _, err := scheduled.Cancel(ctx, userA.ID, paid.ID); err != errorsx.ErrScheduleNotPending {
+		t.Errorf("Expected ErrScheduleNotPending after execution, got %v", err)
+	}
+
+	var balanceA, balanceB int64
+	db.Get(&balanceA, "SELECT balance_cents FROM accounts WHERE id = $1", accountA.ID)
+	db.Get(&balanceB, "SELECT balance_cents FROM accounts WHERE id = $1", accountB.ID)
+	if balanceA != 6000 || balanceB != 4000 {
+		t.Errorf("Expected balances 6000 and 4000, got %d and %d", balanceA, balanceB)
+	}
+}
+
+func TestScheduledTransfers_RecoversStuckClaims(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	scheduled := NewScheduledTransferService(repos.Scheduled, newTestTransactionService(repos, logger), logger)
+	ctx := context.Background()
+
+	userA := createTestUser(t, db, "stuck-a@test.com")
+	userB := createTestUser(t, db, "stuck-b@test.com")
+	accountA := createTestAccount(t, db, userA.ID, "USD", 10000)
+	accountB := createTestAccount(t, db, userB.ID, "USD", 0)
+
+	tomorrow := time.Now().UTC().Add(24 * time.Hour)
+	schedule := func(amountCents int64) *models.ScheduledTransfer {
+		transfer, err := scheduled.Schedule(ctx, userA.ID, dto.ScheduleTransferRequest{
+			TransferRequest: dto.TransferRequest{ToUserID: userB.Email, Currency: "USD", AmountCents: amountCents},
+			ExecuteAt:       tomorrow,
+		})
+		if err != nil {
+			t.Fatalf("Schedule failed: %v", err)
+		}
+		return transfer
+	}
+	posted := schedule(3000)
+	notPosted := schedule(1000)
+
+	// Both workers die after claiming; the first one after its transfer
+	// committed, the second one before posting anything.
+	due := tomorrow.Add(time.Hour)
+	claimed, ok, err := repos.Scheduled.Claim(ctx, posted.ID, due)
+	if err != nil || !ok {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	transaction, err := scheduled.transactionService.transfer(ctx, userA.ID, transferRequest(claimed), scheduled.linkClaim(ctx, claimed.ID))
+	if err != nil {
+		t.Fatalf("transfer failed: %v", err)
+	}
+	if _, ok, err := repos.Scheduled.Claim(ctx, notPosted.ID, due); err != nil || !ok {
+		t.Fatalf("Claim failed: %v", err)
+	}
+
+	if recovered, err := scheduled.RecoverStuck(ctx, time.Now().UTC().Add(-time.Hour)); err != nil || recovered != 0 {
+		t.Errorf("Expected fresh claims to be left alone, got %d, %v", recovered, err)
+	}
+	recovered, err := scheduled.RecoverStuck(ctx, time.Now().UTC().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("RecoverStuck failed: %v", err)
+	}
+	if recovered != 2 {
+		t.Errorf("Expected 2 transfers recovered, got %d", recovered)
+	}
+
+	got, err := repos.Scheduled.FindByID(ctx, posted.ID)
+	if err != nil {
+		t.Fatalf("FindByID failed: %v", err)
+	}
+	if got.Status != models.ScheduledTransferStatusExecuted || got.TransactionID == nil || *got.TransactionID != transaction.ID {
+		t.Errorf("Expected the posted transfer to be executed with %s, got %+v", transaction.ID, got)
+	}
+	got, err = repos.Scheduled.FindByID(ctx, notPosted.ID)
+	if err != nil {
+		t.Fatalf("FindByID failed: %v", err)
+	}
+	if got.Status != models.ScheduledTransferStatusFailed || got.FailureReason == "" {
+		t.Errorf("Expected the unposted transfer to be failed, got %+v", got)
+	}
+
+	// A run that is still going when recovery settles its schedule is
+	// rolled back instead of moving money for a failed transfer.
+	if _, err := scheduled.transactionService.transfer(ctx, userA.ID, transferRequest(got), scheduled.linkClaim(ctx, got.ID)); err != errScheduleClaimLost {
+		t.Errorf("Expected errScheduleClaimLost, got %v", err)
+	}
+	if attempted, err := scheduled.ExecuteDue(ctx, due); err != nil || attempted != 0 {
+		t.Errorf("Expected recovered transfers not to run again, got %d, %v", attempted, err)
+	}
+
+	var balanceA, balanceB int64
+	db.Get(&balanceA, "SELECT balance_cents FROM accounts WHERE id = $1", accountA.ID)
+	db.Get(&balanceB, "SELECT balance_cents FROM accounts WHERE id = $1", accountB.ID)
+	if balanceA != 7000 || balanceB != 3000 {
+		t.Errorf("Expected balances 7000 and 3000, got %d and %d", balanceA, balanceB)
+	}
+}
@@ -0,0 +1,421 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/internal/repository"
+	"mini-banking-platform/pkg/recurrence"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const standingOrderDateLayout = "2006-01-02"
+
+// errStandingOrderRunLost rolls a posting back when recovery settled the run
+// while its transfer was still running.
+var errStandingOrderRunLost = errors.New("standing order run is no longer processing")
+
+// StandingOrderService keeps repeating payments between two accounts. Each
+// run goes through TransactionService.Transfer and is recorded, successful or
+// not, in the order's history.
+type StandingOrderService struct {
+	standingRepo       *repository.StandingOrderRepository
+	transactionRepo    *repository.TransactionRepository
+	transactionService *TransactionService
+	logger             *slog.Logger
+}
+
+func NewStandingOrderService(
+	standingRepo *repository.StandingOrderRepository,
+	transactionRepo *repository.TransactionRepository,
+	transactionService *TransactionService,
+	logger *slog.Logger,
+) *StandingOrderService {
+	return &StandingOrderService{
+		standingRepo:       standingRepo,
+		transactionRepo:    transactionRepo,
+		transactionService: transactionService,
+		logger:             logger,
+	}
+}
+
+// CreateStandingOrder resolves both accounts up front, so later runs always
+// pay the same account even if the recipient opens or closes others.
+func (s *StandingOrderService) CreateStandingOrder(ctx context.Context, userID string, req dto.CreateStandingOrderRequest) (*models.StandingOrder, error) {
+	today := recurrence.Day(time.Now())
+	startOn, err := time.Parse(standingOrderDateLayout, req.StartOn)
+	if err != nil {
+		return nil, errorsx.BadRequest("start_on must be a date (YYYY-MM-DD)")
+	}
+	if startOn.Before(today) {
+		return nil, errorsx.BadRequest("start_on must not be in the past")
+	}
+
+	order := &models.StandingOrder{
+		UserID:       userID,
+		AmountCents:  req.AmountCents,
+		Description:  strings.TrimSpace(req.Description),
+		Frequency:    req.Frequency,
+		MonthEndRule: req.MonthEndRule,
+		StartOn:      startOn,
+		Status:       models.StandingOrderStatusActive,
+	}
+	if order.MonthEndRule == "" {
+		order.MonthEndRule = string(recurrence.LastDay)
+	}
+	if req.EndOn != "" {
+		endOn, err := time.Parse(standingOrderDateLayout, req.EndOn)
+		if err != nil {
+			return nil, errorsx.BadRequest("end_on must be a date (YYYY-MM-DD)")
+		}
+		if endOn.Before(startOn) {
+			return nil, errorsx.BadRequest("end_on must not be before start_on")
+		}
+		order.EndOn = &endOn
+	}
+	switch {
+	case req.Frequency == string(recurrence.Monthly) && req.DayOfMonth == 0:
+		day := startOn.Day()
+		order.DayOfMonth = &day
+	case req.Frequency == string(recurrence.Monthly):
+		order.DayOfMonth = &req.DayOfMonth
+	case req.DayOfMonth != 0:
+		return nil, errorsx.BadRequest("day_of_month only applies to monthly orders")
+	}
+
+	rule := standingOrderRule(order)
+	if err := rule.Validate(); err != nil {
+		return nil, errorsx.BadRequest(err.Error())
+	}
+	first := rule.First()
+	if order.EndOn != nil && first.After(*order.EndOn) {
+		return nil, errorsx.BadRequest("no run falls between start_on and end_on")
+	}
+	order.NextRunOn = &first
+
+	transfer := dto.TransferRequest{
+		FromAccountID:   req.FromAccountID,
+		ToUserID:        req.ToUserID,
+		ToAccountID:     req.ToAccountID,
+		ToAccountNumber: req.ToAccountNumber,
+		AmountCents:     req.AmountCents,
+	}
+	fromAccount, err := s.transactionService.transferSourceAccount(ctx, userID, transfer)
+	if err != nil {
+		return nil, err
+	}
+	toAccount, _, err := s.transactionService.transferTargetAccount(ctx, userID, fromAccount, transfer)
+	if err != nil {
+		return nil, err
+	}
+	order.FromAccountID = fromAccount.ID
+	order.ToAccountID = toAccount.ID
+
+	if err := s.standingRepo.Create(ctx, order); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("standing order created", "standingOrderID", order.ID, "userID", userID, "nextRunOn", first)
+	return order, nil
+}
+
+func (s *StandingOrderService) GetStandingOrders(ctx context.Context, userID, status string) ([]models.StandingOrder, error) {
+	orders, err := s.standingRepo.FindByUser(ctx, userID, status)
+	if err != nil {
+		s.logger.Error("failed to get standing orders", "error", err, "userID", userID)
+		return nil, fmt.Errorf("error getting standing orders: %w", err)
+	}
+	return orders, nil
+}
+
+func (s *StandingOrderService) GetRuns(ctx context.Context, userID, standingOrderID string) ([]models.StandingOrderRun, error) {
+	if _, err := s.ownedOrder(ctx, userID, standingOrderID); err != nil {
+		return nil, err
+	}
+	runs, err := s.standingRepo.FindRuns(ctx, standingOrderID)
+	if err != nil {
+		s.logger.Error("failed to get standing order runs", "error", err, "standingOrderID", standingOrderID)
+		return nil, fmt.Errorf("error getting standing order runs: %w", err)
+	}
+	return runs, nil
+}
+
+// Pause stops an active order from running. Runs that fall due while it is
+// paused are skipped, not caught up on resume.
+func (s *StandingOrderService) Pause(ctx context.Context, userID, standingOrderID string) (*models.StandingOrder, error) {
+	order, err := s.ownedOrder(ctx, userID, standingOrderID)
+	if err != nil {
+		return nil, err
+	}
+	return s.standingRepo.UpdateState(ctx, order.ID, models.StandingOrderStatusActive, models.StandingOrderStatusPaused, order.NextRunOn)
+}
+
+// Resume reactivates a paused order from its next run date on or after
+// today. An order whose end date has passed in the meantime is completed.
+func (s *StandingOrderService) Resume(ctx context.Context, userID, standingOrderID string) (*models.StandingOrder, error) {
+	order, err := s.ownedOrder(ctx, userID, standingOrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Never go back before the run the order was paused at; that date, or an
+	// earlier one, may already have been paid.
+	after := recurrence.Day(time.Now()).AddDate(0, 0, -1)
+	if order.NextRunOn != nil && order.NextRunOn.AddDate(0, 0, -1).After(after) {
+		after = order.NextRunOn.AddDate(0, 0, -1)
+	}
+	next := standingOrderRule(order).Next(after)
+	if order.EndOn != nil && next.After(*order.EndOn) {
+		return s.standingRepo.UpdateState(ctx, order.ID, models.StandingOrderStatusPaused, models.StandingOrderStatusCompleted, nil)
+	}
+	return s.standingRepo.UpdateState(ctx, order.ID, models.StandingOrderStatusPaused, models.StandingOrderStatusActive, &next)
+}
+
+func (s *StandingOrderService) Cancel(ctx context.Context, userID, standingOrderID string) (*models.StandingOrder, error) {
+	order, err := s.ownedOrder(ctx, userID, standingOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.StandingOrderStatusActive && order.Status != models.StandingOrderStatusPaused {
+		return nil, errorsx.ErrStandingOrderState
+	}
+	return s.standingRepo.UpdateState(ctx, order.ID, order.Status, models.StandingOrderStatusCancelled, nil)
+}
+
+func (s *StandingOrderService) ownedOrder(ctx context.Context, userID, standingOrderID string) (*models.StandingOrder, error) {
+	order, err := s.standingRepo.FindByID(ctx, standingOrderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, errorsx.ErrStandingOrderNotFound
+	}
+	return order, nil
+}
+
+// ExecuteDue makes the next run of every active order due on or before
+// today. An order that missed several runs catches up one run per call. It
+// returns the number of runs attempted.
+func (s *StandingOrderService) ExecuteDue(ctx context.Context, today time.Time) (int, error) {
+	today = recurrence.Day(today)
+	ids, err := s.standingRepo.FindDueIDs(ctx, today)
+	if err != nil {
+		return 0, err
+	}
+
+	attempted := 0
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return attempted, err
+		}
+		ok, err := s.execute(ctx, id, today)
+		if err != nil {
+			if ctx.Err() != nil {
+				return attempted, ctx.Err()
+			}
+			s.logger.Error("failed to run standing order", "error", err, "standingOrderID", id)
+			continue
+		}
+		if ok {
+			attempted++
+		}
+	}
+
+	return attempted, nil
+}
+
+func (s *StandingOrderService) execute(ctx context.Context, standingOrderID string, today time.Time) (bool, error) {
+	order, err := s.standingRepo.FindByID(ctx, standingOrderID)
+	if err != nil {
+		return false, err
+	}
+	if order.Status != models.StandingOrderStatusActive || order.NextRunOn == nil || order.NextRunOn.After(today) {
+		return false, nil
+	}
+
+	runOn := recurrence.Day(*order.NextRunOn)
+	status := models.StandingOrderStatusActive
+	next := standingOrderRule(order).Next(runOn)
+	nextRunOn := &next
+	if order.EndOn != nil && next.After(*order.EndOn) {
+		status = models.StandingOrderStatusCompleted
+		nextRunOn = nil
+	}
+
+	// Advancing the order and recording the run in one DB transaction claims
+	// the date, so a second worker cannot pay it again.
+	tx, err := s.transactionRepo.BeginTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	ok, err := s.standingRepo.AdvanceNextRun(ctx, tx, order.ID, runOn, nextRunOn, status)
+	if err != nil || !ok {
+		return false, err
+	}
+	run := &models.StandingOrderRun{
+		StandingOrderID: order.ID,
+		RunOn:           runOn,
+		Status:          models.StandingOrderRunProcessing,
+	}
+	if err := s.standingRepo.CreateRun(ctx, tx, run); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit standing order claim", "error", err)
+		return false, fmt.Errorf("error committing standing order claim: %w", err)
+	}
+
+	// A successful run is settled inside the transfer's own commit, so a
+	// crash can only leave behind runs that never paid; RecoverStuck fails
+	// those.
+	settleCtx := context.WithoutCancel(ctx)
+	transaction, err := s.transactionService.transfer(ctx, order.UserID, dto.TransferRequest{
+		FromAccountID: order.FromAccountID,
+		ToAccountID:   order.ToAccountID,
+		AmountCents:   order.AmountCents,
+	}, s.settleRun(ctx, run))
+	if errors.Is(err, errStandingOrderRunLost) {
+		return false, err
+	}
+	if err != nil && ctx.Err() != nil {
+		if releaseErr := s.releaseRun(settleCtx, order.ID, run.ID, runOn, nextRunOn); releaseErr != nil {
+			s.logger.Error("failed to release standing order run", "error", releaseErr, "standingOrderID", order.ID)
+		}
+		return false, ctx.Err()
+	}
+
+	if err == nil {
+		s.logger.Info("standing order run executed", "standingOrderID", order.ID, "runOn", runOn, "transactionID", transaction.ID)
+		return true, nil
+	}
+
+	run.Status = models.StandingOrderRunFailed
+	run.FailureReason = err.Error()
+	run.TransactionID = nil
+	s.logger.Warn("standing order run failed", "standingOrderID", order.ID, "runOn", runOn, "error", err)
+	if err := s.standingRepo.FinishRun(settleCtx, run); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// settleRun marks the run executed in the DB transaction that posts its
+// transfer, and links the transaction back to the run.
+func (s *StandingOrderService) settleRun(ctx context.Context, run *models.StandingOrderRun) beforeCommit {
+	return func(tx *sqlx.Tx, transaction *models.Transaction) error {
+		ok, err := s.standingRepo.LinkTransactionInTx(ctx, tx, run.ID, transaction.ID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errStandingOrderRunLost
+		}
+		run.Status = models.StandingOrderRunExecuted
+		run.TransactionID = &transaction.ID
+		return s.standingRepo.FinishRunInTx(ctx, tx, run)
+	}
+}
+
+// RecoverStuck settles runs left in processing by a worker that died
+// between claiming the date and recording the outcome. A run whose transfer
+// was posted is marked executed with it; one that never posted is marked
+// failed, and the order carries on with its next date. Returns the number
+// settled.
+func (s *StandingOrderService) RecoverStuck(ctx context.Context, claimedBefore time.Time) (int, error) {
+	ids, err := s.standingRepo.FindStuckRunIDs(ctx, claimedBefore)
+	if err != nil {
+		return 0, err
+	}
+
+	recovered := 0
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return recovered, err
+		}
+		ok, err := s.recoverRun(ctx, id, claimedBefore)
+		if err != nil {
+			s.logger.Error("failed to recover standing order run", "error", err, "runID", id)
+			continue
+		}
+		if ok {
+			recovered++
+		}
+	}
+
+	return recovered, nil
+}
+
+func (s *StandingOrderService) recoverRun(ctx context.Context, runID string, claimedBefore time.Time) (bool, error) {
+	tx, err := s.transactionRepo.BeginTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// A run still posting holds this lock until it commits.
+	run, ok, err := s.standingRepo.FindStuckRunForUpdate(ctx, tx, runID, claimedBefore)
+	if err != nil || !ok {
+		return false, err
+	}
+	transactionID, err := s.standingRepo.FindRunTransactionID(ctx, tx, runID)
+	if err != nil {
+		return false, err
+	}
+
+	if transactionID != nil {
+		run.Status = models.StandingOrderRunExecuted
+		run.TransactionID = transactionID
+	} else {
+		run.Status = models.StandingOrderRunFailed
+		run.FailureReason = "interrupted before the transfer was posted"
+	}
+	if err := s.standingRepo.FinishRunInTx(ctx, tx, run); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("error committing standing order run recovery: %w", err)
+	}
+
+	s.logger.Warn("recovered stuck standing order run", "runID", run.ID, "standingOrderID", run.StandingOrderID,
+		"status", run.Status, "transactionID", run.TransactionID)
+	return true, nil
+}
+
+// releaseRun gives back a claimed date whose transfer was interrupted, so
+// the next run pays it.
+func (s *StandingOrderService) releaseRun(ctx context.Context, standingOrderID, runID string, runOn time.Time, advancedTo *time.Time) error {
+	tx, err := s.transactionRepo.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := s.standingRepo.DeleteRun(ctx, tx, runID); err != nil {
+		return err
+	}
+	if err := s.standingRepo.RestoreNextRun(ctx, tx, standingOrderID, runOn, advancedTo); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func standingOrderRule(order *models.StandingOrder) recurrence.Rule {
+	rule := recurrence.Rule{
+		Frequency: recurrence.Frequency(order.Frequency),
+		MonthEnd:  recurrence.MonthEnd(order.MonthEndRule),
+		Start:     order.StartOn,
+	}
+	if order.DayOfMonth != nil {
+		rule.DayOfMonth = *order.DayOfMonth
+	}
+	return rule
+}
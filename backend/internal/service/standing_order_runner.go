@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// StandingOrderRunner makes the due runs of standing orders in the background.
+type StandingOrderRunner struct {
+	orders   *StandingOrderService
+	interval time.Duration
+	cancel   context.CancelFunc
+	done     chan struct{}
+	logger   *slog.Logger
+}
+
+func NewStandingOrderRunner(orders *StandingOrderService, interval time.Duration, logger *slog.Logger) *StandingOrderRunner {
+	return &StandingOrderRunner{
+		orders:   orders,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+func (r *StandingOrderRunner) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go r.run(ctx)
+	r.logger.Info("standing order runner started", "interval", r.interval)
+}
+
+// Stop cancels the current run and waits for the loop to exit or for ctx to
+// be done, whichever comes first.
+func (r *StandingOrderRunner) Stop(ctx context.Context) error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+
+	select {
+	case <-r.done:
+		r.logger.Info("standing order runner stopped")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *StandingOrderRunner) run(ctx context.Context) {
+	defer close(r.done)
+
+	// Runs left in processing by a crashed process are settled at startup
+	// and then on every tick, in case another instance dies meanwhile.
+	r.recoverStuck(ctx)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		r.recoverStuck(ctx)
+		attempted, err := r.orders.ExecuteDue(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			r.logger.Error("standing order job failed", "error", err)
+			continue
+		}
+		if attempted > 0 {
+			r.logger.Info("standing order runs made", "count", attempted)
+		}
+	}
+}
+
+func (r *StandingOrderRunner) recoverStuck(ctx context.Context) {
+	recovered, err := r.orders.RecoverStuck(ctx, time.Now().UTC().Add(-stuckClaimAfter))
+	if err != nil && ctx.Err() == nil {
+		r.logger.Error("standing order recovery failed", "error", err)
+		return
+	}
+	if recovered > 0 {
+		r.logger.Warn("stuck standing order runs recovered", "count", recovered)
+	}
+}
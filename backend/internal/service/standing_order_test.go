@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/internal/repository"
+	"mini-banking-platform/pkg/recurrence"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func TestStandingOrders_RunPauseResume(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	orders := NewStandingOrderService(repos.Standing, repos.Transaction, newTestTransactionService(repos, logger), logger)
+	ctx := context.Background()
+
+	tenant := createTestUser(t, db, "tenant@test.com")
+	landlord := createTestUser(t, db, "landlord@test.com")
+	rentFrom := createTestAccount(t, db, tenant.ID, "USD", 10000)
+	rentTo := createTestAccount(t, db, landlord.ID, "USD", 0)
+
+	today := recurrence.Day(time.Now())
+	if _, err := orders.CreateStandingOrder(ctx, tenant.ID, dto.CreateStandingOrderRequest{
+		FromAccountID: rentFrom.ID, ToAccountID: rentTo.ID, AmountCents: 100, Frequency: "weekly",
+		StartOn: today.AddDate(0, 0, -1).Format("2006-01-02"),
+	}); err == nil {
+		t.Error("Expected a start date in the past to be rejected")
+	}
+
+	rent, err := orders.CreateStandingOrder(ctx, tenant.ID, dto.CreateStandingOrderRequest{
+		FromAccountID: rentFrom.ID,
+		ToUserID:      landlord.Email,
+		AmountCents:   3000,
+		Frequency:     "monthly",
+		StartOn:       today.Format("2006-01-02"),
+	})
+	if err != nil {
+		t.Fatalf("CreateStandingOrder failed: %v", err)
+	}
+	if rent.ToAccountID != rentTo.ID || rent.DayOfMonth == nil || *rent.DayOfMonth != today.Day() {
+		t.Errorf("Expected the order to pay %s on day %d, got %+v", rentTo.ID, today.Day(), rent)
+	}
+	if rent.NextRunOn == nil || !rent.NextRunOn.Equal(today) {
+		t.Fatalf("Expected the first run today, got %v", rent.NextRunOn)
+	}
+
+	savings, err := orders.CreateStandingOrder(ctx, tenant.ID, dto.CreateStandingOrderRequest{
+		FromAccountID: rentFrom.ID,
+		ToAccountID:   rentTo.ID,
+		AmountCents:   50000,
+		Frequency:     "weekly",
+		StartOn:       today.Format("2006-01-02"),
+	})
+	if err != nil {
+		t.Fatalf("CreateStandingOrder failed: %v", err)
+	}
+
+	attempted, err := orders.ExecuteDue(ctx, today)
+	if err != nil || attempted != 2 {
+		t.Fatalf("Expected 2 runs, got %d, %v", attempted, err)
+	}
+	if attempted, err := orders.ExecuteDue(ctx, today); err != nil || attempted != 0 {
+		t.Errorf("Expected no second run on the same day, got %d, %v", attempted, err)
+	}
+
+	runs, err := orders.GetRuns(ctx, tenant.ID, rent.ID)
+	if err != nil || len(runs) != 1 || runs[0].Status != models.StandingOrderRunExecuted || runs[0].TransactionID == nil {
+		t.Errorf("Expected one executed rent run, got %+v, %v", runs, err)
+	}
+	runs, err = orders.GetRuns(ctx, tenant.ID, savings.ID)
+	if err != nil || len(runs) != 1 || runs[0].Status != models.StandingOrderRunFailed || runs[0].FailureReason != errorsx.ErrInsufficientFunds.Error() {
+		t.Errorf("Expected one failed savings run, got %+v, %v", runs, err)
+	}
+	if _, err := orders.GetRuns(ctx, landlord.ID, rent.ID); err != errorsx.ErrStandingOrderNotFound {
+		t.Errorf("Expected ErrStandingOrderNotFound for another user, got %v", err)
+	}
+
+	paused, err := orders.Pause(ctx, tenant.ID, savings.ID)
+	if err != nil || paused.Status != models.StandingOrderStatusPaused {
+		t.Fatalf("Pause failed: %+v, %v", paused, err)
+	}
+	if _, err := orders.Pause(ctx, tenant.ID, savings.ID); err != errorsx.ErrStandingOrderState {
+		t.Errorf("Expected ErrStandingOrderState pausing twice, got %v", err)
+	}
+	if attempted, err := orders.ExecuteDue(ctx, today.AddDate(0, 0, 7)); err != nil || attempted != 0 {
+		t.Errorf("Expected the paused order to be skipped, got %d, %v", attempted, err)
+	}
+	resumed, err := orders.Resume(ctx, tenant.ID, savings.ID)
+	if err != nil || resumed.Status != models.StandingOrderStatusActive || !resumed.NextRunOn.Equal(today.AddDate(0, 0, 7)) {
+		t.Errorf("Expected the resumed order to run next week, got %+v, %v", resumed, err)
+	}
+
+	if _, err := orders.Cancel(ctx, tenant.ID, rent.ID); err != nil {
+		t.Fatalf("Cancel failed: %v", err)
+	}
+	if attempted, err := orders.ExecuteDue(ctx, today.AddDate(0, 2, 0)); err != nil || attempted != 1 {
+		t.Errorf("Expected only the savings order to run, got %d, %v", attempted, err)
+	}
+
+	var balance int64
+	db.Get(&balance, "SELECT balance_cents FROM accounts WHERE id = $1", rentTo.ID)
+	if balance != 3000 {
+		t.Errorf("Expected the landlord to receive one rent payment, got %d", balance)
+	}
+}
+
+func TestStandingOrders_RecoversStuckRuns(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	orders := NewStandingOrderService(repos.Standing, repos.Transaction, newTestTransactionService(repos, logger), logger)
+	ctx := context.Background()
+
+	tenant := createTestUser(t, db, "stuck-tenant@test.com")
+	landlord := createTestUser(t, db, "stuck-landlord@test.com")
+	from := createTestAccount(t, db, tenant.ID, "USD", 10000)
+	to := createTestAccount(t, db, landlord.ID, "USD", 0)
+
+	today := recurrence.Day(time.Now())
+	create := func(amountCents int64) *models.StandingOrder {
+		order, err := orders.CreateStandingOrder(ctx, tenant.ID, dto.CreateStandingOrderRequest{
+			FromAccountID: from.ID, ToAccountID: to.ID, AmountCents: amountCents, Frequency: "weekly",
+			StartOn: today.Format("2006-01-02"),
+		})
+		if err != nil {
+			t.Fatalf("CreateStandingOrder failed: %v", err)
+		}
+		return order
+	}
+	// claim does what execute commits before paying, then the worker dies.
+	claim := func(order *models.StandingOrder) *models.StandingOrderRun {
+		tx, err := repos.Transaction.BeginTx(ctx)
+		if err != nil {
+			t.Fatalf("BeginTx failed: %v", err)
+		}
+		defer tx.Rollback()
+		next := today.AddDate(0, 0, 7)
+		if ok, err := repos.Standing.AdvanceNextRun(ctx, tx, order.ID, today, &next, models.StandingOrderStatusActive); err != nil || !ok {
+			t.Fatalf("AdvanceNextRun failed: %v", err)
+		}
+		run := &models.StandingOrderRun{StandingOrderID: order.ID, RunOn: today, Status: models.StandingOrderRunProcessing}
+		if err := repos.Standing.CreateRun(ctx, tx, run); err != nil {
+			t.Fatalf("CreateRun failed: %v", err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Commit failed: %v", err)
+		}
+		return run
+	}
+
+	paid := create(3000)
+	unpaid := create(1000)
+	paidRun := claim(paid)
+	unpaidRun := claim(unpaid)
+
+	// The first run's transfer got as far as linking itself before the
+	// outcome was written.
+	transaction, err := orders.transactionService.transfer(ctx, tenant.ID,
+		dto.TransferRequest{FromAccountID: from.ID, ToAccountID: to.ID, AmountCents: 3000},
+		func(tx *sqlx.Tx, transaction *models.Transaction) error {
+			_, err := repos.Standing.LinkTransactionInTx(ctx, tx, paidRun.ID, transaction.ID)
+			return err
+		})
+	if err != nil {
+		t.Fatalf("transfer failed: %v", err)
+	}
+
+	if recovered, err := orders.RecoverStuck(ctx, time.Now().UTC().Add(-time.Hour)); err != nil || recovered != 0 {
+		t.Errorf("Expected fresh runs to be left alone, got %d, %v", recovered, err)
+	}
+	recovered, err := orders.RecoverStuck(ctx, time.Now().UTC().Add(time.Minute))
+	if err != nil || recovered != 2 {
+		t.Fatalf("Expected 2 runs recovered, got %d, %v", recovered, err)
+	}
+
+	runs, err := orders.GetRuns(ctx, tenant.ID, paid.ID)
+	if err != nil || len(runs) != 1 || runs[0].Status != models.StandingOrderRunExecuted ||
+		runs[0].TransactionID == nil || *runs[0].TransactionID != transaction.ID {
+		t.Errorf("Expected the paid run to be executed with %s, got %+v, %v", transaction.ID, runs, err)
+	}
+	runs, err = orders.GetRuns(ctx, tenant.ID, unpaid.ID)
+	if err != nil || len(runs) != 1 || runs[0].Status != models.StandingOrderRunFailed || runs[0].FailureReason == "" {
+		t.Errorf("Expected the unpaid run to be failed, got %+v, %v", runs, err)
+	}
+
+	// A transfer still going when recovery failed its run is rolled back.
+	if _, err := orders.transactionService.transfer(ctx, tenant.ID,
+		dto.TransferRequest{FromAccountID: from.ID, ToAccountID: to.ID, AmountCents: 1000},
+		orders.settleRun(ctx, unpaidRun)); err != errStandingOrderRunLost {
+		t.Errorf("Expected errStandingOrderRunLost, got %v", err)
+	}
+
+	var balance int64
+	db.Get(&balance, "SELECT balance_cents FROM accounts WHERE id = $1", to.ID)
+	if balance != 3000 {
+		t.Errorf("Expected one payment of 3000, got %d", balance)
+	}
+
+	// Both orders carry on with their next date.
+	if attempted, err := orders.ExecuteDue(ctx, today.AddDate(0, 0, 7)); err != nil || attempted != 2 {
+		t.Errorf("Expected both orders to run next week, got %d, %v", attempted, err)
+	}
+	db.Get(&balance, "SELECT balance_cents FROM accounts WHERE id = $1", to.ID)
+	if balance != 7000 {
+		t.Errorf("Expected 7000 after next week's runs, got %d", balance)
+	}
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/models"
+	"mini-banking-platform/internal/repository"
+	"time"
+)
+
+const statementPeriodLayout = "2006-01"
+
+// statementSettlementGrace is how long after a month ends its statement can
+// first be issued. Postings are dated when their DB transaction starts, so
+// one that started before midnight may commit just after it; posting
+// transactions take milliseconds, so by then every one dated inside the month
+// has committed.
+const statementSettlementGrace = 10 * time.Minute
+
+type StatementService struct {
+	statementRepo *repository.StatementRepository
+	accountRepo   *repository.AccountRepository
+	logger        *slog.Logger
+}
+
+func NewStatementService(statementRepo *repository.StatementRepository, accountRepo *repository.AccountRepository, logger *slog.Logger) *StatementService {
+	return &StatementService{
+		statementRepo: statementRepo,
+		accountRepo:   accountRepo,
+		logger:        logger,
+	}
+}
+
+// GetStatement returns the statement for a calendar month (YYYY-MM, UTC). The
+// first request once the month has settled issues it from the ledger and
+// stores it; later requests return the stored copy unchanged.
+func (s *StatementService) GetStatement(ctx context.Context, userID, accountID, period string) (*models.Statement, error) {
+	start, err := time.Parse(statementPeriodLayout, period)
+	if err != nil {
+		return nil, errorsx.BadRequest("period must be in YYYY-MM format")
+	}
+	end := start.AddDate(0, 1, 0)
+	if end.After(time.Now().UTC()) {
+		return nil, errorsx.BadRequest("statements can only be issued for finished months")
+	}
+	if end.Add(statementSettlementGrace).After(time.Now().UTC()) {
+		return nil, errorsx.ErrStatementNotSettled
+	}
+
+	account, err := s.accountRepo.FindByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireAccountRole(ctx, s.accountRepo, account, userID, models.AccountRoleViewOnly); err != nil {
+		s.logger.Warn("unauthorized statement access", "userID", userID, "accountID", accountID)
+		return nil, err
+	}
+	if !account.CreatedAt.Before(end) {
+		return nil, errorsx.BadRequest("account did not exist during this period")
+	}
+
+	statement, err := s.statementRepo.FindByAccountAndPeriod(ctx, account.ID, period)
+	if err != nil {
+		return nil, err
+	}
+	if statement != nil {
+		return statement, nil
+	}
+
+	return s.issue(ctx, account, period, start, end)
+}
+
+func (s *StatementService) issue(ctx context.Context, account *models.Account, period string, start, end time.Time) (*models.Statement, error) {
+	tx, err := s.statementRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	opening, err := s.statementRepo.GetBalanceBefore(ctx, tx, account.ID, start)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := s.statementRepo.FindMovements(ctx, tx, account.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &models.Statement{
+		AccountID:           account.ID,
+		Period:              period,
+		PeriodStart:         start,
+		PeriodEnd:           end,
+		Currency:            account.Currency,
+		OpeningBalanceCents: opening,
+		Lines:               make([]models.StatementLine, 0, len(lines)),
+	}
+	balance := opening
+	for i, line := range lines {
+		balance += line.AmountCents
+		if line.AmountCents >= 0 {
+			statement.TotalCreditsCents += line.AmountCents
+		} else {
+			statement.TotalDebitsCents -= line.AmountCents
+		}
+		line.Position = i + 1
+		line.BalanceCents = balance
+		statement.Lines = append(statement.Lines, line)
+	}
+	statement.ClosingBalanceCents = balance
+
+	created, err := s.statementRepo.Create(ctx, tx, statement)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		// A concurrent request issued it first; theirs is the statement of record.
+		tx.Rollback()
+		return s.statementRepo.FindByAccountAndPeriod(ctx, account.ID, period)
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit statement", "error", err)
+		return nil, fmt.Errorf("error committing statement: %w", err)
+	}
+
+	s.logger.Info("statement issued", "accountID", account.ID, "period", period, "lines", len(statement.Lines))
+	return statement, nil
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"mini-banking-platform/internal/errorsx"
+	"mini-banking-platform/internal/http/dto"
+	"mini-banking-platform/internal/repository"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestStatement_IssuedFromLedgerAndImmutable(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	statements := NewStatementService(repos.Statement, repos.Account, logger)
+	transactions := newTestTransactionService(repos, logger)
+	ctx := context.Background()
+
+	userA := createTestUser(t, db, "statement@test.com")
+	userB := createTestUser(t, db, "counterparty@test.com")
+	accountA := createTestAccount(t, db, userA.ID, "USD", 10000)
+	createTestAccount(t, db, userB.ID, "USD", 0)
+
+	today := time.Now().UTC()
+	month := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	period := month.Format("2006-01")
+
+	if _, err := db.Exec(`UPDATE accounts SET created_at = $1 WHERE id = $2`, month.AddDate(0, 0, -1), accountA.ID); err != nil {
+		t.Fatalf("Failed to backdate account: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE ledger_entries SET created_at = $1 WHERE account_id = $2`, month.AddDate(0, 0, -1), accountA.ID); err != nil {
+		t.Fatalf("Failed to backdate deposit: %v", err)
+	}
+
+	for _, amount := range []int64{2500, 1000} {
+		transfer := dto.TransferRequest{ToUserID: userB.ID, Currency: "USD", AmountCents: amount}
+		if _, err := transactions.Transfer(ctx, userA.ID, transfer); err != nil {
+			t.Fatalf("Transfer failed: %v", err)
+		}
+	}
+	if _, err := db.Exec(`UPDATE ledger_entries SET created_at = $1 WHERE account_id = $2 AND amount_cents = -2500`, month.AddDate(0, 0, 3), accountA.ID); err != nil {
+		t.Fatalf("Failed to backdate transfer: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE ledger_entries SET created_at = $1 WHERE account_id = $2 AND amount_cents = -1000`, month.AddDate(0, 0, 10), accountA.ID); err != nil {
+		t.Fatalf("Failed to backdate transfer: %v", err)
+	}
+
+	statement, err := statements.GetStatement(ctx, userA.ID, accountA.ID, period)
+	if err != nil {
+		t.Fatalf("GetStatement failed: %v", err)
+	}
+	if statement.OpeningBalanceCents != 10000 || statement.ClosingBalanceCents != 6500 {
+		t.Errorf("Expected opening 10000 and closing 6500, got %d and %d", statement.OpeningBalanceCents, statement.ClosingBalanceCents)
+	}
+	if statement.TotalDebitsCents != 3500 || statement.TotalCreditsCents != 0 {
+		t.Errorf("Expected 3500 debits and no credits, got %d and %d", statement.TotalDebitsCents, statement.TotalCreditsCents)
+	}
+	if len(statement.Lines) != 2 || statement.Lines[0].BalanceCents != 7500 || statement.Lines[1].BalanceCents != 6500 {
+		t.Fatalf("Unexpected running balances: %+v", statement.Lines)
+	}
+
+	// A late posting dated into the period must not change the issued statement.
+	transfer := dto.TransferRequest{ToUserID: userB.ID, Currency: "USD", AmountCents: 500}
+	if _, err := transactions.Transfer(ctx, userA.ID, transfer); err != nil {
+		t.Fatalf("Transfer failed: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE ledger_entries SET created_at = $1 WHERE account_id = $2 AND amount_cents = -500`, month.AddDate(0, 0, 20), accountA.ID); err != nil {
+		t.Fatalf("Failed to backdate late posting: %v", err)
+	}
+
+	again, err := statements.GetStatement(ctx, userA.ID, accountA.ID, period)
+	if err != nil {
+		t.Fatalf("GetStatement failed: %v", err)
+	}
+	if again.ID != statement.ID || again.ClosingBalanceCents != 6500 || len(again.Lines) != 2 {
+		t.Errorf("Expected the issued statement back unchanged, got %+v", again)
+	}
+
+	if _, err := statements.GetStatement(ctx, userB.ID, accountA.ID, period); err != errorsx.ErrAccountNotFound {
+		t.Errorf("Expected ErrAccountNotFound for another user, got %v", err)
+	}
+	if _, err := statements.GetStatement(ctx, userA.ID, accountA.ID, today.Format("2006-01")); err == nil {
+		t.Error("Expected the current month to be rejected")
+	}
+}
+
+func TestStatement_ClosingMatchesNextOpening(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	statements := NewStatementService(repos.Statement, repos.Account, logger)
+	transactions := newTestTransactionService(repos, logger)
+	ctx := context.Background()
+
+	userA := createTestUser(t, db, "chained@test.com")
+	userB := createTestUser(t, db, "chained-payee@test.com")
+	accountA := createTestAccount(t, db, userA.ID, "USD", 10000)
+	createTestAccount(t, db, userB.ID, "USD", 0)
+
+	today := time.Now().UTC()
+	lastMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	earlier := lastMonth.AddDate(0, -1, 0)
+
+	if _, err := db.Exec(`UPDATE accounts SET created_at = $1 WHERE id = $2`, earlier.AddDate(0, 0, -1), accountA.ID); err != nil {
+		t.Fatalf("Failed to backdate account: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE ledger_entries SET created_at = $1 WHERE account_id = $2`, earlier.AddDate(0, 0, -1), accountA.ID); err != nil {
+		t.Fatalf("Failed to backdate deposit: %v", err)
+	}
+
+	// One posting on each side of the month boundary, the first in the last
+	// second of the earlier month.
+	postings := []struct {
+		amount int64
+		at     time.Time
+	}{
+		{1500, lastMonth.Add(-time.Second)},
+		{700, lastMonth},
+	}
+	for _, p := range postings {
+		if _, err := transactions.Transfer(ctx, userA.ID, dto.TransferRequest{ToUserID: userB.ID, Currency: "USD", AmountCents: p.amount}); err != nil {
+			t.Fatalf("Transfer failed: %v", err)
+		}
+		if _, err := db.Exec(`UPDATE ledger_entries SET created_at = $1 WHERE account_id = $2 AND amount_cents = $3`, p.at, accountA.ID, -p.amount); err != nil {
+			t.Fatalf("Failed to backdate transfer: %v", err)
+		}
+	}
+
+	first, err := statements.GetStatement(ctx, userA.ID, accountA.ID, earlier.Format("2006-01"))
+	if err != nil {
+		t.Fatalf("GetStatement failed: %v", err)
+	}
+	second, err := statements.GetStatement(ctx, userA.ID, accountA.ID, lastMonth.Format("2006-01"))
+	if err != nil {
+		t.Fatalf("GetStatement failed: %v", err)
+	}
+	if first.ClosingBalanceCents != 8500 || second.OpeningBalanceCents != first.ClosingBalanceCents {
+		t.Errorf("Expected closing 8500 to carry over as the next opening, got %d and %d", first.ClosingBalanceCents, second.OpeningBalanceCents)
+	}
+	if second.ClosingBalanceCents != 7800 {
+		t.Errorf("Expected closing 7800, got %d", second.ClosingBalanceCents)
+	}
+}
@@ -4,18 +4,31 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"math"
 	"mini-banking-platform/internal/errorsx"
 	"mini-banking-platform/internal/http/dto"
 	"mini-banking-platform/internal/models"
 	"mini-banking-platform/internal/repository"
+	"mini-banking-platform/pkg/accountnumber"
+	"mini-banking-platform/pkg/decimal"
 	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
 )
 
 type TransactionService struct {
 	accountRepo     *repository.AccountRepository
 	transactionRepo *repository.TransactionRepository
 	userRepo        *repository.UserRepository
+	currencyRepo    *repository.CurrencyRepository
+	quoteRepo       *repository.FXQuoteRepository
+	spreadRepo      *repository.FXSpreadRepository
+	roundingRepo    *repository.RoundingRepository
+	scheduledRepo   *repository.ScheduledTransferRepository
+	standingRepo    *repository.StandingOrderRepository
+	rates           FXRateProvider
+	interest        *InterestService
+	roundingMode    decimal.RoundingMode
 	logger          *slog.Logger
 }
 
@@ -23,66 +36,89 @@ func NewTransactionService(
 	accountRepo *repository.AccountRepository,
 	transactionRepo *repository.TransactionRepository,
 	userRepo *repository.UserRepository,
+	currencyRepo *repository.CurrencyRepository,
+	quoteRepo *repository.FXQuoteRepository,
+	spreadRepo *repository.FXSpreadRepository,
+	roundingRepo *repository.RoundingRepository,
+	scheduledRepo *repository.ScheduledTransferRepository,
+	standingRepo *repository.StandingOrderRepository,
+	rates FXRateProvider,
+	interest *InterestService,
+	roundingMode decimal.RoundingMode,
 	logger *slog.Logger,
 ) *TransactionService {
 	return &TransactionService{
 		accountRepo:     accountRepo,
 		transactionRepo: transactionRepo,
 		userRepo:        userRepo,
+		currencyRepo:    currencyRepo,
+		quoteRepo:       quoteRepo,
+		spreadRepo:      spreadRepo,
+		roundingRepo:    roundingRepo,
+		scheduledRepo:   scheduledRepo,
+		standingRepo:    standingRepo,
+		rates:           rates,
+		interest:        interest,
+		roundingMode:    roundingMode,
 		logger:          logger,
 	}
 }
 
+// beforeCommit runs inside a posting's DB transaction once the transaction
+// row and its ledger entries are written, so whatever it stores commits or
+// rolls back together with the money.
+type beforeCommit func(tx *sqlx.Tx, transaction *models.Transaction) error
+
 func (s *TransactionService) Transfer(ctx context.Context, fromUserID string, req dto.TransferRequest) (*models.Transaction, error) {
+	return s.transfer(ctx, fromUserID, req, nil)
+}
+
+func (s *TransactionService) transfer(ctx context.Context, fromUserID string, req dto.TransferRequest, hook beforeCommit) (*models.Transaction, error) {
 	amountCents := req.AmountCents
 	if amountCents <= 0 {
 		return nil, errorsx.ErrInvalidAmount
 	}
-	if req.Currency != models.CurrencyUSD && req.Currency != models.CurrencyEUR {
-		return nil, errorsx.ErrInvalidCurrency
-	}
 
-	var toUser *models.User
-	var err error
-	if strings.Contains(req.ToUserID, "@") {
-		toUser, err = s.userRepo.FindByEmail(ctx, req.ToUserID)
-	} else {
-		toUser, err = s.userRepo.FindByID(ctx, req.ToUserID)
-	}
+	fromAccount, err := s.transferSourceAccount(ctx, fromUserID, req)
 	if err != nil {
-		return nil, errorsx.ErrUserNotFound
+		return nil, err
 	}
-
-	if fromUserID == toUser.ID {
-		return nil, errorsx.ErrCannotTransferToSelf
+	if _, err := enabledCurrency(ctx, s.currencyRepo, fromAccount.Currency); err != nil {
+		return nil, err
 	}
 
-	tx, err := s.transactionRepo.BeginTx(ctx)
+	toAccount, toUser, err := s.transferTargetAccount(ctx, fromUserID, fromAccount, req)
 	if err != nil {
 		return nil, err
 	}
-	defer tx.Rollback()
 
-	fromAccount, err := s.accountRepo.FindByUserAndCurrency(ctx, fromUserID, req.Currency)
-	if err != nil {
+	if err := checkAccountStatus(fromAccount, true); err != nil {
+		return nil, err
+	}
+	if err := checkAccountStatus(toAccount, false); err != nil {
 		return nil, err
 	}
 
-	toAccount, err := s.accountRepo.FindByUserAndCurrency(ctx, toUser.ID, req.Currency)
+	if toAccount.Currency != fromAccount.Currency {
+		return s.crossCurrencyTransfer(ctx, fromUserID, fromAccount, toAccount, toUser, amountCents, hook)
+	}
+
+	tx, err := s.transactionRepo.BeginTx(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 
 	if err := s.accountRepo.LockAccountsForUpdate(ctx, tx, []string{fromAccount.ID, toAccount.ID}); err != nil {
 		return nil, err
 	}
 
-	balanceCents, err := s.accountRepo.GetBalanceCents(ctx, tx, fromAccount.ID)
+	availableCents, err := s.accountRepo.GetAvailableCents(ctx, tx, fromAccount.ID)
 	if err != nil {
 		return nil, err
 	}
-	if balanceCents < amountCents {
-		s.logger.Warn("insufficient funds", "userID", fromUserID, "available", balanceCents, "required", amountCents)
+	if availableCents < amountCents {
+		s.logger.Warn("insufficient funds", "userID", fromUserID, "available", availableCents, "required", amountCents)
 		return nil, errorsx.ErrInsufficientFunds
 	}
 
@@ -90,9 +126,9 @@ func (s *TransactionService) Transfer(ctx context.Context, fromUserID string, re
 		Type:        models.TransactionTypeTransfer,
 		FromUserID:  fromUserID,
 		ToUserID:    &toUser.ID,
-		Currency:    req.Currency,
+		Currency:    fromAccount.Currency,
 		AmountCents: amountCents,
-		Description: fmt.Sprintf("Transfer to %s %s", toUser.FirstName, toUser.LastName),
+		Description: transferDescription(toUser, toAccount),
 	}
 
 	if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
@@ -102,7 +138,7 @@ func (s *TransactionService) Transfer(ctx context.Context, fromUserID string, re
 	debitEntry := &models.LedgerEntry{
 		TransactionID: transaction.ID,
 		AccountID:     fromAccount.ID,
-		Currency:      req.Currency,
+		Currency:      fromAccount.Currency,
 		AmountCents:   -amountCents,
 	}
 	if err := s.transactionRepo.CreateLedgerEntry(ctx, tx, debitEntry); err != nil {
@@ -112,7 +148,7 @@ func (s *TransactionService) Transfer(ctx context.Context, fromUserID string, re
 	creditEntry := &models.LedgerEntry{
 		TransactionID: transaction.ID,
 		AccountID:     toAccount.ID,
-		Currency:      req.Currency,
+		Currency:      fromAccount.Currency,
 		AmountCents:   amountCents,
 	}
 	if err := s.transactionRepo.CreateLedgerEntry(ctx, tx, creditEntry); err != nil {
@@ -125,154 +161,251 @@ func (s *TransactionService) Transfer(ctx context.Context, fromUserID string, re
 	if err := s.accountRepo.UpdateBalanceCents(ctx, tx, toAccount.ID, amountCents); err != nil {
 		return nil, err
 	}
+	if err := runBeforeCommit(hook, tx, transaction); err != nil {
+		return nil, err
+	}
 
 	if err := tx.Commit(); err != nil {
 		s.logger.Error("failed to commit transfer", "error", err)
 		return nil, fmt.Errorf("error committing transfer: %w", err)
 	}
 
-	s.logger.Info("transfer completed", "transactionID", transaction.ID, "from", fromUserID, "to", req.ToUserID, "amountCents", amountCents)
+	s.logger.Info("transfer completed", "transactionID", transaction.ID, "fromAccount", fromAccount.ID, "toAccount", toAccount.ID, "amountCents", amountCents)
 	return transaction, nil
 }
 
-func (s *TransactionService) Exchange(ctx context.Context, userID string, req dto.ExchangeRequest) (*models.Transaction, error) {
-	fromAmountCents := req.AmountCents
-	if fromAmountCents <= 0 {
-		return nil, errorsx.ErrInvalidAmount
+// transferSourceAccount returns the account named by from_account_id, or the
+// sender's primary account in currency.
+func (s *TransactionService) transferSourceAccount(ctx context.Context, fromUserID string, req dto.TransferRequest) (*models.Account, error) {
+	if req.FromAccountID == "" {
+		return s.accountRepo.FindByUserAndCurrency(ctx, fromUserID, req.Currency)
 	}
 
-	if fromAmountCents < models.MinExchangeAmountCents {
-		return nil, errorsx.BadRequest(fmt.Sprintf("minimum exchange amount is %d cents", models.MinExchangeAmountCents))
+	account, err := s.accountRepo.FindByID(ctx, req.FromAccountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireAccountRole(ctx, s.accountRepo, account, fromUserID, models.AccountRoleCanTransact); err != nil {
+		return nil, err
 	}
+	if req.Currency != "" && req.Currency != account.Currency {
+		return nil, errorsx.BadRequest("currency does not match from_account_id")
+	}
+	return account, nil
+}
+
+// transferTargetAccount resolves the credited account. An explicit
+// to_account_id or to_account_number wins; otherwise the recipient's primary
+// account is used in to_currency, then their preferred currency, then the
+// source currency. Moving money between one's own accounts requires an
+// explicit account.
+func (s *TransactionService) transferTargetAccount(ctx context.Context, fromUserID string, fromAccount *models.Account, req dto.TransferRequest) (*models.Account, *models.User, error) {
+	if req.ToAccountID != "" || req.ToAccountNumber != "" {
+		toAccount, err := s.destinationAccount(ctx, req)
+		if err != nil {
+			return nil, nil, err
+		}
+		if toAccount.ID == fromAccount.ID {
+			return nil, nil, errorsx.ErrCannotTransferToSelf
+		}
+		if req.ToCurrency != "" && req.ToCurrency != toAccount.Currency {
+			return nil, nil, errorsx.BadRequest("to_currency does not match to_account_id")
+		}
 
-	fromCurrency := req.FromCurrency
-	var toCurrency string
-	var rateNum, rateDenom int64
+		toUser, err := s.userRepo.FindByID(ctx, toAccount.UserID)
+		if err != nil {
+			return nil, nil, errorsx.ErrUserNotFound
+		}
+		if req.ToUserID != "" && req.ToUserID != toUser.ID && !strings.EqualFold(req.ToUserID, toUser.Email) {
+			return nil, nil, errorsx.BadRequest("to_user_id does not own to_account_id")
+		}
+		return toAccount, toUser, nil
+	}
 
-	if fromCurrency == models.CurrencyUSD {
-		toCurrency = models.CurrencyEUR
-		rateNum = models.ExchangeRateUSDtoEURNum
-		rateDenom = models.ExchangeRateUSDtoEURDenom
-	} else if fromCurrency == models.CurrencyEUR {
-		toCurrency = models.CurrencyUSD
-		rateNum = models.ExchangeRateEURtoUSDNum
-		rateDenom = models.ExchangeRateEURtoUSDDenom
+	var toUser *models.User
+	var err error
+	if strings.Contains(req.ToUserID, "@") {
+		toUser, err = s.userRepo.FindByEmail(ctx, req.ToUserID)
 	} else {
-		return nil, errorsx.ErrInvalidCurrency
+		toUser, err = s.userRepo.FindByID(ctx, req.ToUserID)
+	}
+	if err != nil {
+		return nil, nil, errorsx.ErrUserNotFound
 	}
 
-	if fromCurrency == toCurrency {
-		return nil, errorsx.ErrCurrenciesMustDiffer
+	if fromUserID == toUser.ID {
+		return nil, nil, errorsx.ErrCannotTransferToSelf
 	}
 
-	maxSafeAmount := int64(math.MaxInt64 / rateNum)
-	if fromAmountCents > maxSafeAmount {
-		s.logger.Error("exchange amount too large, would cause overflow",
-			"fromAmountCents", fromAmountCents, "maxSafe", maxSafeAmount)
-		return nil, errorsx.BadRequest("amount too large")
+	toAccount, err := s.accountRepo.FindByUserAndCurrency(ctx, toUser.ID, transferTargetCurrency(req, toUser, fromAccount.Currency))
+	if err != nil {
+		return nil, nil, err
 	}
+	return toAccount, toUser, nil
+}
 
-	toAmountCents := (fromAmountCents * rateNum) / rateDenom
+// destinationAccount looks up the account named by to_account_id or
+// to_account_number. Mistyped numbers fail the check digits and are rejected
+// before the lookup.
+func (s *TransactionService) destinationAccount(ctx context.Context, req dto.TransferRequest) (*models.Account, error) {
+	if req.ToAccountNumber == "" {
+		return s.accountRepo.FindByID(ctx, req.ToAccountID)
+	}
+	if req.ToAccountID != "" {
+		return nil, errorsx.BadRequest("use either to_account_id or to_account_number")
+	}
 
-	tx, err := s.transactionRepo.BeginTx(ctx)
-	if err != nil {
-		return nil, err
+	number := accountnumber.Normalize(req.ToAccountNumber)
+	if err := accountnumber.Validate(number); err != nil {
+		return nil, errorsx.BadRequest(err.Error())
 	}
-	defer tx.Rollback()
+	return s.accountRepo.FindByAccountNumber(ctx, number)
+}
 
-	fromAccount, err := s.accountRepo.FindByUserAndCurrency(ctx, userID, fromCurrency)
-	if err != nil {
-		return nil, err
+// transferTargetCurrency picks the currency the recipient is credited in: an
+// explicit to_currency wins, then the recipient's preferred currency.
+func transferTargetCurrency(req dto.TransferRequest, toUser *models.User, fromCurrency string) string {
+	if req.ToCurrency != "" {
+		return req.ToCurrency
+	}
+	if toUser.PreferredCurrency != nil {
+		return *toUser.PreferredCurrency
 	}
+	return fromCurrency
+}
 
-	toAccount, err := s.accountRepo.FindByUserAndCurrency(ctx, userID, toCurrency)
+func transferDescription(toUser *models.User, toAccount *models.Account) string {
+	if toAccount.Name != nil {
+		return fmt.Sprintf("Transfer to %s %s (%s)", toUser.FirstName, toUser.LastName, *toAccount.Name)
+	}
+	return fmt.Sprintf("Transfer to %s %s", toUser.FirstName, toUser.LastName)
+}
+
+// crossCurrencyTransfer debits fromAccount and credits toAccount in its own
+// currency, converting through the FX system accounts in the same DB
+// transaction.
+func (s *TransactionService) crossCurrencyTransfer(ctx context.Context, fromUserID string, fromAccount, toAccount *models.Account, toUser *models.User, amountCents int64, hook beforeCommit) (*models.Transaction, error) {
+	pricing, err := s.priceExchange(ctx, fromAccount.Currency, toAccount.Currency, amountCents, time.Now().UTC())
 	if err != nil {
 		return nil, err
 	}
 
-	fxFromAccount, err := s.accountRepo.FindFXAccountByCurrency(ctx, fromCurrency)
+	tx, err := s.transactionRepo.BeginTx(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 
-	fxToAccount, err := s.accountRepo.FindFXAccountByCurrency(ctx, toCurrency)
+	fxAccounts, err := s.findConversionAccounts(ctx, pricing)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := s.accountRepo.LockAccountsForUpdate(ctx, tx, []string{
+	if err := s.accountRepo.LockAccountsForUpdate(ctx, tx, append([]string{
 		fromAccount.ID,
 		toAccount.ID,
-		fxFromAccount.ID,
-		fxToAccount.ID,
-	}); err != nil {
+	}, fxAccounts.IDs()...)); err != nil {
 		return nil, err
 	}
 
-	balanceCents, err := s.accountRepo.GetBalanceCents(ctx, tx, fromAccount.ID)
+	availableCents, err := s.accountRepo.GetAvailableCents(ctx, tx, fromAccount.ID)
 	if err != nil {
 		return nil, err
 	}
-	if balanceCents < fromAmountCents {
-		s.logger.Warn("insufficient funds for exchange", "userID", userID, "available", balanceCents, "required", fromAmountCents)
+	if availableCents < pricing.FromAmountCents {
+		s.logger.Warn("insufficient funds", "userID", fromUserID, "available", availableCents, "required", pricing.FromAmountCents)
 		return nil, errorsx.ErrInsufficientFunds
 	}
 
+	rate := pricing.Rate
 	transaction := &models.Transaction{
-		Type:        models.TransactionTypeExchange,
-		FromUserID:  userID,
-		Currency:    fromCurrency,
-		AmountCents: fromAmountCents,
-		Description: fmt.Sprintf("Exchange %d cents %s to %d cents %s (rate: %d/%d)", fromAmountCents, fromCurrency, toAmountCents, toCurrency, rateNum, rateDenom),
+		Type:          models.TransactionTypeTransfer,
+		FromUserID:    fromUserID,
+		ToUserID:      &toUser.ID,
+		Currency:      pricing.FromCurrency,
+		AmountCents:   pricing.FromAmountCents,
+		ToCurrency:    &pricing.ToCurrency,
+		ToAmountCents: &pricing.ToAmountCents,
+		Description: fmt.Sprintf("%s: %d cents %s to %d cents %s (rate: %d/%d, fee: %d cents)",
+			transferDescription(toUser, toAccount), pricing.FromAmountCents, pricing.FromCurrency,
+			pricing.ToAmountCents, pricing.ToCurrency, rate.RateNum, rate.RateDenom, pricing.FeeCents),
+		FXRateID:    &rate.ID,
+		FXRateNum:   &rate.RateNum,
+		FXRateDenom: &rate.RateDenom,
+		FeeCents:    pricing.FeeCents,
 	}
 
 	if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
 		return nil, err
 	}
 
-	entries := []*models.LedgerEntry{
-		{
-			TransactionID: transaction.ID,
-			AccountID:     fromAccount.ID,
-			Currency:      fromCurrency,
-			AmountCents:   -fromAmountCents,
-		},
-		{
-			TransactionID: transaction.ID,
-			AccountID:     fxFromAccount.ID,
-			Currency:      fromCurrency,
-			AmountCents:   fromAmountCents,
-		},
-		{
-			TransactionID: transaction.ID,
-			AccountID:     fxToAccount.ID,
-			Currency:      toCurrency,
-			AmountCents:   -toAmountCents,
-		},
-		{
-			TransactionID: transaction.ID,
-			AccountID:     toAccount.ID,
-			Currency:      toCurrency,
-			AmountCents:   toAmountCents,
-		},
+	entries := conversionEntries(transaction.ID, fromAccount.ID, toAccount.ID, fxAccounts, pricing)
+	if err := s.postLedgerEntries(ctx, tx, entries); err != nil {
+		return nil, err
 	}
 
-	for _, entry := range entries {
-		if err := s.transactionRepo.CreateLedgerEntry(ctx, tx, entry); err != nil {
-			return nil, err
-		}
+	if err := s.recordRounding(ctx, tx, transaction.ID, pricing); err != nil {
+		return nil, err
 	}
-
-	if err := s.accountRepo.UpdateBalanceCents(ctx, tx, fromAccount.ID, -fromAmountCents); err != nil {
+	if err := runBeforeCommit(hook, tx, transaction); err != nil {
 		return nil, err
 	}
-	if err := s.accountRepo.UpdateBalanceCents(ctx, tx, fxFromAccount.ID, fromAmountCents); err != nil {
+
+	if err := tx.Commit(); err != nil {
+		s.logger.Error("failed to commit transfer", "error", err)
+		return nil, fmt.Errorf("error committing transfer: %w", err)
+	}
+
+	s.logger.Info("cross-currency transfer completed", "transactionID", transaction.ID, "fromAccount", fromAccount.ID, "toAccount", toAccount.ID,
+		"fromCurrency", pricing.FromCurrency, "toCurrency", pricing.ToCurrency, "amountCents", pricing.FromAmountCents, "toAmountCents", pricing.ToAmountCents)
+	return transaction, nil
+}
+
+func (s *TransactionService) Exchange(ctx context.Context, userID string, req dto.ExchangeRequest) (*models.Transaction, error) {
+	return s.exchange(ctx, userID, req, nil)
+}
+
+func (s *TransactionService) exchange(ctx context.Context, userID string, req dto.ExchangeRequest, hook beforeCommit) (*models.Transaction, error) {
+	now := time.Now().UTC()
+
+	tx, err := s.transactionRepo.BeginTx(ctx)
+	if err != nil {
 		return nil, err
 	}
-	if err := s.accountRepo.UpdateBalanceCents(ctx, tx, fxToAccount.ID, -toAmountCents); err != nil {
+	defer tx.Rollback()
+
+	var pricing *exchangePricing
+	var quote *models.FXQuote
+	if req.QuoteID != "" {
+		quote, err = s.quoteRepo.FindByIDForUpdate(ctx, tx, req.QuoteID)
+		if err != nil {
+			return nil, err
+		}
+		if err := validateQuote(quote, userID, req, now); err != nil {
+			return nil, err
+		}
+		pricing, err = s.pricingFromQuote(ctx, quote)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		pricing, err = s.priceExchangeRequest(ctx, req.FromCurrency, req.ToCurrency, req.AmountCents, req.ToAmountCents, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	transaction, err := s.postExchange(ctx, tx, userID, pricing, 0)
+	if err != nil {
 		return nil, err
 	}
-	if err := s.accountRepo.UpdateBalanceCents(ctx, tx, toAccount.ID, toAmountCents); err != nil {
+
+	if quote != nil {
+		if err := s.quoteRepo.MarkUsed(ctx, tx, quote.ID, transaction.ID); err != nil {
+			return nil, err
+		}
+	}
+	if err := runBeforeCommit(hook, tx, transaction); err != nil {
 		return nil, err
 	}
 
@@ -281,10 +414,113 @@ func (s *TransactionService) Exchange(ctx context.Context, userID string, req dt
 		return nil, fmt.Errorf("error committing exchange: %w", err)
 	}
 
-	s.logger.Info("exchange completed", "transactionID", transaction.ID, "from", fromCurrency, "to", toCurrency, "amountCents", fromAmountCents)
+	s.logger.Info("exchange completed", "transactionID", transaction.ID, "from", pricing.FromCurrency, "to", pricing.ToCurrency, "amountCents", pricing.FromAmountCents)
 	return transaction, nil
 }
 
+// postExchange converts between two of the user's accounts inside tx.
+// reservedCents is released from the source account before the funds check,
+// so a limit order can spend the amount it reserved at placement.
+func (s *TransactionService) postExchange(ctx context.Context, tx *sqlx.Tx, userID string, pricing *exchangePricing, reservedCents int64) (*models.Transaction, error) {
+	fromCurrency := pricing.FromCurrency
+	toCurrency := pricing.ToCurrency
+	fromAmountCents := pricing.FromAmountCents
+	toAmountCents := pricing.ToAmountCents
+	rate := pricing.Rate
+
+	fromAccount, err := s.accountRepo.FindByUserAndCurrency(ctx, userID, fromCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	toAccount, err := s.accountRepo.FindByUserAndCurrency(ctx, userID, toCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkAccountStatus(fromAccount, true); err != nil {
+		return nil, err
+	}
+	if err := checkAccountStatus(toAccount, false); err != nil {
+		return nil, err
+	}
+
+	fxAccounts, err := s.findConversionAccounts(ctx, pricing)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.accountRepo.LockAccountsForUpdate(ctx, tx, append([]string{
+		fromAccount.ID,
+		toAccount.ID,
+	}, fxAccounts.IDs()...)); err != nil {
+		return nil, err
+	}
+
+	if reservedCents > 0 {
+		if err := s.accountRepo.UpdateReservedCents(ctx, tx, fromAccount.ID, -reservedCents); err != nil {
+			return nil, err
+		}
+	}
+
+	availableCents, err := s.accountRepo.GetAvailableCents(ctx, tx, fromAccount.ID)
+	if err != nil {
+		return nil, err
+	}
+	if availableCents < fromAmountCents {
+		s.logger.Warn("insufficient funds for exchange", "userID", userID, "available", availableCents, "required", fromAmountCents)
+		return nil, errorsx.ErrInsufficientFunds
+	}
+
+	transaction := &models.Transaction{
+		Type:          models.TransactionTypeExchange,
+		FromUserID:    userID,
+		Currency:      fromCurrency,
+		AmountCents:   fromAmountCents,
+		ToCurrency:    &pricing.ToCurrency,
+		ToAmountCents: &pricing.ToAmountCents,
+		Description:   fmt.Sprintf("Exchange %d cents %s to %d cents %s (rate: %d/%d, fee: %d cents)", fromAmountCents, fromCurrency, toAmountCents, toCurrency, rate.RateNum, rate.RateDenom, pricing.FeeCents),
+		FXRateID:      &rate.ID,
+		FXRateNum:     &rate.RateNum,
+		FXRateDenom:   &rate.RateDenom,
+		FeeCents:      pricing.FeeCents,
+	}
+
+	if err := s.transactionRepo.Create(ctx, tx, transaction); err != nil {
+		return nil, err
+	}
+
+	entries := conversionEntries(transaction.ID, fromAccount.ID, toAccount.ID, fxAccounts, pricing)
+	if err := s.postLedgerEntries(ctx, tx, entries); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordRounding(ctx, tx, transaction.ID, pricing); err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+func runBeforeCommit(hook beforeCommit, tx *sqlx.Tx, transaction *models.Transaction) error {
+	if hook == nil {
+		return nil
+	}
+	return hook(tx, transaction)
+}
+
+func (s *TransactionService) postLedgerEntries(ctx context.Context, tx *sqlx.Tx, entries []*models.LedgerEntry) error {
+	for _, entry := range entries {
+		if err := s.transactionRepo.CreateLedgerEntry(ctx, tx, entry); err != nil {
+			return err
+		}
+		if err := s.accountRepo.UpdateBalanceCents(ctx, tx, entry.AccountID, entry.AmountCents); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *TransactionService) GetTransactions(ctx context.Context, userID, transactionType string, page, limit int) ([]models.Transaction, int, error) {
 	transactions, total, err := s.transactionRepo.FindByUserID(ctx, userID, transactionType, page, limit)
 	if err != nil {
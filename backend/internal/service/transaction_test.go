@@ -2,13 +2,18 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"mini-banking-platform/internal/errorsx"
 	"mini-banking-platform/internal/http/dto"
 	"mini-banking-platform/internal/models"
 	"mini-banking-platform/internal/repository"
+	"mini-banking-platform/pkg/accountnumber"
+	"mini-banking-platform/pkg/decimal"
+	"mini-banking-platform/pkg/interest"
 	"os"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -59,13 +64,29 @@ func getEnv(key, defaultValue string) string {
 }
 
 func cleanupTestData(t *testing.T, db *sqlx.DB) {
-	tables := []string{"ledger_entries", "transactions", "accounts", "users"}
+	tables := []string{"ledger_entries", "transactions", "accounts", "users", "fx_spreads", "rounding_suspense"}
 	for _, table := range tables {
 		_, err := db.Exec(fmt.Sprintf("DELETE FROM %s", table))
 		if err != nil {
 			t.Logf("Warning: failed to clean %s: %v", table, err)
 		}
 	}
+	if _, err := db.Exec("DELETE FROM fx_reference_rates"); err != nil {
+		t.Logf("Warning: failed to clean fx_reference_rates: %v", err)
+	}
+	if _, err := db.Exec("DELETE FROM fx_rates WHERE source <> 'seed'"); err != nil {
+		t.Logf("Warning: failed to clean fx_rates: %v", err)
+	}
+}
+
+func newTestTransactionService(repos *repository.Repositories, logger *slog.Logger) *TransactionService {
+	return newTestTransactionServiceWithRounding(repos, logger, decimal.RoundFloor)
+}
+
+func newTestTransactionServiceWithRounding(repos *repository.Repositories, logger *slog.Logger, mode decimal.RoundingMode) *TransactionService {
+	rates := NewFXRateService(repos.FXRate, repos.FXSpread, repos.Rounding, repos.Currency, repos.Transaction, logger)
+	interestService := NewInterestService(repos.Interest, repos.Account, repos.Transaction, interest.Actual365, logger)
+	return NewTransactionService(repos.Account, repos.Transaction, repos.User, repos.Currency, repos.FXQuote, repos.FXSpread, repos.Rounding, repos.Scheduled, repos.Standing, rates, interestService, mode, logger)
 }
 
 func createTestUser(t *testing.T, db *sqlx.DB, email string) *models.User {
@@ -126,25 +147,41 @@ func createFXSystemAccounts(t *testing.T, db *sqlx.DB) {
 		t.Fatalf("Failed to create FX system user: %v", err)
 	}
 
-	accountQuery := `INSERT INTO accounts (user_id, currency, balance_cents, allow_negative)
-                     VALUES ($1, $2, $3, $4)`
-	_, err = db.Exec(accountQuery, models.FXSystemUserID, models.CurrencyUSD, 0, true)
+	accountQuery := `INSERT INTO accounts (user_id, currency, balance_cents, credit_limit_cents)
+                     VALUES ($1, $2, $3, NULL)`
+	_, err = db.Exec(accountQuery, models.FXSystemUserID, models.CurrencyUSD, 0)
 	if err != nil {
 		t.Fatalf("Failed to create FX USD account: %v", err)
 	}
-	_, err = db.Exec(accountQuery, models.FXSystemUserID, models.CurrencyEUR, 0, true)
+	_, err = db.Exec(accountQuery, models.FXSystemUserID, models.CurrencyEUR, 0)
 	if err != nil {
 		t.Fatalf("Failed to create FX EUR account: %v", err)
 	}
 }
 
+func createRevenueSystemAccounts(t *testing.T, db *sqlx.DB) {
+	userQuery := `INSERT INTO users (id, email, password, first_name, last_name) VALUES ($1, $2, $3, $4, $5)`
+	_, err := db.Exec(userQuery, models.RevenueSystemUserID, models.RevenueSystemUserEmail, "N/A", "Revenue", "System")
+	if err != nil {
+		t.Fatalf("Failed to create revenue system user: %v", err)
+	}
+
+	accountQuery := `INSERT INTO accounts (user_id, currency, balance_cents, credit_limit_cents)
+                     VALUES ($1, $2, 0, 0)`
+	for _, currency := range []string{models.CurrencyUSD, models.CurrencyEUR} {
+		if _, err := db.Exec(accountQuery, models.RevenueSystemUserID, currency); err != nil {
+			t.Fatalf("Failed to create revenue %s account: %v", currency, err)
+		}
+	}
+}
+
 func TestTransfer_Success(t *testing.T) {
 	db := setupTestDB(t)
 	defer cleanupTestData(t, db)
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 	repos := repository.NewRepositories(db, logger)
-	service := NewTransactionService(repos.Account, repos.Transaction, repos.User, logger)
+	service := newTestTransactionService(repos, logger)
 
 	userA := createTestUser(t, db, "usera@test.com")
 	userB := createTestUser(t, db, "userb@test.com")
@@ -187,7 +224,7 @@ func TestTransfer_InsufficientFunds(t *testing.T) {
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 	repos := repository.NewRepositories(db, logger)
-	service := NewTransactionService(repos.Account, repos.Transaction, repos.User, logger)
+	service := newTestTransactionService(repos, logger)
 
 	userA := createTestUser(t, db, "usera@test.com")
 	userB := createTestUser(t, db, "userb@test.com")
@@ -218,7 +255,7 @@ func TestTransfer_LedgerBalance(t *testing.T) {
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 	repos := repository.NewRepositories(db, logger)
-	service := NewTransactionService(repos.Account, repos.Transaction, repos.User, logger)
+	service := newTestTransactionService(repos, logger)
 
 	userA := createTestUser(t, db, "usera@test.com")
 	userB := createTestUser(t, db, "userb@test.com")
@@ -271,7 +308,7 @@ func TestConcurrentTransfers(t *testing.T) {
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 	repos := repository.NewRepositories(db, logger)
-	service := NewTransactionService(repos.Account, repos.Transaction, repos.User, logger)
+	service := newTestTransactionService(repos, logger)
 
 	userA := createTestUser(t, db, "usera@test.com")
 	userB := createTestUser(t, db, "userb@test.com")
@@ -322,7 +359,7 @@ func TestConcurrentOppositeTransfers_NoDeadlock(t *testing.T) {
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 	repos := repository.NewRepositories(db, logger)
-	service := NewTransactionService(repos.Account, repos.Transaction, repos.User, logger)
+	service := newTestTransactionService(repos, logger)
 
 	userA := createTestUser(t, db, "deadlock-a@test.com")
 	userB := createTestUser(t, db, "deadlock-b@test.com")
@@ -381,7 +418,7 @@ func TestConcurrentOppositeExchange_NoDeadlock(t *testing.T) {
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 	repos := repository.NewRepositories(db, logger)
-	service := NewTransactionService(repos.Account, repos.Transaction, repos.User, logger)
+	service := newTestTransactionService(repos, logger)
 
 	createFXSystemAccounts(t, db)
 
@@ -442,7 +479,7 @@ func TestExchange_Success(t *testing.T) {
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 	repos := repository.NewRepositories(db, logger)
-	service := NewTransactionService(repos.Account, repos.Transaction, repos.User, logger)
+	service := newTestTransactionService(repos, logger)
 
 	createFXSystemAccounts(t, db)
 
@@ -491,7 +528,7 @@ func TestExchange_NoMoneyMinting(t *testing.T) {
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 	repos := repository.NewRepositories(db, logger)
-	service := NewTransactionService(repos.Account, repos.Transaction, repos.User, logger)
+	service := newTestTransactionService(repos, logger)
 
 	createFXSystemAccounts(t, db)
 
@@ -539,7 +576,7 @@ func TestExchange_MinimumAmount(t *testing.T) {
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 	repos := repository.NewRepositories(db, logger)
-	service := NewTransactionService(repos.Account, repos.Transaction, repos.User, logger)
+	service := newTestTransactionService(repos, logger)
 
 	createFXSystemAccounts(t, db)
 
@@ -569,7 +606,7 @@ func TestExchange_IntegerOverflowProtection(t *testing.T) {
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
 	repos := repository.NewRepositories(db, logger)
-	service := NewTransactionService(repos.Account, repos.Transaction, repos.User, logger)
+	service := newTestTransactionService(repos, logger)
 
 	createFXSystemAccounts(t, db)
 
@@ -588,3 +625,198 @@ func TestExchange_IntegerOverflowProtection(t *testing.T) {
 		t.Error("Expected error for amount that would cause overflow")
 	}
 }
+
+func TestTransfer_CrossCurrency(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	service := newTestTransactionService(repos, logger)
+
+	createFXSystemAccounts(t, db)
+
+	userA := createTestUser(t, db, "cross-a@test.com")
+	userB := createTestUser(t, db, "cross-b@test.com")
+	createTestAccount(t, db, userA.ID, "USD", 20000)
+	createTestAccount(t, db, userB.ID, "EUR", 0)
+
+	tx, err := service.Transfer(context.Background(), userA.ID, dto.TransferRequest{
+		ToUserID:    userB.Email,
+		Currency:    "USD",
+		ToCurrency:  "EUR",
+		AmountCents: 10000,
+	})
+	if err != nil {
+		t.Fatalf("Transfer failed: %v", err)
+	}
+	if tx.ToCurrency == nil || *tx.ToCurrency != "EUR" || tx.ToAmountCents == nil || *tx.ToAmountCents != 9200 {
+		t.Errorf("Expected recipient leg of 9200 EUR, got %v %v", tx.ToCurrency, tx.ToAmountCents)
+	}
+	if tx.FXRateID == nil {
+		t.Error("Expected applied rate to be recorded")
+	}
+
+	var balanceA, balanceB int64
+	db.Get(&balanceA, "SELECT balance_cents FROM accounts WHERE user_id = $1 AND currency = 'USD'", userA.ID)
+	db.Get(&balanceB, "SELECT balance_cents FROM accounts WHERE user_id = $1 AND currency = 'EUR'", userB.ID)
+	if balanceA != 10000 {
+		t.Errorf("Expected sender USD balance 10000, got %d", balanceA)
+	}
+	if balanceB != 9200 {
+		t.Errorf("Expected recipient EUR balance 9200, got %d", balanceB)
+	}
+
+	var sums []int64
+	db.Select(&sums, "SELECT SUM(amount_cents) FROM ledger_entries WHERE transaction_id = $1 GROUP BY currency", tx.ID)
+	if len(sums) != 2 {
+		t.Errorf("Expected entries in 2 currencies, got %d", len(sums))
+	}
+	for _, sum := range sums {
+		if sum != 0 {
+			t.Errorf("Expected ledger entries to balance per currency, got %d", sum)
+		}
+	}
+}
+
+func TestTransfer_PreferredCurrency(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	service := newTestTransactionService(repos, logger)
+
+	createFXSystemAccounts(t, db)
+
+	userA := createTestUser(t, db, "pref-a@test.com")
+	userB := createTestUser(t, db, "pref-b@test.com")
+	createTestAccount(t, db, userA.ID, "EUR", 20000)
+	createTestAccount(t, db, userB.ID, "USD", 0)
+	createTestAccount(t, db, userB.ID, "EUR", 0)
+
+	preferred := "USD"
+	if err := repos.User.SetPreferredCurrency(context.Background(), userB.ID, &preferred); err != nil {
+		t.Fatalf("SetPreferredCurrency failed: %v", err)
+	}
+
+	_, err := service.Transfer(context.Background(), userA.ID, dto.TransferRequest{
+		ToUserID:    userB.ID,
+		Currency:    "EUR",
+		AmountCents: 9200,
+	})
+	if err != nil {
+		t.Fatalf("Transfer failed: %v", err)
+	}
+
+	var balanceUSD, balanceEUR int64
+	db.Get(&balanceUSD, "SELECT balance_cents FROM accounts WHERE user_id = $1 AND currency = 'USD'", userB.ID)
+	db.Get(&balanceEUR, "SELECT balance_cents FROM accounts WHERE user_id = $1 AND currency = 'EUR'", userB.ID)
+	if balanceUSD != 10000 || balanceEUR != 0 {
+		t.Errorf("Expected recipient credited 10000 USD, got USD %d EUR %d", balanceUSD, balanceEUR)
+	}
+}
+
+func TestTransfer_ToAccountID(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	service := newTestTransactionService(repos, logger)
+	ctx := context.Background()
+
+	userA := createTestUser(t, db, "usera@test.com")
+	userB := createTestUser(t, db, "userb@test.com")
+	primaryA := createTestAccount(t, db, userA.ID, "USD", 10000)
+	createTestAccount(t, db, userB.ID, "USD", 0)
+
+	name := "Rent"
+	rent := &models.Account{UserID: userA.ID, Currency: "USD", Name: &name}
+	if err := repos.Account.Create(ctx, rent); err != nil {
+		t.Fatalf("Failed to create pocket: %v", err)
+	}
+	holidayName := "Holiday"
+	holiday := &models.Account{UserID: userB.ID, Currency: "USD", Name: &holidayName}
+	if err := repos.Account.Create(ctx, holiday); err != nil {
+		t.Fatalf("Failed to create pocket: %v", err)
+	}
+
+	_, err := service.Transfer(ctx, userA.ID, dto.TransferRequest{ToAccountID: rent.ID, Currency: "USD", AmountCents: 3000})
+	if err != nil {
+		t.Fatalf("Transfer to own pocket failed: %v", err)
+	}
+
+	_, err = service.Transfer(ctx, userA.ID, dto.TransferRequest{FromAccountID: rent.ID, ToAccountID: holiday.ID, AmountCents: 1000})
+	if err != nil {
+		t.Fatalf("Transfer from pocket failed: %v", err)
+	}
+
+	_, err = service.Transfer(ctx, userA.ID, dto.TransferRequest{FromAccountID: rent.ID, ToAccountID: rent.ID, AmountCents: 100})
+	if err != errorsx.ErrCannotTransferToSelf {
+		t.Errorf("Expected ErrCannotTransferToSelf, got %v", err)
+	}
+
+	_, err = service.Transfer(ctx, userB.ID, dto.TransferRequest{FromAccountID: rent.ID, ToAccountID: holiday.ID, AmountCents: 100})
+	if err != errorsx.ErrAccountNotFound {
+		t.Errorf("Expected ErrAccountNotFound for someone else's account, got %v", err)
+	}
+
+	balances := map[string]int64{primaryA.ID: 7000, rent.ID: 2000, holiday.ID: 1000}
+	for id, want := range balances {
+		var got int64
+		db.Get(&got, "SELECT balance_cents FROM accounts WHERE id = $1", id)
+		if got != want {
+			t.Errorf("Expected account %s balance %d, got %d", id, want, got)
+		}
+	}
+}
+
+func TestTransfer_ToAccountNumber(t *testing.T) {
+	db := setupTestDB(t)
+	defer cleanupTestData(t, db)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repos := repository.NewRepositories(db, logger)
+	service := newTestTransactionService(repos, logger)
+	ctx := context.Background()
+
+	userA := createTestUser(t, db, "usera@test.com")
+	userB := createTestUser(t, db, "userb@test.com")
+	primaryA := createTestAccount(t, db, userA.ID, "USD", 10000)
+
+	name := "Savings jar"
+	jar := &models.Account{UserID: userB.ID, Currency: "USD", Name: &name}
+	if err := repos.Account.Create(ctx, jar); err != nil {
+		t.Fatalf("Failed to create pocket: %v", err)
+	}
+	if err := accountnumber.Validate(jar.AccountNumber); err != nil {
+		t.Fatalf("Generated account number %q is invalid: %v", jar.AccountNumber, err)
+	}
+
+	typed := strings.ToLower(accountnumber.Format(jar.AccountNumber))
+	if _, err := service.Transfer(ctx, userA.ID, dto.TransferRequest{ToAccountNumber: typed, Currency: "USD", AmountCents: 2500}); err != nil {
+		t.Fatalf("Transfer to account number failed: %v", err)
+	}
+
+	// Swapping two digits is caught by the check digits.
+	n := jar.AccountNumber
+	mistyped := n[:len(n)-2] + string(n[len(n)-1]) + string(n[len(n)-2])
+	if mistyped != n {
+		_, err := service.Transfer(ctx, userA.ID, dto.TransferRequest{ToAccountNumber: mistyped, Currency: "USD", AmountCents: 100})
+		var pub *errorsx.PublicError
+		if !errors.As(err, &pub) {
+			t.Errorf("Expected a mistyped account number to be rejected, got %v", err)
+		}
+	}
+
+	var got int64
+	db.Get(&got, "SELECT balance_cents FROM accounts WHERE id = $1", jar.ID)
+	if got != 2500 {
+		t.Errorf("Expected pocket balance 2500, got %d", got)
+	}
+	db.Get(&got, "SELECT balance_cents FROM accounts WHERE id = $1", primaryA.ID)
+	if got != 7500 {
+		t.Errorf("Expected sender balance 7500, got %d", got)
+	}
+}
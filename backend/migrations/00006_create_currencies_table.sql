@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS currencies (
+    code VARCHAR(3) PRIMARY KEY CHECK (code ~ '^[A-Z]{3}$'),
+    minor_units SMALLINT NOT NULL CHECK (minor_units BETWEEN 0 AND 4),
+    symbol VARCHAR(8) NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO currencies (code, minor_units, symbol, enabled)
+VALUES
+  ('USD', 2, '$', TRUE),
+  ('EUR', 2, '€', TRUE),
+  ('GBP', 2, '£', FALSE),
+  ('CHF', 2, 'CHF', FALSE),
+  ('JPY', 0, '¥', FALSE)
+ON CONFLICT (code) DO NOTHING;
+
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_currency_check;
+ALTER TABLE accounts
+  ADD CONSTRAINT accounts_currency_fkey
+  FOREIGN KEY (currency) REFERENCES currencies(code);
+
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_currency_check;
+ALTER TABLE ledger_entries
+  ADD CONSTRAINT ledger_entries_currency_fkey
+  FOREIGN KEY (currency) REFERENCES currencies(code);
+
+ALTER TABLE transactions
+  ADD CONSTRAINT transactions_currency_fkey
+  FOREIGN KEY (currency) REFERENCES currencies(code);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_currency_fkey;
+
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_currency_fkey;
+ALTER TABLE ledger_entries
+  ADD CONSTRAINT ledger_entries_currency_check CHECK (currency IN ('USD', 'EUR'));
+
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_currency_fkey;
+ALTER TABLE accounts
+  ADD CONSTRAINT accounts_currency_check CHECK (currency IN ('USD', 'EUR'));
+
+DROP TABLE IF EXISTS currencies;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS fx_rates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    base_currency VARCHAR(3) NOT NULL REFERENCES currencies(code),
+    quote_currency VARCHAR(3) NOT NULL REFERENCES currencies(code),
+    rate_num BIGINT NOT NULL CHECK (rate_num > 0),
+    rate_denom BIGINT NOT NULL CHECK (rate_denom > 0),
+    valid_from TIMESTAMP NOT NULL,
+    source VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (base_currency <> quote_currency)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fx_rates_pair_valid_from ON fx_rates(base_currency, quote_currency, valid_from DESC);
+
+INSERT INTO fx_rates (base_currency, quote_currency, rate_num, rate_denom, valid_from, source)
+VALUES
+  ('USD', 'EUR', 23, 25, '1970-01-01 00:00:00', 'seed'),
+  ('EUR', 'USD', 25, 23, '1970-01-01 00:00:00', 'seed');
+
+ALTER TABLE transactions
+  ADD COLUMN IF NOT EXISTS fx_rate_id UUID REFERENCES fx_rates(id),
+  ADD COLUMN IF NOT EXISTS fx_rate_num BIGINT,
+  ADD COLUMN IF NOT EXISTS fx_rate_denom BIGINT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE transactions
+  DROP COLUMN IF EXISTS fx_rate_id,
+  DROP COLUMN IF EXISTS fx_rate_num,
+  DROP COLUMN IF EXISTS fx_rate_denom;
+
+DROP TABLE IF EXISTS fx_rates;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS fx_quotes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_currency VARCHAR(3) NOT NULL REFERENCES currencies(code),
+    to_currency VARCHAR(3) NOT NULL REFERENCES currencies(code),
+    from_amount_cents BIGINT NOT NULL CHECK (from_amount_cents > 0),
+    to_amount_cents BIGINT NOT NULL CHECK (to_amount_cents >= 0),
+    fx_rate_id UUID NOT NULL REFERENCES fx_rates(id),
+    rate_num BIGINT NOT NULL,
+    rate_denom BIGINT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_fx_quotes_user_id ON fx_quotes(user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS fx_quotes;
+-- +goose StatementEnd
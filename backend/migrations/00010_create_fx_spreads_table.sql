@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS fx_spreads (
+    base_currency VARCHAR(3) NOT NULL REFERENCES currencies(code),
+    quote_currency VARCHAR(3) NOT NULL REFERENCES currencies(code),
+    bid_bps INTEGER NOT NULL DEFAULT 0 CHECK (bid_bps BETWEEN 0 AND 10000),
+    ask_bps INTEGER NOT NULL DEFAULT 0 CHECK (ask_bps BETWEEN 0 AND 10000),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (base_currency, quote_currency),
+    CHECK (base_currency <> quote_currency)
+);
+
+ALTER TABLE transactions
+  ADD COLUMN IF NOT EXISTS fee_cents BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE fx_quotes
+  ADD COLUMN IF NOT EXISTS fee_cents BIGINT NOT NULL DEFAULT 0;
+
+INSERT INTO users (id, email, password, first_name, last_name)
+VALUES ('00000000-0000-0000-0000-000000000002', 'revenue@system.local', 'N/A', 'Platform', 'Revenue')
+ON CONFLICT (email) DO NOTHING;
+
+INSERT INTO accounts (user_id, currency, balance_cents)
+VALUES
+  ('00000000-0000-0000-0000-000000000002', 'USD', 0),
+  ('00000000-0000-0000-0000-000000000002', 'EUR', 0)
+ON CONFLICT (user_id, currency) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM users WHERE id = '00000000-0000-0000-0000-000000000002';
+ALTER TABLE fx_quotes DROP COLUMN IF EXISTS fee_cents;
+ALTER TABLE transactions DROP COLUMN IF EXISTS fee_cents;
+DROP TABLE IF EXISTS fx_spreads;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE fx_quotes
+  ADD COLUMN IF NOT EXISTS rounding_mode VARCHAR(16) NOT NULL DEFAULT 'floor';
+
+CREATE TABLE IF NOT EXISTS fx_rounding_entries (
+    transaction_id UUID PRIMARY KEY REFERENCES transactions(id) ON DELETE CASCADE,
+    currency VARCHAR(3) NOT NULL REFERENCES currencies(code),
+    rounding_mode VARCHAR(16) NOT NULL,
+    remainder_num BIGINT NOT NULL,
+    remainder_denom BIGINT NOT NULL CHECK (remainder_denom > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_fx_rounding_entries_currency ON fx_rounding_entries(currency);
+
+CREATE TABLE IF NOT EXISTS rounding_suspense (
+    currency VARCHAR(3) PRIMARY KEY REFERENCES currencies(code),
+    remainder_num NUMERIC NOT NULL DEFAULT 0,
+    remainder_denom NUMERIC NOT NULL DEFAULT 1 CHECK (remainder_denom > 0),
+    exchange_count BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS rounding_suspense;
+DROP TABLE IF EXISTS fx_rounding_entries;
+ALTER TABLE fx_quotes DROP COLUMN IF EXISTS rounding_mode;
+-- +goose StatementEnd
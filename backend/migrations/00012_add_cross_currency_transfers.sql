@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS preferred_currency VARCHAR(3) REFERENCES currencies(code);
+
+ALTER TABLE transactions
+  ADD COLUMN IF NOT EXISTS to_currency VARCHAR(3) REFERENCES currencies(code),
+  ADD COLUMN IF NOT EXISTS to_amount_cents BIGINT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE transactions
+  DROP COLUMN IF EXISTS to_amount_cents,
+  DROP COLUMN IF EXISTS to_currency;
+
+ALTER TABLE users DROP COLUMN IF EXISTS preferred_currency;
+-- +goose StatementEnd
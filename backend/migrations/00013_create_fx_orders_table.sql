@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE accounts
+  ADD COLUMN IF NOT EXISTS reserved_cents BIGINT NOT NULL DEFAULT 0 CHECK (reserved_cents >= 0);
+
+CREATE TABLE IF NOT EXISTS fx_orders (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_currency VARCHAR(3) NOT NULL REFERENCES currencies(code),
+    to_currency VARCHAR(3) NOT NULL REFERENCES currencies(code),
+    amount_cents BIGINT NOT NULL CHECK (amount_cents > 0),
+    target_rate VARCHAR(32) NOT NULL,
+    target_rate_num BIGINT NOT NULL CHECK (target_rate_num > 0),
+    target_rate_denom BIGINT NOT NULL CHECK (target_rate_denom > 0),
+    status VARCHAR(16) NOT NULL DEFAULT 'open'
+        CHECK (status IN ('open', 'filled', 'cancelled', 'expired', 'failed')),
+    expires_at TIMESTAMP NOT NULL,
+    transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+    failure_reason TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (from_currency <> to_currency)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fx_orders_user_id ON fx_orders(user_id);
+CREATE INDEX IF NOT EXISTS idx_fx_orders_open ON fx_orders(from_currency, to_currency) WHERE status = 'open';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS fx_orders;
+ALTER TABLE accounts DROP COLUMN IF EXISTS reserved_cents;
+-- +goose StatementEnd
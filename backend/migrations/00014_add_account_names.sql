@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_user_id_currency_key;
+
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS name VARCHAR(50);
+
+-- Unnamed accounts are the primary account per currency; named pockets are
+-- unique per user regardless of currency.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_accounts_primary ON accounts(user_id, currency) WHERE name IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_accounts_user_name ON accounts(user_id, LOWER(name)) WHERE name IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_accounts_user_name;
+DROP INDEX IF EXISTS idx_accounts_primary;
+ALTER TABLE accounts DROP COLUMN IF EXISTS name;
+ALTER TABLE accounts ADD CONSTRAINT accounts_user_id_currency_key UNIQUE (user_id, currency);
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A NULL credit limit means unlimited and is reserved for system accounts.
+ALTER TABLE accounts
+  ADD COLUMN IF NOT EXISTS credit_limit_cents BIGINT DEFAULT 0
+    CHECK (credit_limit_cents IS NULL OR credit_limit_cents >= 0);
+
+UPDATE accounts SET credit_limit_cents = NULL WHERE allow_negative = TRUE;
+
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_balance_cents_check;
+ALTER TABLE accounts DROP COLUMN IF EXISTS allow_negative;
+
+ALTER TABLE accounts
+  ADD CONSTRAINT accounts_balance_cents_check
+  CHECK (credit_limit_cents IS NULL OR balance_cents >= -credit_limit_cents);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE accounts
+  ADD COLUMN IF NOT EXISTS allow_negative BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE accounts SET allow_negative = TRUE WHERE credit_limit_cents IS NULL;
+
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_balance_cents_check;
+ALTER TABLE accounts DROP COLUMN IF EXISTS credit_limit_cents;
+
+ALTER TABLE accounts
+  ADD CONSTRAINT accounts_balance_cents_check
+  CHECK (balance_cents >= 0 OR allow_negative = TRUE);
+-- +goose StatementEnd
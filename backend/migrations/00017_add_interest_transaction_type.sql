@@ -0,0 +1,7 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+ALTER TYPE transaction_type ADD VALUE IF NOT EXISTS 'interest';
+
+-- +goose Down
+-- Enum values cannot be dropped; 'interest' stays in transaction_type.
+SELECT 1;
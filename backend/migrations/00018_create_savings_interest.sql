@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE accounts
+  ADD COLUMN IF NOT EXISTS type VARCHAR(16) NOT NULL DEFAULT 'current' CHECK (type IN ('current', 'savings')),
+  ADD COLUMN IF NOT EXISTS interest_rate VARCHAR(32),
+  ADD COLUMN IF NOT EXISTS interest_rate_num BIGINT,
+  ADD COLUMN IF NOT EXISTS interest_rate_denom BIGINT,
+  ADD COLUMN IF NOT EXISTS interest_carry_micros BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS interest_accruals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    accrual_date DATE NOT NULL,
+    balance_cents BIGINT NOT NULL,
+    rate_num BIGINT NOT NULL,
+    rate_denom BIGINT NOT NULL,
+    day_count VARCHAR(8) NOT NULL,
+    amount_micros BIGINT NOT NULL,
+    transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+    capitalized_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (account_id, accrual_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_interest_accruals_pending ON interest_accruals(account_id) WHERE capitalized_at IS NULL;
+
+INSERT INTO users (id, email, password, first_name, last_name)
+VALUES ('00000000-0000-0000-0000-000000000003', 'interest@system.local', 'N/A', 'Interest', 'Expense')
+ON CONFLICT (email) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS interest_accruals;
+DELETE FROM users WHERE id = '00000000-0000-0000-0000-000000000003';
+ALTER TABLE accounts
+  DROP COLUMN IF EXISTS interest_carry_micros,
+  DROP COLUMN IF EXISTS interest_rate_denom,
+  DROP COLUMN IF EXISTS interest_rate_num,
+  DROP COLUMN IF EXISTS interest_rate,
+  DROP COLUMN IF EXISTS type;
+-- +goose StatementEnd
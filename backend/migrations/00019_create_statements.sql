@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS statements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    period VARCHAR(7) NOT NULL,
+    period_start TIMESTAMP NOT NULL,
+    period_end TIMESTAMP NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    opening_balance_cents BIGINT NOT NULL,
+    closing_balance_cents BIGINT NOT NULL,
+    total_credits_cents BIGINT NOT NULL,
+    total_debits_cents BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (account_id, period)
+);
+
+CREATE TABLE IF NOT EXISTS statement_lines (
+    statement_id UUID NOT NULL REFERENCES statements(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    ledger_entry_id UUID NOT NULL,
+    transaction_id UUID NOT NULL,
+    transaction_type VARCHAR(32) NOT NULL,
+    description TEXT NOT NULL,
+    amount_cents BIGINT NOT NULL,
+    balance_cents BIGINT NOT NULL,
+    posted_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (statement_id, position)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS statement_lines;
+DROP TABLE IF EXISTS statements;
+-- +goose StatementEnd
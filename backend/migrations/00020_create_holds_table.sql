@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS holds (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    payee_account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    currency VARCHAR(3) NOT NULL REFERENCES currencies(code),
+    amount_cents BIGINT NOT NULL CHECK (amount_cents > 0),
+    captured_cents BIGINT NOT NULL DEFAULT 0 CHECK (captured_cents >= 0),
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'captured', 'released', 'expired')),
+    expires_at TIMESTAMP NOT NULL,
+    transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (captured_cents <= amount_cents),
+    CHECK (account_id <> payee_account_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_holds_account_id ON holds(account_id);
+CREATE INDEX IF NOT EXISTS idx_holds_payee_account_id ON holds(payee_account_id);
+CREATE INDEX IF NOT EXISTS idx_holds_active_expiry ON holds(expires_at) WHERE status = 'active';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS holds;
+-- +goose StatementEnd
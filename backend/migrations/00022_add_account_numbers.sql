@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE SEQUENCE IF NOT EXISTS account_number_seq;
+
+-- MB + ISO 7064 mod 97-10 check digits + 16-digit serial; mirrors
+-- pkg/accountnumber. "MB00" moved to the end reads 221100 (M=22, B=11).
+CREATE OR REPLACE FUNCTION next_account_number() RETURNS VARCHAR AS $$
+DECLARE
+    bban TEXT := lpad(nextval('account_number_seq')::TEXT, 16, '0');
+BEGIN
+    RETURN 'MB' || lpad((98 - (bban || '221100')::NUMERIC % 97)::TEXT, 2, '0') || bban;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS account_number VARCHAR(20);
+UPDATE accounts SET account_number = next_account_number() WHERE account_number IS NULL;
+ALTER TABLE accounts ALTER COLUMN account_number SET DEFAULT next_account_number();
+ALTER TABLE accounts ALTER COLUMN account_number SET NOT NULL;
+ALTER TABLE accounts ADD CONSTRAINT accounts_account_number_key UNIQUE (account_number);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE accounts DROP COLUMN IF EXISTS account_number;
+DROP FUNCTION IF EXISTS next_account_number();
+DROP SEQUENCE IF EXISTS account_number_seq;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Closed accounts keep their history but no longer hold the user's primary
+-- slot in the currency or the pocket name, so either can be opened again.
+DROP INDEX IF EXISTS idx_accounts_primary;
+DROP INDEX IF EXISTS idx_accounts_user_name;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_accounts_primary ON accounts(user_id, currency)
+    WHERE name IS NULL AND status <> 'closed';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_accounts_user_name ON accounts(user_id, LOWER(name))
+    WHERE name IS NOT NULL AND status <> 'closed';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_accounts_user_name;
+DROP INDEX IF EXISTS idx_accounts_primary;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_accounts_primary ON accounts(user_id, currency) WHERE name IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_accounts_user_name ON accounts(user_id, LOWER(name)) WHERE name IS NOT NULL;
+-- +goose StatementEnd
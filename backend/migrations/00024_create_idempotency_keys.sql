@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- transaction_id stays NULL while the first request with the key is running.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    idempotency_key VARCHAR(255) NOT NULL,
+    endpoint VARCHAR(32) NOT NULL,
+    request_hash VARCHAR(64) NOT NULL,
+    transaction_id UUID REFERENCES transactions(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, idempotency_key)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS idempotency_keys;
+-- +goose StatementEnd
@@ -0,0 +1,7 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+ALTER TYPE transaction_type ADD VALUE IF NOT EXISTS 'reversal';
+
+-- +goose Down
+-- Enum values cannot be dropped; 'reversal' stays in transaction_type.
+SELECT 1;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A reversal points at the transaction it undoes and the original points
+-- back, so either side shows the link. The unique index is what stops a
+-- transaction from being reversed twice.
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS reverses_transaction_id UUID REFERENCES transactions(id),
+    ADD COLUMN IF NOT EXISTS reversed_by_transaction_id UUID REFERENCES transactions(id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_reverses ON transactions(reverses_transaction_id)
+    WHERE reverses_transaction_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_transactions_reverses;
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS reversed_by_transaction_id,
+    DROP COLUMN IF EXISTS reverses_transaction_id;
+-- +goose StatementEnd
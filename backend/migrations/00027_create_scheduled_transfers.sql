@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The transfer request is stored as the user sent it and only resolved when
+-- it runs, so it sees the balances and accounts of the execution date.
+CREATE TABLE IF NOT EXISTS scheduled_transfers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    to_user_id VARCHAR(255) NOT NULL DEFAULT '',
+    to_account_id UUID REFERENCES accounts(id) ON DELETE CASCADE,
+    to_account_number VARCHAR(42) NOT NULL DEFAULT '',
+    from_account_id UUID REFERENCES accounts(id) ON DELETE CASCADE,
+    currency VARCHAR(3) NOT NULL DEFAULT '',
+    to_currency VARCHAR(3) NOT NULL DEFAULT '',
+    amount_cents BIGINT NOT NULL CHECK (amount_cents > 0),
+    execute_at TIMESTAMP NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'scheduled'
+        CHECK (status IN ('scheduled', 'processing', 'executed', 'failed', 'cancelled')),
+    failure_reason TEXT NOT NULL DEFAULT '',
+    transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+    executed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfers_user_id ON scheduled_transfers(user_id);
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfers_due ON scheduled_transfers(execute_at) WHERE status = 'scheduled';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS scheduled_transfers;
+-- +goose StatementEnd
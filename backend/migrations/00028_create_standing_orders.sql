@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+-- next_run_on is advanced when a run is claimed and is NULL once the order
+-- has finished. Runs are unique per date, so a date is never paid twice.
+CREATE TABLE IF NOT EXISTS standing_orders (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    to_account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    amount_cents BIGINT NOT NULL CHECK (amount_cents > 0),
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    frequency VARCHAR(16) NOT NULL CHECK (frequency IN ('weekly', 'monthly')),
+    day_of_month SMALLINT CHECK (day_of_month BETWEEN 1 AND 31),
+    month_end_rule VARCHAR(16) NOT NULL DEFAULT 'last_day'
+        CHECK (month_end_rule IN ('last_day', 'next_month')),
+    start_on DATE NOT NULL,
+    end_on DATE,
+    next_run_on DATE,
+    status VARCHAR(16) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'paused', 'completed', 'cancelled')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (from_account_id <> to_account_id),
+    CHECK (end_on IS NULL OR end_on >= start_on),
+    CHECK (frequency = 'weekly' OR day_of_month IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_standing_orders_user_id ON standing_orders(user_id);
+CREATE INDEX IF NOT EXISTS idx_standing_orders_due ON standing_orders(next_run_on) WHERE status = 'active';
+
+CREATE TABLE IF NOT EXISTS standing_order_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    standing_order_id UUID NOT NULL REFERENCES standing_orders(id) ON DELETE CASCADE,
+    run_on DATE NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'processing'
+        CHECK (status IN ('processing', 'executed', 'failed')),
+    failure_reason TEXT NOT NULL DEFAULT '',
+    transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (standing_order_id, run_on)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS standing_order_runs;
+DROP TABLE IF EXISTS standing_orders;
+-- +goose StatementEnd
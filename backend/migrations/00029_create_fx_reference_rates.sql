@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS fx_reference_rates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    base_currency VARCHAR(3) NOT NULL REFERENCES currencies(code),
+    quote_currency VARCHAR(3) NOT NULL REFERENCES currencies(code),
+    rate_num BIGINT NOT NULL CHECK (rate_num > 0),
+    rate_denom BIGINT NOT NULL CHECK (rate_denom > 0),
+    valid_from TIMESTAMP NOT NULL,
+    source VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (base_currency <> quote_currency),
+    UNIQUE (base_currency, quote_currency, valid_from, source)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fx_reference_rates_pair_valid_from ON fx_reference_rates(base_currency, quote_currency, valid_from DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS fx_reference_rates;
+-- +goose StatementEnd
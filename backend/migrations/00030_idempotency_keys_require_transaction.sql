@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Keys are now stored in the same DB transaction as the posting, so a key
+-- without a transaction can only be left over from the old flow.
+DELETE FROM idempotency_keys WHERE transaction_id IS NULL;
+ALTER TABLE idempotency_keys ALTER COLUMN transaction_id SET NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE idempotency_keys ALTER COLUMN transaction_id DROP NOT NULL;
+-- +goose StatementEnd
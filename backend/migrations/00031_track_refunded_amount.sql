@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A transaction can be refunded in several parts, so the original keeps a
+-- running total instead of relying on a single reversal link. The check is
+-- the last line of defence against refunding more than was paid.
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS refunded_cents BIGINT NOT NULL DEFAULT 0;
+
+UPDATE transactions t
+SET refunded_cents = r.total
+FROM (
+    SELECT reverses_transaction_id AS id, SUM(amount_cents) AS total
+    FROM transactions
+    WHERE reverses_transaction_id IS NOT NULL
+    GROUP BY reverses_transaction_id
+) r
+WHERE t.id = r.id;
+
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_refunded_cents_check CHECK (refunded_cents >= 0 AND refunded_cents <= amount_cents);
+
+DROP INDEX IF EXISTS idx_transactions_reverses;
+CREATE INDEX IF NOT EXISTS idx_transactions_reverses ON transactions(reverses_transaction_id)
+    WHERE reverses_transaction_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_transactions_reverses;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_reverses ON transactions(reverses_transaction_id)
+    WHERE reverses_transaction_id IS NOT NULL;
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS transactions_refunded_cents_check,
+    DROP COLUMN IF EXISTS refunded_cents;
+-- +goose StatementEnd
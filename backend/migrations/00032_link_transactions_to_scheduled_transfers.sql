@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A scheduled run writes its schedule ID onto the transaction it posts, in the
+-- same commit. If the worker dies before it records the outcome, recovery
+-- finds the posting through this column; the unique index guarantees a
+-- schedule is never posted twice.
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS scheduled_transfer_id UUID REFERENCES scheduled_transfers(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_scheduled_transfer ON transactions(scheduled_transfer_id)
+    WHERE scheduled_transfer_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_transfers_processing ON scheduled_transfers(updated_at)
+    WHERE status = 'processing';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_scheduled_transfers_processing;
+DROP INDEX IF EXISTS idx_transactions_scheduled_transfer;
+ALTER TABLE transactions DROP COLUMN IF EXISTS scheduled_transfer_id;
+-- +goose StatementEnd
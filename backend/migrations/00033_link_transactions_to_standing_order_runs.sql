@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A standing order run is marked executed in the same commit as the transfer
+-- it posts, and the transaction carries the run's ID. Recovery uses the link
+-- to settle runs a dead worker left in processing; the unique index keeps a
+-- run from ever being paid twice.
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS standing_order_run_id UUID REFERENCES standing_order_runs(id) ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_standing_order_run ON transactions(standing_order_run_id)
+    WHERE standing_order_run_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_standing_order_runs_processing ON standing_order_runs(updated_at)
+    WHERE status = 'processing';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_standing_order_runs_processing;
+DROP INDEX IF EXISTS idx_transactions_standing_order_run;
+ALTER TABLE transactions DROP COLUMN IF EXISTS standing_order_run_id;
+-- +goose StatementEnd
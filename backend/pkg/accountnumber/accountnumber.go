@@ -0,0 +1,92 @@
+// Package accountnumber implements the platform's IBAN-style account numbers:
+// the country-style prefix MB, two ISO 7064 mod 97-10 check digits and a
+// 16-digit zero-padded serial, e.g. MB34 0000 0000 0000 0001.
+package accountnumber
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	Prefix       = "MB"
+	SerialDigits = 16
+	Length       = len(Prefix) + 2 + SerialDigits
+)
+
+var (
+	ErrInvalidFormat   = errors.New("account number must be MB followed by 18 digits")
+	ErrInvalidChecksum = errors.New("account number check digits do not match")
+)
+
+// Generate returns the account number for a serial.
+func Generate(serial uint64) string {
+	bban := fmt.Sprintf("%0*d", SerialDigits, serial)
+	return fmt.Sprintf("%s%02d%s", Prefix, 98-mod97(bban+Prefix+"00"), bban)
+}
+
+// Normalize strips spaces and upper-cases the prefix, so numbers can be typed
+// in the grouped form they are displayed in.
+func Normalize(s string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(s), ""))
+}
+
+// Validate checks a normalized account number's format and check digits
+// without touching the database.
+func Validate(number string) error {
+	if len(number) != Length || !strings.HasPrefix(number, Prefix) {
+		return ErrInvalidFormat
+	}
+	for _, r := range number[len(Prefix):] {
+		if r < '0' || r > '9' {
+			return ErrInvalidFormat
+		}
+	}
+	// IBAN rule: move the first four characters to the end; the result
+	// must leave remainder 1.
+	if mod97(number[4:]+number[:4]) != 1 {
+		return ErrInvalidChecksum
+	}
+	return nil
+}
+
+// LooksLike reports whether a normalized string was meant as an account
+// number: the prefix followed by two check digits. Such strings should be
+// validated as account numbers rather than tried as anything else, so a typo
+// is reported as one.
+func LooksLike(s string) bool {
+	return len(s) >= len(Prefix)+2 && strings.HasPrefix(s, Prefix) &&
+		isDigit(s[len(Prefix)]) && isDigit(s[len(Prefix)+1])
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// Format groups a normalized account number in blocks of four for display.
+func Format(number string) string {
+	var b strings.Builder
+	for i, r := range number {
+		if i > 0 && i%4 == 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// mod97 computes the remainder piecewise, with letters expanded to two
+// digits (A=10 ... Z=35), so the number never overflows.
+func mod97(s string) int {
+	rem := 0
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			rem = (rem*10 + int(r-'0')) % 97
+		case r >= 'A' && r <= 'Z':
+			rem = (rem*100 + int(r-'A') + 10) % 97
+		}
+	}
+	return rem
+}
@@ -0,0 +1,71 @@
+package accountnumber
+
+import "testing"
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		serial uint64
+		want   string
+	}{
+		{1, "MB340000000000000001"},
+		{42, "MB910000000000000042"},
+		{9999999999999999, "MB639999999999999999"},
+	}
+	for _, tt := range tests {
+		got := Generate(tt.serial)
+		if got != tt.want {
+			t.Errorf("Generate(%d) = %s, want %s", tt.serial, got, tt.want)
+		}
+		if err := Validate(got); err != nil {
+			t.Errorf("Validate(%s) failed: %v", got, err)
+		}
+	}
+}
+
+func TestValidate_RejectsTypos(t *testing.T) {
+	tests := []struct {
+		number string
+		want   error
+	}{
+		{"MB340000000000000002", ErrInvalidChecksum},
+		{"MB430000000000000001", ErrInvalidChecksum},
+		{"MB340000000000000010", ErrInvalidChecksum},
+		{"MB34000000000000001", ErrInvalidFormat},
+		{"GB340000000000000001", ErrInvalidFormat},
+		{"MB34000000000000000O", ErrInvalidFormat},
+	}
+	for _, tt := range tests {
+		if err := Validate(tt.number); err != tt.want {
+			t.Errorf("Validate(%s) = %v, want %v", tt.number, err, tt.want)
+		}
+	}
+}
+
+func TestNormalizeAndFormat(t *testing.T) {
+	number := Normalize(" mb34 0000 0000\t0000 0001 ")
+	if number != "MB340000000000000001" {
+		t.Fatalf("Normalize = %q", number)
+	}
+	if got := Format(number); got != "MB34 0000 0000 0000 0001" {
+		t.Errorf("Format = %q", got)
+	}
+}
+
+func TestLooksLike(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{"MB340000000000000001", true},
+		{"MB34000000000000001", true},
+		{"MB34000000000000000O", true},
+		{"MB", false},
+		{"MBA@EXAMPLE.COM", false},
+		{"0F8FAD5B-D9CB-469F-A165-70867728950E", false},
+	}
+	for _, tt := range tests {
+		if got := LooksLike(tt.s); got != tt.want {
+			t.Errorf("LooksLike(%s) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package decimal
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseRational converts a positive decimal string such as "1.0956" into an
+// exact reduced fraction.
+func ParseRational(s string) (int64, int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, 0, fmt.Errorf("decimal: empty value")
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" {
+		intPart = "0"
+	}
+	if len(fracPart) > 12 {
+		return 0, 0, fmt.Errorf("decimal: too many fractional digits in %q", s)
+	}
+
+	num, err := strconv.ParseInt(intPart+fracPart, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("decimal: invalid value %q: %w", s, err)
+	}
+	if num <= 0 {
+		return 0, 0, fmt.Errorf("decimal: value must be positive: %q", s)
+	}
+
+	denom := int64(math.Pow10(len(fracPart)))
+	g := gcd(num, denom)
+	return num / g, denom / g, nil
+}
+
+// FormatRational renders num/denom with the given number of fractional digits,
+// truncating any remainder.
+func FormatRational(num, denom int64, digits int) string {
+	scale := int64(math.Pow10(digits))
+	whole := num / denom
+	frac := (num % denom) * scale / denom
+	if digits == 0 {
+		return strconv.FormatInt(whole, 10)
+	}
+	return fmt.Sprintf("%d.%0*d", whole, digits, frac)
+}
+
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
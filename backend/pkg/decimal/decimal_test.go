@@ -0,0 +1,43 @@
+package decimal
+
+import "testing"
+
+func TestParseRational(t *testing.T) {
+	cases := []struct {
+		in    string
+		num   int64
+		denom int64
+	}{
+		{"0.92", 23, 25},
+		{"1.0956", 2739, 2500},
+		{"150", 150, 1},
+		{".5", 1, 2},
+	}
+
+	for _, tc := range cases {
+		num, denom, err := ParseRational(tc.in)
+		if err != nil {
+			t.Fatalf("ParseRational(%q) failed: %v", tc.in, err)
+		}
+		if num != tc.num || denom != tc.denom {
+			t.Errorf("ParseRational(%q) = %d/%d, expected %d/%d", tc.in, num, denom, tc.num, tc.denom)
+		}
+	}
+}
+
+func TestParseRational_Invalid(t *testing.T) {
+	for _, in := range []string{"", "abc", "0", "-1.5", "1.2.3"} {
+		if _, _, err := ParseRational(in); err == nil {
+			t.Errorf("Expected error for %q", in)
+		}
+	}
+}
+
+func TestFormatRational(t *testing.T) {
+	if got := FormatRational(23, 25, 4); got != "0.9200" {
+		t.Errorf("Expected 0.9200, got %s", got)
+	}
+	if got := FormatRational(25, 23, 6); got != "1.086956" {
+		t.Errorf("Expected 1.086956, got %s", got)
+	}
+}
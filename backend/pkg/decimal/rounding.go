@@ -0,0 +1,47 @@
+package decimal
+
+import "fmt"
+
+type RoundingMode string
+
+const (
+	RoundHalfEven RoundingMode = "half_even"
+	RoundHalfUp   RoundingMode = "half_up"
+	RoundFloor    RoundingMode = "floor"
+	// RoundCeiling is not a configurable mode; it is used where an amount
+	// must always be rounded in the platform's favour.
+	RoundCeiling RoundingMode = "ceiling"
+)
+
+func ParseRoundingMode(s string) (RoundingMode, error) {
+	switch mode := RoundingMode(s); mode {
+	case RoundHalfEven, RoundHalfUp, RoundFloor:
+		return mode, nil
+	}
+	return "", fmt.Errorf("decimal: unknown rounding mode %q", s)
+}
+
+// DivRound divides a non-negative num by a positive denom and rounds the
+// quotient with mode. The remainder satisfies num = q*denom + rem, so it is
+// negative when the quotient was rounded up.
+func DivRound(num, denom int64, mode RoundingMode) (q, rem int64) {
+	q, rem = num/denom, num%denom
+	if rem == 0 {
+		return q, rem
+	}
+
+	var up bool
+	switch mode {
+	case RoundCeiling:
+		up = true
+	case RoundHalfUp:
+		up = rem >= denom-rem
+	case RoundHalfEven:
+		up = rem > denom-rem || (rem == denom-rem && q%2 == 1)
+	}
+
+	if up {
+		return q + 1, rem - denom
+	}
+	return q, rem
+}
@@ -0,0 +1,45 @@
+package decimal
+
+import "testing"
+
+func TestDivRound(t *testing.T) {
+	cases := []struct {
+		num, denom int64
+		mode       RoundingMode
+		q, rem     int64
+	}{
+		{920000, 100, RoundFloor, 9200, 0},
+		{25, 10, RoundFloor, 2, 5},
+		{25, 10, RoundHalfUp, 3, -5},
+		{25, 10, RoundHalfEven, 2, 5},
+		{35, 10, RoundHalfEven, 4, -5},
+		{24, 10, RoundHalfUp, 2, 4},
+		{26, 10, RoundHalfEven, 3, -4},
+		{29, 10, RoundFloor, 2, 9},
+		{1, 3, RoundHalfUp, 0, 1},
+		{2, 3, RoundHalfEven, 1, -1},
+		{21, 10, RoundCeiling, 3, -9},
+		{20, 10, RoundCeiling, 2, 0},
+	}
+
+	for _, tc := range cases {
+		q, rem := DivRound(tc.num, tc.denom, tc.mode)
+		if q != tc.q || rem != tc.rem {
+			t.Errorf("DivRound(%d, %d, %s) = %d r %d, expected %d r %d", tc.num, tc.denom, tc.mode, q, rem, tc.q, tc.rem)
+		}
+		if q*tc.denom+rem != tc.num {
+			t.Errorf("DivRound(%d, %d, %s) does not preserve value", tc.num, tc.denom, tc.mode)
+		}
+	}
+}
+
+func TestParseRoundingMode(t *testing.T) {
+	for _, in := range []string{"half_even", "half_up", "floor"} {
+		if _, err := ParseRoundingMode(in); err != nil {
+			t.Errorf("ParseRoundingMode(%q) failed: %v", in, err)
+		}
+	}
+	if _, err := ParseRoundingMode("ceiling"); err == nil {
+		t.Error("expected error for unknown rounding mode")
+	}
+}
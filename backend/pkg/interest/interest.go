@@ -0,0 +1,57 @@
+package interest
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// MicrosPerCent is the precision daily accruals are kept in: millionths of a
+// minor unit, so sub-cent interest is not lost between capitalizations.
+const MicrosPerCent int64 = 1_000_000
+
+type DayCount string
+
+const (
+	Actual365    DayCount = "act/365"
+	Actual360    DayCount = "act/360"
+	ActualActual DayCount = "act/act"
+)
+
+func ParseDayCount(s string) (DayCount, error) {
+	switch dc := DayCount(s); dc {
+	case Actual365, Actual360, ActualActual:
+		return dc, nil
+	}
+	return "", fmt.Errorf("interest: unknown day-count convention %q", s)
+}
+
+// DaysInYear is the denominator one day of interest is divided by. Under
+// act/act it depends on whether the day falls in a leap year.
+func (d DayCount) DaysInYear(day time.Time) int64 {
+	switch d {
+	case Actual360:
+		return 360
+	case ActualActual:
+		year := day.Year()
+		if year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+			return 366
+		}
+		return 365
+	default:
+		return 365
+	}
+}
+
+// DailyMicros returns one day of interest on balanceCents at the annual rate
+// rateNum/rateDenom, in micros, rounded down. Non-positive balances earn
+// nothing.
+func DailyMicros(balanceCents, rateNum, rateDenom, daysInYear int64) int64 {
+	if balanceCents <= 0 || rateNum <= 0 {
+		return 0
+	}
+	num := new(big.Int).Mul(big.NewInt(balanceCents), big.NewInt(rateNum))
+	num.Mul(num, big.NewInt(MicrosPerCent))
+	denom := new(big.Int).Mul(big.NewInt(rateDenom), big.NewInt(daysInYear))
+	return num.Quo(num, denom).Int64()
+}
@@ -0,0 +1,54 @@
+package interest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDayCount(t *testing.T) {
+	for _, s := range []string{"act/365", "act/360", "act/act"} {
+		if _, err := ParseDayCount(s); err != nil {
+			t.Errorf("ParseDayCount(%q) failed: %v", s, err)
+		}
+	}
+	if _, err := ParseDayCount("30/360"); err == nil {
+		t.Error("Expected unsupported convention to fail")
+	}
+}
+
+func TestDaysInYear(t *testing.T) {
+	leap := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	common := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	century := time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		dc   DayCount
+		day  time.Time
+		want int64
+	}{
+		{Actual365, leap, 365},
+		{Actual360, leap, 360},
+		{ActualActual, leap, 366},
+		{ActualActual, common, 365},
+		{ActualActual, century, 365},
+	}
+	for _, tt := range tests {
+		if got := tt.dc.DaysInYear(tt.day); got != tt.want {
+			t.Errorf("%s.DaysInYear(%s) = %d, want %d", tt.dc, tt.day.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
+
+func TestDailyMicros(t *testing.T) {
+	// 1000.00 at 3.65% for one day under act/365 is exactly 10 cents.
+	if got := DailyMicros(100000, 365, 10000, 365); got != 10*MicrosPerCent {
+		t.Errorf("Expected 10 cents, got %d micros", got)
+	}
+	// 1.00 at 5% under act/360: 100*0.05/360 = 0.013888... cents, rounded down.
+	if got := DailyMicros(100, 5, 100, 360); got != 13888 {
+		t.Errorf("Expected 13888 micros, got %d", got)
+	}
+	if got := DailyMicros(-500, 5, 100, 365); got != 0 {
+		t.Errorf("Expected no interest on a negative balance, got %d", got)
+	}
+}
@@ -0,0 +1,110 @@
+// Package recurrence computes the run dates of repeating payments. Dates are
+// whole UTC days; time of day is ignored.
+package recurrence
+
+import (
+	"errors"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Weekly  Frequency = "weekly"
+	Monthly Frequency = "monthly"
+)
+
+// MonthEnd decides where a monthly run goes when the month is shorter than
+// its day of month, e.g. the 31st in April.
+type MonthEnd string
+
+const (
+	// LastDay runs on the last day of the short month.
+	LastDay MonthEnd = "last_day"
+	// NextMonth runs on the first day of the following month.
+	NextMonth MonthEnd = "next_month"
+)
+
+var (
+	ErrInvalidFrequency  = errors.New("recurrence: frequency must be weekly or monthly")
+	ErrInvalidDayOfMonth = errors.New("recurrence: day of month must be between 1 and 31")
+	ErrInvalidMonthEnd   = errors.New("recurrence: month end rule must be last_day or next_month")
+)
+
+// Rule describes a schedule. Weekly rules repeat every 7 days from Start;
+// monthly rules run on DayOfMonth of every month from Start on.
+type Rule struct {
+	Frequency  Frequency
+	DayOfMonth int
+	MonthEnd   MonthEnd
+	Start      time.Time
+}
+
+func (r Rule) Validate() error {
+	switch r.Frequency {
+	case Weekly:
+		return nil
+	case Monthly:
+		if r.DayOfMonth < 1 || r.DayOfMonth > 31 {
+			return ErrInvalidDayOfMonth
+		}
+		if r.MonthEnd != LastDay && r.MonthEnd != NextMonth {
+			return ErrInvalidMonthEnd
+		}
+		return nil
+	default:
+		return ErrInvalidFrequency
+	}
+}
+
+// Next returns the first run date strictly after after, and never before
+// Start. Every run is derived from Start rather than from the previous run, so
+// a short month does not shift the ones that follow.
+func (r Rule) Next(after time.Time) time.Time {
+	start := Day(r.Start)
+	after = Day(after)
+	if after.Before(start) {
+		after = start.AddDate(0, 0, -1)
+	}
+
+	if r.Frequency == Weekly {
+		if after.Before(start) {
+			return start
+		}
+		weeks := int(after.Sub(start).Hours()/24)/7 + 1
+		return start.AddDate(0, 0, 7*weeks)
+	}
+
+	// Start at the month before after: with NextMonth its run can spill into
+	// after's month.
+	year, month := after.Year(), after.Month()-1
+	for {
+		run := r.monthlyRun(year, month)
+		if run.After(after) && !run.Before(start) {
+			return run
+		}
+		month++
+	}
+}
+
+func (r Rule) monthlyRun(year int, month time.Month) time.Time {
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if r.DayOfMonth <= last {
+		return time.Date(year, month, r.DayOfMonth, 0, 0, 0, 0, time.UTC)
+	}
+	if r.MonthEnd == NextMonth {
+		return time.Date(year, month+1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(year, month, last, 0, 0, 0, 0, time.UTC)
+}
+
+// First returns the first run date on or after Start.
+func (r Rule) First() time.Time {
+	return r.Next(Day(r.Start).AddDate(0, 0, -1))
+}
+
+// Day truncates t to midnight UTC.
+func Day(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
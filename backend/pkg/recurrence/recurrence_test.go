@@ -0,0 +1,88 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+)
+
+func date(s string) time.Time {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		rule Rule
+		want error
+	}{
+		{Rule{Frequency: Weekly}, nil},
+		{Rule{Frequency: Monthly, DayOfMonth: 31, MonthEnd: LastDay}, nil},
+		{Rule{Frequency: Monthly, DayOfMonth: 0, MonthEnd: LastDay}, ErrInvalidDayOfMonth},
+		{Rule{Frequency: Monthly, DayOfMonth: 32, MonthEnd: LastDay}, ErrInvalidDayOfMonth},
+		{Rule{Frequency: Monthly, DayOfMonth: 5}, ErrInvalidMonthEnd},
+		{Rule{Frequency: "daily"}, ErrInvalidFrequency},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.Validate(); got != tt.want {
+			t.Errorf("Validate(%+v) = %v, want %v", tt.rule, got, tt.want)
+		}
+	}
+}
+
+func TestNext_Weekly(t *testing.T) {
+	rule := Rule{Frequency: Weekly, Start: date("2025-01-06")}
+	tests := []struct{ after, want string }{
+		{"2024-12-01", "2025-01-06"},
+		{"2025-01-05", "2025-01-06"},
+		{"2025-01-06", "2025-01-13"},
+		{"2025-01-12", "2025-01-13"},
+		{"2025-03-01", "2025-03-03"},
+	}
+	for _, tt := range tests {
+		if got := rule.Next(date(tt.after)); !got.Equal(date(tt.want)) {
+			t.Errorf("Next(%s) = %s, want %s", tt.after, got.Format("2006-01-02"), tt.want)
+		}
+	}
+}
+
+func TestNext_MonthlyMonthEnd(t *testing.T) {
+	lastDay := Rule{Frequency: Monthly, DayOfMonth: 31, MonthEnd: LastDay, Start: date("2025-01-15")}
+	nextMonth := Rule{Frequency: Monthly, DayOfMonth: 31, MonthEnd: NextMonth, Start: date("2025-01-15")}
+	tests := []struct {
+		rule        Rule
+		after, want string
+	}{
+		{lastDay, "2025-01-14", "2025-01-31"},
+		{lastDay, "2025-01-31", "2025-02-28"},
+		{lastDay, "2025-02-28", "2025-03-31"},
+		{lastDay, "2028-02-01", "2028-02-29"},
+		{nextMonth, "2025-01-31", "2025-03-01"},
+		{nextMonth, "2025-03-01", "2025-03-31"},
+		{nextMonth, "2025-03-31", "2025-05-01"},
+		{nextMonth, "2025-12-31", "2026-01-31"},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.Next(date(tt.after)); !got.Equal(date(tt.want)) {
+			t.Errorf("%s: Next(%s) = %s, want %s", tt.rule.MonthEnd, tt.after, got.Format("2006-01-02"), tt.want)
+		}
+	}
+}
+
+func TestFirst(t *testing.T) {
+	tests := []struct {
+		rule Rule
+		want string
+	}{
+		{Rule{Frequency: Monthly, DayOfMonth: 1, MonthEnd: LastDay, Start: date("2025-01-15")}, "2025-02-01"},
+		{Rule{Frequency: Monthly, DayOfMonth: 15, MonthEnd: LastDay, Start: date("2025-01-15")}, "2025-01-15"},
+		{Rule{Frequency: Weekly, Start: date("2025-01-15")}, "2025-01-15"},
+	}
+	for _, tt := range tests {
+		if got := tt.rule.First(); !got.Equal(date(tt.want)) {
+			t.Errorf("First(%+v) = %s, want %s", tt.rule, got.Format("2006-01-02"), tt.want)
+		}
+	}
+}